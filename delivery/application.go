@@ -0,0 +1,150 @@
+package delivery
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"talentapp/middleware"
+	"talentapp/model"
+	"talentapp/usecase"
+	"talentapp/utils"
+)
+
+type applicationDelivery struct {
+	applicationUsecase usecase.ApplicationUsecase
+}
+
+func NewApplicationDelivery(applicationUsecase usecase.ApplicationUsecase) *applicationDelivery {
+	return &applicationDelivery{
+		applicationUsecase: applicationUsecase,
+	}
+}
+
+func (h *applicationDelivery) Router(app *fiber.App, auth *middleware.Auth) {
+	app.Get("/recruitment/:id/board", auth.Authenticate, auth.Require(model.PermApplicationRead), h.GetApplicationBoard)
+	app.Post("/recruitment/:id/application", auth.Authenticate, auth.Require(model.PermApplicationWrite), h.PostApplication)
+
+	application := app.Group("/application", auth.Authenticate)
+	application.Get("/:id", auth.Require(model.PermApplicationRead), auth.RequireApplicationAssignment, h.GetApplicationByID)
+	application.Put("/:id/stage", auth.Require(model.PermApplicationWrite), h.PutApplicationStage)
+	application.Get("/:id/events", auth.Require(model.PermApplicationRead), auth.RequireApplicationAssignment, h.GetApplicationEvents)
+}
+
+func (h *applicationDelivery) GetApplicationByID(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.applicationUsecase.GetApplicationByID(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *applicationDelivery) GetApplicationBoard(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.applicationUsecase.GetApplicationBoard(ctx.Context(), id, middleware.AssignedTo(ctx))
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *applicationDelivery) PostApplication(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.ApplicationCreateRequest
+		err     error
+		ok      bool
+		result  = new(model.Application)
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	payload.ChangedBy = middleware.CurrentUser(ctx).Name
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err = h.applicationUsecase.CreateNewApplication(ctx.Context(), id, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *applicationDelivery) PutApplicationStage(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.ApplicationMoveRequest
+		err     error
+		ok      bool
+		result  = new(model.Application)
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	payload.ChangedBy = middleware.CurrentUser(ctx).Name
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err = h.applicationUsecase.MoveApplication(ctx.Context(), id, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *applicationDelivery) GetApplicationEvents(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.applicationUsecase.GetApplicationEvents(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
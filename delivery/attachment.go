@@ -0,0 +1,192 @@
+package delivery
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"talentapp/middleware"
+	"talentapp/model"
+	"talentapp/usecase"
+	"talentapp/utils"
+)
+
+type attachmentDelivery struct {
+	attachmentUsecase usecase.AttachmentUsecase
+}
+
+func NewAttachmentDelivery(attachmentUsecase usecase.AttachmentUsecase) *attachmentDelivery {
+	return &attachmentDelivery{
+		attachmentUsecase: attachmentUsecase,
+	}
+}
+
+// Router serves the attachments below their candidate, so they need the
+// same permissions and assignment as the candidate itself.
+func (h *attachmentDelivery) Router(app *fiber.App, auth *middleware.Auth) {
+	attachment := app.Group("/candidate/:id/attachment", auth.Authenticate)
+	attachment.Get("", auth.Require(model.PermCandidateRead), auth.RequireCandidateAssignment, h.GetAttachments)
+	attachment.Post("", auth.Require(model.PermCandidateWrite), h.PostAttachment)
+	attachment.Get("/:attachment_id", auth.Require(model.PermCandidateRead), auth.RequireCandidateAssignment, h.GetAttachmentByID)
+	attachment.Get("/:attachment_id/download", auth.Require(model.PermCandidateRead), auth.RequireCandidateAssignment, h.DownloadAttachment)
+	attachment.Delete("/:attachment_id", auth.Require(model.PermCandidateWrite), h.DeleteAttachment)
+	attachment.Get("/:attachment_id/resume", auth.Require(model.PermCandidateRead), auth.RequireCandidateAssignment, h.ParseAttachment)
+	app.Post("/candidate/resume", auth.Authenticate, auth.Require(model.PermCandidateWrite), h.ParseResume)
+}
+
+func (h *attachmentDelivery) GetAttachments(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.attachmentUsecase.GetAttachments(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *attachmentDelivery) GetAttachmentByID(ctx *fiber.Ctx) error {
+	var (
+		id           = ctx.Params("id")
+		attachmentID = ctx.Params("attachment_id")
+	)
+
+	result, err := h.attachmentUsecase.GetAttachmentByID(ctx.Context(), id, attachmentID)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+// PostAttachment uploads the multipart field "file" together with the form
+// fields kind and, optionally, application_id.
+func (h *attachmentDelivery) PostAttachment(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.AttachmentCreateRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	payload.UploadedBy = middleware.CurrentUser(ctx).Name
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+	defer file.Close()
+
+	result, err := h.attachmentUsecase.UploadAttachment(ctx.Context(), id, header.Filename, file, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *attachmentDelivery) DownloadAttachment(ctx *fiber.Ctx) error {
+	var (
+		id           = ctx.Params("id")
+		attachmentID = ctx.Params("attachment_id")
+	)
+
+	result, content, err := h.attachmentUsecase.OpenAttachment(ctx.Context(), id, attachmentID)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return utils.SendFile(ctx, result.Filename, result.ContentType, result.Size, content)
+}
+
+func (h *attachmentDelivery) DeleteAttachment(ctx *fiber.Ctx) error {
+	var (
+		id           = ctx.Params("id")
+		attachmentID = ctx.Params("attachment_id")
+	)
+
+	err := h.attachmentUsecase.DeleteAttachment(ctx.Context(), id, attachmentID)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+// ParseResume reads the resume in the multipart field "file" without
+// storing it, to prefill a new candidate.
+func (h *attachmentDelivery) ParseResume(ctx *fiber.Ctx) error {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+	defer file.Close()
+
+	result, err := h.attachmentUsecase.ParseResume(ctx.Context(), header.Filename, file)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *attachmentDelivery) ParseAttachment(ctx *fiber.Ctx) error {
+	var (
+		id           = ctx.Params("id")
+		attachmentID = ctx.Params("attachment_id")
+	)
+
+	result, err := h.attachmentUsecase.ParseAttachment(ctx.Context(), id, attachmentID)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
@@ -0,0 +1,68 @@
+package delivery
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"talentapp/middleware"
+	"talentapp/model"
+	"talentapp/usecase"
+	"talentapp/utils"
+)
+
+type auditDelivery struct {
+	auditUsecase usecase.AuditUsecase
+}
+
+func NewAuditDelivery(auditUsecase usecase.AuditUsecase) *auditDelivery {
+	return &auditDelivery{
+		auditUsecase: auditUsecase,
+	}
+}
+
+func (h *auditDelivery) Router(app *fiber.App, auth *middleware.Auth) {
+	audit := app.Group("/audit", auth.Authenticate, auth.Require(model.PermAuditRead))
+	audit.Get("", h.GetAuditLogs)
+	audit.Get("/:entity_type/:entity_id", h.GetAuditLogs)
+}
+
+// GetAuditLogs lists audit entries, newest first. The entity can be given
+// in the path or with the entity_type and entity_id query params.
+func (h *auditDelivery) GetAuditLogs(ctx *fiber.Ctx) error {
+	var (
+		filter model.AuditLogListRequest
+		err    error
+		ok     bool
+	)
+
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	if entityType := ctx.Params("entity_type"); entityType != "" {
+		filter.EntityType = entityType
+		filter.EntityID = ctx.Params("entity_id")
+	}
+
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, meta, err := h.auditUsecase.GetAuditLogs(ctx.Context(), filter)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	utils.SetPageLinks(ctx, meta)
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+		"meta":    meta,
+	})
+}
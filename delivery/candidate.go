@@ -1,8 +1,10 @@
 package delivery
 
 import (
+	"errors"
 	"github.com/gofiber/fiber/v2"
 	"net/http"
+	"talentapp/middleware"
 	"talentapp/model"
 	"talentapp/usecase"
 	"talentapp/utils"
@@ -18,11 +20,22 @@ func NewCandidateDelivery(candidateUsecase usecase.CandidateUsecase) *candidateD
 	}
 }
 
-func (h *candidateDelivery) Router(app *fiber.App) {
-	candidate := app.Group("/candidate")
-	candidate.Get("/:id", h.GetCandidateByID)
-	candidate.Post("", h.PostCandidate)
-	candidate.Get("/", h.GetCandidates)
+func (h *candidateDelivery) Router(app *fiber.App, auth *middleware.Auth) {
+	candidate := app.Group("/candidate", auth.Authenticate)
+	candidate.Get("/:id", auth.Require(model.PermCandidateRead), auth.RequireCandidateAssignment, h.GetCandidateByID)
+	candidate.Get("/:id/history", auth.Require(model.PermCandidateRead), auth.Require(model.PermScoreRead), auth.RequireCandidateAssignment, h.GetCandidateHistory)
+	candidate.Get("/:id/duplicate", auth.Require(model.PermCandidateRead), auth.RequireEveryCandidate, h.GetDuplicates)
+	candidate.Post("/:id/merge", auth.Require(model.PermCandidateWrite), h.MergeCandidates)
+	candidate.Get("/:id/consent", auth.Require(model.PermCandidateRead), auth.RequireCandidateAssignment, h.GetConsents)
+	candidate.Post("/:id/consent", auth.Require(model.PermCandidateWrite), h.PostConsent)
+	candidate.Post("/:id/consent/:consent_id/withdraw", auth.Require(model.PermCandidateWrite), h.WithdrawConsent)
+	candidate.Get("/:id/contact", auth.Require(model.PermCandidateRead), auth.RequireCandidateAssignment, h.GetContact)
+	candidate.Post("", auth.Require(model.PermCandidateWrite), h.PostCandidate)
+	candidate.Post("/import", auth.Require(model.PermCandidateWrite), h.ImportCandidates)
+	candidate.Get("/", auth.Require(model.PermCandidateRead), h.GetCandidates)
+	candidate.Put("/:id", auth.Require(model.PermCandidateWrite), h.PutCandidate)
+	candidate.Patch("/:id", auth.Require(model.PermCandidateWrite), h.PatchCandidate)
+	candidate.Delete("/:id", auth.Require(model.PermCandidateWrite), h.DeleteCandidate)
 }
 
 func (h *candidateDelivery) GetCandidateByID(ctx *fiber.Ctx) error {
@@ -32,12 +45,76 @@ func (h *candidateDelivery) GetCandidateByID(ctx *fiber.Ctx) error {
 
 	result, err := h.candidateUsecase.GetCandidateByID(ctx.Context(), id)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *candidateDelivery) GetCandidateHistory(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.candidateUsecase.GetCandidateHistory(ctx.Context(), id, middleware.AssignedTo(ctx))
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *candidateDelivery) GetDuplicates(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.candidateUsecase.GetDuplicates(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+// MergeCandidates merges the candidate named by duplicate_id into the one of
+// the path and answers with the merged candidate.
+func (h *candidateDelivery) MergeCandidates(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.CandidateMergeRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
 			"error":   err.Error(),
 		})
 	}
 
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err := h.candidateUsecase.MergeCandidates(ctx.Context(), id, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "success",
 		"data":    result,
@@ -45,17 +122,39 @@ func (h *candidateDelivery) GetCandidateByID(ctx *fiber.Ctx) error {
 }
 
 func (h *candidateDelivery) GetCandidates(ctx *fiber.Ctx) error {
-	result, err := h.candidateUsecase.GetCandidates(ctx.Context())
-	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
+	var (
+		filter model.CandidateListRequest
+		err    error
+		ok     bool
+	)
+
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
 			"error":   err.Error(),
 		})
 	}
 
+	filter.AssignedTo = middleware.AssignedTo(ctx)
+
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, meta, err := h.candidateUsecase.GetCandidates(ctx.Context(), filter)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	utils.SetPageLinks(ctx, meta)
+
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "success",
 		"data":    result,
+		"meta":    meta,
 	})
 }
 
@@ -82,14 +181,164 @@ func (h *candidateDelivery) PostCandidate(ctx *fiber.Ctx) error {
 	}
 
 	result, err = h.candidateUsecase.CreateNewCandidate(ctx.Context(), payload)
+	if duplicate := new(usecase.DuplicateError); errors.As(err, &duplicate) {
+		return ctx.Status(http.StatusConflict).JSON(fiber.Map{
+			"message":    "conflict",
+			"error":      err.Error(),
+			"duplicates": duplicate.Duplicates,
+		})
+	}
+
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *candidateDelivery) PutCandidate(ctx *fiber.Ctx) error {
+	var (
+		payload model.CandidateCreateRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
 			"error":   err.Error(),
 		})
 	}
 
-	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	return h.updateCandidate(ctx, payload.UpdateRequest())
+}
+
+func (h *candidateDelivery) PatchCandidate(ctx *fiber.Ctx) error {
+	var (
+		payload model.CandidateUpdateRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	return h.updateCandidate(ctx, payload)
+}
+
+func (h *candidateDelivery) updateCandidate(ctx *fiber.Ctx, payload model.CandidateUpdateRequest) error {
+	var id = ctx.Params("id")
+
+	result, err := h.candidateUsecase.UpdateCandidate(ctx.Context(), id, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *candidateDelivery) DeleteCandidate(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	err := h.candidateUsecase.DeleteCandidate(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+// ImportCandidates reads a CSV or XLSX file from the multipart field "file".
+// In the default mode an invalid row rejects the whole file with 422 and the
+// per-row report.
+func (h *candidateDelivery) ImportCandidates(ctx *fiber.Ctx) error {
+	var (
+		payload model.CandidateImportRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.QueryParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	rows, err := utils.ReadSpreadsheetFile(file)
+	if err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	result, err := h.candidateUsecase.ImportCandidates(ctx.Context(), rows, payload)
+	if errors.Is(err, usecase.ErrInvalidImport) {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	if !result.DryRun && result.Mode == model.ImportModeAll && result.Invalid > 0 {
+		return ctx.Status(http.StatusUnprocessableEntity).JSON(fiber.Map{
+			"message": "unprocessable entity",
+			"data":    result,
+		})
+	}
+
+	status := http.StatusOK
+	if result.Imported > 0 {
+		status = http.StatusCreated
+	}
+
+	return ctx.Status(status).JSON(fiber.Map{
 		"message": "success",
 		"data":    result,
 	})
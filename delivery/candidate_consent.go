@@ -0,0 +1,97 @@
+package delivery
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"talentapp/middleware"
+	"talentapp/model"
+	"talentapp/utils"
+)
+
+func (h *candidateDelivery) GetConsents(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.candidateUsecase.GetConsents(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+// PostConsent records a consent given by the candidate, on behalf of the
+// current user.
+func (h *candidateDelivery) PostConsent(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.CandidateConsentCreateRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	payload.RecordedBy = middleware.CurrentUser(ctx).Name
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err := h.candidateUsecase.CreateConsent(ctx.Context(), id, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *candidateDelivery) WithdrawConsent(ctx *fiber.Ctx) error {
+	var (
+		id        = ctx.Params("id")
+		consentID = ctx.Params("consent_id")
+	)
+
+	result, err := h.candidateUsecase.WithdrawConsent(ctx.Context(), id, consentID)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+// GetContact answers with the email and phone to contact the candidate at,
+// or 409 when the candidate has not consented to be contacted.
+func (h *candidateDelivery) GetContact(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.candidateUsecase.GetContact(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
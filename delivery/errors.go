@@ -0,0 +1,32 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"talentapp/repository"
+	"talentapp/usecase"
+)
+
+// errorResponse answers a failed usecase call with the status of the kind of
+// err: 404 for usecase.ErrNotFound, 409 for usecase.ErrConflict,
+// usecase.ErrClosed and the constraint violations of the repositories, 422
+// for usecase.ErrValidation and 500 otherwise.
+func errorResponse(ctx *fiber.Ctx, err error) error {
+	status, message := http.StatusInternalServerError, "something bad happened"
+
+	switch {
+	case errors.Is(err, usecase.ErrNotFound):
+		status, message = http.StatusNotFound, "not found"
+	case errors.Is(err, usecase.ErrConflict), errors.Is(err, usecase.ErrClosed),
+		errors.Is(err, repository.ErrDuplicate), errors.Is(err, repository.ErrReferenced):
+		status, message = http.StatusConflict, "conflict"
+	case errors.Is(err, usecase.ErrValidation):
+		status, message = http.StatusUnprocessableEntity, "unprocessable entity"
+	}
+
+	return ctx.Status(status).JSON(fiber.Map{
+		"message": message,
+		"error":   err.Error(),
+	})
+}
@@ -3,6 +3,7 @@ package delivery
 import (
 	"github.com/gofiber/fiber/v2"
 	"net/http"
+	"talentapp/middleware"
 	"talentapp/model"
 	"talentapp/usecase"
 	"talentapp/utils"
@@ -18,11 +19,17 @@ func NewJobDelivery(jobUsecase usecase.JobUsecase) *jobDelivery {
 	}
 }
 
-func (h *jobDelivery) Router(app *fiber.App) {
-	job := app.Group("/job")
-	job.Get("/:id", h.GetJobByID)
-	job.Post("", h.PostJob)
-	job.Get("", h.GetJobs)
+func (h *jobDelivery) Router(app *fiber.App, auth *middleware.Auth) {
+	job := app.Group("/job", auth.Authenticate)
+	job.Get("/:id", auth.Require(model.PermJobRead), h.GetJobByID)
+	job.Post("", auth.Require(model.PermJobWrite), h.PostJob)
+	job.Get("", auth.Require(model.PermJobRead), h.GetJobs)
+	job.Put("/:id", auth.Require(model.PermJobWrite), h.PutJob)
+	job.Patch("/:id", auth.Require(model.PermJobWrite), h.PatchJob)
+	job.Delete("/:id", auth.Require(model.PermJobWrite), h.DeleteJob)
+	job.Get("/:id/scoring-profile", auth.Require(model.PermJobRead), h.GetScoringProfile)
+	job.Get("/:id/scoring-profile/history", auth.Require(model.PermJobRead), h.GetScoringProfiles)
+	job.Put("/:id/scoring-profile", auth.Require(model.PermJobWrite), h.PutScoringProfile)
 }
 
 func (h *jobDelivery) GetJobByID(ctx *fiber.Ctx) error {
@@ -32,10 +39,7 @@ func (h *jobDelivery) GetJobByID(ctx *fiber.Ctx) error {
 
 	result, err := h.jobUsecase.GetJobByID(ctx.Context(), id)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
-			"error":   err.Error(),
-		})
+		return errorResponse(ctx, err)
 	}
 
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{
@@ -45,17 +49,37 @@ func (h *jobDelivery) GetJobByID(ctx *fiber.Ctx) error {
 }
 
 func (h *jobDelivery) GetJobs(ctx *fiber.Ctx) error {
-	result, err := h.jobUsecase.GetJobs(ctx.Context())
-	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
+	var (
+		filter model.JobListRequest
+		err    error
+		ok     bool
+	)
+
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
 			"error":   err.Error(),
 		})
 	}
 
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, meta, err := h.jobUsecase.GetJobs(ctx.Context(), filter)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	utils.SetPageLinks(ctx, meta)
+
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "success",
 		"data":    result,
+		"meta":    meta,
 	})
 }
 
@@ -83,12 +107,146 @@ func (h *jobDelivery) PostJob(ctx *fiber.Ctx) error {
 
 	result, err = h.jobUsecase.CreateNewJob(ctx.Context(), payload)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *jobDelivery) PutJob(ctx *fiber.Ctx) error {
+	var (
+		payload model.JobCreateRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	return h.updateJob(ctx, payload.UpdateRequest())
+}
+
+func (h *jobDelivery) PatchJob(ctx *fiber.Ctx) error {
+	var (
+		payload model.JobUpdateRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	return h.updateJob(ctx, payload)
+}
+
+func (h *jobDelivery) updateJob(ctx *fiber.Ctx, payload model.JobUpdateRequest) error {
+	var id = ctx.Params("id")
+
+	result, err := h.jobUsecase.UpdateJob(ctx.Context(), id, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *jobDelivery) DeleteJob(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	err := h.jobUsecase.DeleteJob(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *jobDelivery) GetScoringProfile(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	result, err := h.jobUsecase.GetScoringProfile(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *jobDelivery) GetScoringProfiles(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	result, err := h.jobUsecase.GetScoringProfiles(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *jobDelivery) PutScoringProfile(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.ScoringProfileRequest
+		err     error
+		ok      bool
+		result  = new(model.ScoringProfile)
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
 			"error":   err.Error(),
 		})
 	}
 
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err = h.jobUsecase.UpdateScoringProfile(ctx.Context(), id, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
 	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
 		"message": "success",
 		"data":    result,
@@ -0,0 +1,68 @@
+package delivery
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"net/http"
+	"talentapp/export"
+	"talentapp/middleware"
+	"talentapp/model"
+	"talentapp/usecase"
+	"talentapp/utils"
+)
+
+type privacyDelivery struct {
+	privacyUsecase usecase.PrivacyUsecase
+}
+
+func NewPrivacyDelivery(privacyUsecase usecase.PrivacyUsecase) *privacyDelivery {
+	return &privacyDelivery{
+		privacyUsecase: privacyUsecase,
+	}
+}
+
+// Router serves the export and erasure of a candidate. The export holds the
+// audit entries of the candidate, so it also needs to read the audit log.
+func (h *privacyDelivery) Router(app *fiber.App, auth *middleware.Auth) {
+	candidate := app.Group("/candidate/:id", auth.Authenticate)
+	candidate.Get("/export", auth.Require(model.PermCandidateWrite), auth.Require(model.PermAuditRead), h.ExportCandidate)
+	candidate.Post("/erase", auth.Require(model.PermCandidateWrite), h.EraseCandidate)
+}
+
+// ExportCandidate downloads everything stored about a candidate as a zip
+// archive.
+func (h *privacyDelivery) ExportCandidate(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.privacyUsecase.ExportCandidate(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	// the attachments are read after the handler returned, outside of the
+	// request
+	return utils.SendAttachment(ctx, export.CandidateFilename(result), export.ContentType("zip"), func(w io.Writer) error {
+		return export.WriteCandidate(w, result, func(attachment model.Attachment) (io.ReadCloser, error) {
+			return h.privacyUsecase.OpenAttachment(context.Background(), attachment)
+		})
+	})
+}
+
+func (h *privacyDelivery) EraseCandidate(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.privacyUsecase.EraseCandidate(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
@@ -3,7 +3,11 @@ package delivery
 import (
 	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"io"
 	"net/http"
+	"strings"
+	"talentapp/export"
+	"talentapp/middleware"
 	"talentapp/model"
 	"talentapp/usecase"
 	"talentapp/utils"
@@ -19,15 +23,24 @@ func NewRecruitmentDelivery(recruitmentUsecase usecase.RecruitmentUsecase) *recr
 	}
 }
 
-func (h *recruitmentDelivery) Router(app *fiber.App) {
-	recruitment := app.Group("/recruitment")
-	recruitment.Get("/:id", h.GetRecruitmentByID)
-	recruitment.Post("", h.PostRecruitment)
-	recruitment.Get("/", h.GetRecruitments)
-	recruitment.Put("/:id", h.PutRecruitmentStatus)
-	recruitment.Get("/:id/score", h.GetRecruitmentScore)
-	recruitment.Post("/:id/score", h.PostCandidateScore)
-	recruitment.Get("/:id/candidate/:candidate_id/score", h.GetCandidateScoreByID)
+func (h *recruitmentDelivery) Router(app *fiber.App, auth *middleware.Auth) {
+	recruitment := app.Group("/recruitment", auth.Authenticate)
+	recruitment.Get("/:id", auth.Require(model.PermRecruitmentRead), h.GetRecruitmentByID)
+	recruitment.Post("", auth.Require(model.PermRecruitmentWrite), h.PostRecruitment)
+	recruitment.Get("/", auth.Require(model.PermRecruitmentRead), h.GetRecruitments)
+	recruitment.Put("/:id", auth.Require(model.PermRecruitmentWrite), h.PutRecruitmentStatus)
+	recruitment.Get("/:id/score", auth.Require(model.PermScoreRead), h.GetRecruitmentScore)
+	recruitment.Post("/:id/score", auth.Require(model.PermScoreWrite), h.PostCandidateScore)
+	recruitment.Get("/:id/score/export/:format", auth.Require(model.PermScoreRead), h.ExportRecruitmentScore)
+	recruitment.Get("/:id/suggestion", auth.Require(model.PermRecruitmentRead), auth.Require(model.PermCandidateRead), auth.RequireEveryCandidate, h.GetSuggestedCandidates)
+	recruitment.Get("/:id/candidate/:candidate_id/score", auth.Require(model.PermScoreRead), auth.RequireAssignment, h.GetCandidateScoreByID)
+	recruitment.Patch("/:id", auth.Require(model.PermRecruitmentWrite), h.PatchRecruitment)
+	recruitment.Delete("/:id", auth.Require(model.PermRecruitmentWrite), h.DeleteRecruitment)
+	recruitment.Put("/:id/candidate/:candidate_id/score", auth.Require(model.PermScoreWrite), h.PutCandidateScore)
+	recruitment.Delete("/:id/candidate/:candidate_id/score", auth.Require(model.PermScoreWrite), h.DeleteCandidateScore)
+	recruitment.Get("/:id/candidate/:candidate_id/scorecard", auth.Require(model.PermScoreRead), auth.RequireAssignment, h.GetScorecards)
+	recruitment.Post("/:id/candidate/:candidate_id/scorecard", auth.Require(model.PermScorecardWrite), auth.RequireAssignment, h.PostScorecard)
+	recruitment.Delete("/:id/candidate/:candidate_id/scorecard/:scorecard_id", auth.Require(model.PermScoreWrite), h.DeleteScorecard)
 }
 
 func (h *recruitmentDelivery) GetRecruitmentByID(ctx *fiber.Ctx) error {
@@ -37,10 +50,7 @@ func (h *recruitmentDelivery) GetRecruitmentByID(ctx *fiber.Ctx) error {
 
 	result, err := h.recruitmentUsecase.GetRecruitmentByID(ctx.Context(), id)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
-			"error":   err.Error(),
-		})
+		return errorResponse(ctx, err)
 	}
 
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{
@@ -50,17 +60,37 @@ func (h *recruitmentDelivery) GetRecruitmentByID(ctx *fiber.Ctx) error {
 }
 
 func (h *recruitmentDelivery) GetRecruitments(ctx *fiber.Ctx) error {
-	result, err := h.recruitmentUsecase.GetRecruitments(ctx.Context())
-	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
+	var (
+		filter model.RecruitmentListRequest
+		err    error
+		ok     bool
+	)
+
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
 			"error":   err.Error(),
 		})
 	}
 
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, meta, err := h.recruitmentUsecase.GetRecruitments(ctx.Context(), filter)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	utils.SetPageLinks(ctx, meta)
+
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "success",
 		"data":    result,
+		"meta":    meta,
 	})
 }
 
@@ -89,10 +119,7 @@ func (h *recruitmentDelivery) PostRecruitment(ctx *fiber.Ctx) error {
 
 	result, err = h.recruitmentUsecase.CreateNewRecruitment(ctx.Context(), payload)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
-			"error":   err.Error(),
-		})
+		return errorResponse(ctx, err)
 	}
 
 	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
@@ -126,10 +153,7 @@ func (h *recruitmentDelivery) PutRecruitmentStatus(ctx *fiber.Ctx) error {
 
 	result, err = h.recruitmentUsecase.UpdateRecruitmentStatus(ctx.Context(), id, payload)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
-			"error":   err.Error(),
-		})
+		return errorResponse(ctx, err)
 	}
 
 	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
@@ -139,19 +163,118 @@ func (h *recruitmentDelivery) PutRecruitmentStatus(ctx *fiber.Ctx) error {
 }
 
 func (h *recruitmentDelivery) GetRecruitmentScore(ctx *fiber.Ctx) error {
-	var id = ctx.Params("id")
+	var (
+		id     = ctx.Params("id")
+		filter model.CandidateScoreListRequest
+		err    error
+		ok     bool
+	)
 
-	result, err := h.recruitmentUsecase.GetRecruitmentScores(ctx.Context(), id)
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	filter.AssignedTo = middleware.AssignedTo(ctx)
+
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, meta, err := h.recruitmentUsecase.GetRecruitmentScores(ctx.Context(), id, filter)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
+		return errorResponse(ctx, err)
+	}
+
+	utils.SetPageLinks(ctx, meta)
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+		"meta":    meta,
+	})
+}
+
+// GetSuggestedCandidates lists the candidates of the talent pool that best
+// match the requirements of the job, with the criteria each one meets or
+// misses.
+func (h *recruitmentDelivery) GetSuggestedCandidates(ctx *fiber.Ctx) error {
+	var (
+		id     = ctx.Params("id")
+		filter model.CandidateMatchListRequest
+		err    error
+		ok     bool
+	)
+
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
 			"error":   err.Error(),
 		})
 	}
 
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, meta, err := h.recruitmentUsecase.GetSuggestedCandidates(ctx.Context(), id, filter)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	utils.SetPageLinks(ctx, meta)
+
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "success",
 		"data":    result,
+		"meta":    meta,
+	})
+}
+
+// ExportRecruitmentScore downloads the whole ranking as csv, xlsx or pdf. It
+// accepts the same filters and sort as GetRecruitmentScore.
+func (h *recruitmentDelivery) ExportRecruitmentScore(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.CandidateScoreExportRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.QueryParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	payload.AssignedTo = middleware.AssignedTo(ctx)
+
+	// the format is used by the stream writer after the handler returned, so
+	// it must not point into the reused request buffer
+	payload.Format = strings.Clone(ctx.Params("format"))
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err := h.recruitmentUsecase.GetCandidateScoreReport(ctx.Context(), id, payload.CandidateScoreListRequest)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return utils.SendAttachment(ctx, export.ScoreFilename(result, payload.Format), export.ContentType(payload.Format), func(w io.Writer) error {
+		return export.WriteScores(w, payload.Format, result)
 	})
 }
 
@@ -180,10 +303,7 @@ func (h *recruitmentDelivery) PostCandidateScore(ctx *fiber.Ctx) error {
 
 	result, err = h.recruitmentUsecase.CreateNewCandidateScore(ctx.Context(), id, payload)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
-			"error":   err.Error(),
-		})
+		return errorResponse(ctx, err)
 	}
 
 	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
@@ -200,14 +320,182 @@ func (h *recruitmentDelivery) GetCandidateScoreByID(ctx *fiber.Ctx) error {
 
 	result, err := h.recruitmentUsecase.GetCandidateScoreByID(ctx.Context(), id, candidateID)
 	if err != nil {
-		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
-			"message": "something bad happened",
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *recruitmentDelivery) PatchRecruitment(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.RecruitmentUpdateRequest
+		err     error
+		ok      bool
+		result  = new(model.Recruitment)
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err = h.recruitmentUsecase.UpdateRecruitment(ctx.Context(), id, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *recruitmentDelivery) DeleteRecruitment(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	err := h.recruitmentUsecase.DeleteRecruitment(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *recruitmentDelivery) PutCandidateScore(ctx *fiber.Ctx) error {
+	var (
+		id          = ctx.Params("id")
+		candidateID = ctx.Params("candidate_id")
+		payload     model.CandidateScoreUpdateRequest
+		err         error
+		ok          bool
+		result      = new(model.CandidateScore)
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
 			"error":   err.Error(),
 		})
 	}
 
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err = h.recruitmentUsecase.UpdateCandidateScore(ctx.Context(), id, candidateID, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
 	return ctx.Status(http.StatusOK).JSON(fiber.Map{
 		"message": "success",
 		"data":    result,
 	})
 }
+
+func (h *recruitmentDelivery) DeleteCandidateScore(ctx *fiber.Ctx) error {
+	var (
+		id          = ctx.Params("id")
+		candidateID = ctx.Params("candidate_id")
+	)
+
+	err := h.recruitmentUsecase.DeleteCandidateScore(ctx.Context(), id, candidateID)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *recruitmentDelivery) GetScorecards(ctx *fiber.Ctx) error {
+	var (
+		id          = ctx.Params("id")
+		candidateID = ctx.Params("candidate_id")
+	)
+
+	result, err := h.recruitmentUsecase.GetScorecards(ctx.Context(), id, candidateID)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *recruitmentDelivery) PostScorecard(ctx *fiber.Ctx) error {
+	var (
+		id          = ctx.Params("id")
+		candidateID = ctx.Params("candidate_id")
+		payload     model.ScorecardCreateRequest
+		err         error
+		ok          bool
+		result      = new(model.Scorecard)
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	if user := middleware.CurrentUser(ctx); user.Role == model.RoleInterviewer {
+		payload.Interviewer = user.Name
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err = h.recruitmentUsecase.SubmitScorecard(ctx.Context(), id, candidateID, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *recruitmentDelivery) DeleteScorecard(ctx *fiber.Ctx) error {
+	var (
+		id          = ctx.Params("id")
+		candidateID = ctx.Params("candidate_id")
+		scorecardID = ctx.Params("scorecard_id")
+	)
+
+	err := h.recruitmentUsecase.DeleteScorecard(ctx.Context(), id, candidateID, scorecardID)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
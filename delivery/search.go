@@ -0,0 +1,61 @@
+package delivery
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"talentapp/middleware"
+	"talentapp/model"
+	"talentapp/usecase"
+	"talentapp/utils"
+)
+
+type searchDelivery struct {
+	searchUsecase usecase.SearchUsecase
+}
+
+func NewSearchDelivery(searchUsecase usecase.SearchUsecase) *searchDelivery {
+	return &searchDelivery{
+		searchUsecase: searchUsecase,
+	}
+}
+
+func (h *searchDelivery) Router(app *fiber.App, auth *middleware.Auth) {
+	search := app.Group("/search", auth.Authenticate, auth.Require(model.PermCandidateRead), auth.Require(model.PermJobRead))
+	search.Get("", h.Search)
+}
+
+// Search returns the candidates and jobs most relevant to the q param. The
+// candidate and job lists take the same param to page through all matches.
+func (h *searchDelivery) Search(ctx *fiber.Ctx) error {
+	var (
+		payload model.SearchRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.QueryParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	payload.AssignedTo = middleware.AssignedTo(ctx)
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err := h.searchUsecase.Search(ctx.Context(), payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
@@ -0,0 +1,235 @@
+package delivery
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"talentapp/middleware"
+	"talentapp/model"
+	"talentapp/usecase"
+	"talentapp/utils"
+)
+
+type userDelivery struct {
+	userUsecase usecase.UserUsecase
+}
+
+func NewUserDelivery(userUsecase usecase.UserUsecase) *userDelivery {
+	return &userDelivery{
+		userUsecase: userUsecase,
+	}
+}
+
+func (h *userDelivery) Router(app *fiber.App, auth *middleware.Auth) {
+	app.Post("/auth/token", h.PostToken)
+	app.Get("/auth/token", auth.Authenticate, h.GetTokens)
+	app.Delete("/auth/token/:id", auth.Authenticate, h.DeleteToken)
+	app.Get("/auth/me", auth.Authenticate, h.GetMe)
+
+	user := app.Group("/user", auth.Authenticate, auth.Require(model.PermUserManage))
+	user.Get("", h.GetUsers)
+	user.Post("", h.PostUser)
+	user.Delete("/:id", h.DeleteUser)
+
+	app.Get("/recruitment/:id/interviewer", auth.Authenticate, auth.Require(model.PermRecruitmentRead), h.GetInterviewerAssignments)
+	app.Post("/recruitment/:id/interviewer", auth.Authenticate, auth.Require(model.PermRecruitmentWrite), h.PostInterviewerAssignment)
+	app.Delete("/recruitment/:id/interviewer/:assignment_id", auth.Authenticate, auth.Require(model.PermRecruitmentWrite), h.DeleteInterviewerAssignment)
+}
+
+// PostToken exchanges an email and password for an API key. The key is
+// only returned here, it cannot be read back later.
+func (h *userDelivery) PostToken(ctx *fiber.Ctx) error {
+	var (
+		payload model.TokenCreateRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	token, result, err := h.userUsecase.CreateAPIToken(ctx.Context(), payload)
+	if errors.Is(err, usecase.ErrInvalidCredentials) {
+		return ctx.Status(http.StatusUnauthorized).JSON(fiber.Map{
+			"message": "unauthorized",
+			"error":   err.Error(),
+		})
+	}
+
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "success",
+		"data": fiber.Map{
+			"token":      token,
+			"token_info": result,
+		},
+	})
+}
+
+func (h *userDelivery) GetTokens(ctx *fiber.Ctx) error {
+	result, err := h.userUsecase.GetAPITokens(ctx.Context(), middleware.CurrentUser(ctx).ID)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *userDelivery) DeleteToken(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	err := h.userUsecase.DeleteAPIToken(ctx.Context(), middleware.CurrentUser(ctx).ID, id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *userDelivery) GetMe(ctx *fiber.Ctx) error {
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    middleware.CurrentUser(ctx),
+	})
+}
+
+func (h *userDelivery) GetUsers(ctx *fiber.Ctx) error {
+	result, err := h.userUsecase.GetUsers(ctx.Context())
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *userDelivery) PostUser(ctx *fiber.Ctx) error {
+	var (
+		payload model.UserCreateRequest
+		err     error
+		ok      bool
+		result  = new(model.User)
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err = h.userUsecase.CreateUser(ctx.Context(), payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *userDelivery) DeleteUser(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	err := h.userUsecase.DeleteUser(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
+
+func (h *userDelivery) GetInterviewerAssignments(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	result, err := h.userUsecase.GetInterviewerAssignments(ctx.Context(), id)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *userDelivery) PostInterviewerAssignment(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.InterviewerAssignmentCreateRequest
+		err     error
+		ok      bool
+		result  = new(model.InterviewerAssignment)
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"message": "bad request",
+			"error":   utils.CustomValidator(err),
+		})
+	}
+
+	result, err = h.userUsecase.AssignInterviewer(ctx.Context(), id, payload)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
+		"message": "success",
+		"data":    result,
+	})
+}
+
+func (h *userDelivery) DeleteInterviewerAssignment(ctx *fiber.Ctx) error {
+	var (
+		id           = ctx.Params("id")
+		assignmentID = ctx.Params("assignment_id")
+	)
+
+	err := h.userUsecase.UnassignInterviewer(ctx.Context(), id, assignmentID)
+	if err != nil {
+		return errorResponse(ctx, err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(fiber.Map{
+		"message": "success",
+	})
+}
@@ -0,0 +1,177 @@
+// Package migrationtest checks the migrations of a database driver. Every
+// driver runs the same checks, against a database they migrate down first.
+package migrationtest
+
+import (
+	"database/sql"
+	"github.com/golang-migrate/migrate/v4"
+	"reflect"
+	"testing"
+)
+
+// Versions are the migrations of a driver that move data, by name.
+type Versions struct {
+	SeedDefaultScoringProfile uint
+	AddAuditCandidate         uint
+}
+
+// Run migrates db up, all the way down and up again, and checks the data
+// the migrations of versions write. newMigrate returns the migrations of db.
+func Run(t *testing.T, db *sql.DB, versions Versions, newMigrate func() (*migrate.Migrate, error)) {
+	t.Run("DownAndUp", func(t *testing.T) { testDownAndUp(t, newMigrate) })
+	t.Run("SeedsDefaultScoringProfile", func(t *testing.T) { testSeed(t, db, versions.SeedDefaultScoringProfile, newMigrate) })
+	t.Run("FillsAuditCandidate", func(t *testing.T) { testAuditCandidate(t, db, versions.AddAuditCandidate, newMigrate) })
+}
+
+func testDownAndUp(t *testing.T, newMigrate func() (*migrate.Migrate, error)) {
+	m := empty(t, newMigrate)
+
+	for _, step := range []struct {
+		name string
+		run  func() error
+	}{
+		{"up", m.Up},
+		// migrating an up to date database does nothing
+		{"up to date", m.Up},
+		{"down", m.Down},
+		{"up again", m.Up},
+	} {
+		if err := step.run(); err != nil && err != migrate.ErrNoChange {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+	}
+}
+
+func testSeed(t *testing.T, db *sql.DB, seedVersion uint, newMigrate func() (*migrate.Migrate, error)) {
+	m := empty(t, newMigrate)
+
+	if err := m.Migrate(seedVersion - 1); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, statement := range []string{
+		"INSERT INTO job (id, position) VALUES ('scored', 'Developer'), ('profiled', 'Designer')",
+		"INSERT INTO scoring_profile (id, job_id, version, attitude_weight, relocation_weight, skill_weight, experience_weight, experience_bands, grade_values)" +
+			" VALUES ('own', 'profiled', 1, 0.25, 0.25, 0.25, 0.25, '[]', '{}')",
+		"INSERT INTO recruitment (id, job_id) VALUES ('recruitment', 'scored')",
+		"INSERT INTO candidate (id, name) VALUES ('candidate', 'Ann')",
+		"INSERT INTO candidate_score (id, candidate_id, recruitment_id) VALUES ('score', 'candidate', 'recruitment')",
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+
+	if err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	var profiles int
+	if err := db.QueryRow("SELECT COUNT(*) FROM scoring_profile WHERE job_id = 'profiled'").Scan(&profiles); err != nil {
+		t.Fatal(err)
+	}
+
+	if profiles != 1 {
+		t.Errorf("got %d profiles for a job that had one, want 1", profiles)
+	}
+
+	var profileID string
+	if err := db.QueryRow("SELECT scoring_profile_id FROM candidate_score WHERE id = 'score'").Scan(&profileID); err != nil {
+		t.Fatal(err)
+	}
+
+	if profileID != "default-scored" {
+		t.Errorf("got the score on profile %q, want default-scored", profileID)
+	}
+}
+
+// testAuditCandidate checks that the entries logged before audit_log had a
+// candidate_id get the candidate they are about.
+func testAuditCandidate(t *testing.T, db *sql.DB, version uint, newMigrate func() (*migrate.Migrate, error)) {
+	m := empty(t, newMigrate)
+
+	if err := m.Migrate(version - 1); err != nil {
+		t.Fatal(err)
+	}
+
+	SQL := "INSERT INTO audit_log (id, actor, entity_type, entity_id, action, before_data, after_data) VALUES " +
+		"('created', 'system', 'candidate', 'ann', 'create', NULL, '{\"id\":\"ann\"}'), " +
+		"('merged', 'system', 'candidate', 'bob', 'merge', '{\"id\":\"bob\"}', '{\"id\":\"ann\"}'), " +
+		"('scored', 'system', 'candidate_score', 'score', 'create', NULL, '{\"id\":\"score\",\"candidate_id\":\"bob\"}'), " +
+		"('deleted', 'system', 'application', 'application', 'delete', '{\"id\":\"application\",\"candidate_id\":\"ann\"}', NULL), " +
+		"('posted', 'system', 'job', 'job', 'create', NULL, '{\"id\":\"job\"}')"
+	if _, err := db.Exec(SQL); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.Query("SELECT id, candidate_id FROM audit_log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	got := map[string]string{}
+	for rows.Next() {
+		var (
+			id          string
+			candidateID sql.NullString
+		)
+
+		if err = rows.Scan(&id, &candidateID); err != nil {
+			t.Fatal(err)
+		}
+
+		got[id] = candidateID.String
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"created": "ann", "merged": "ann", "scored": "bob", "deleted": "ann", "posted": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got candidates %v, want %v", got, want)
+	}
+}
+
+// empty returns the migrations of a database without any table, migrating
+// it down when it was migrated before, such as by the repository contract
+// tests.
+func empty(t *testing.T, newMigrate func() (*migrate.Migrate, error)) *migrate.Migrate {
+	t.Helper()
+
+	m, err := newMigrate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, dirty, err := m.Version()
+	switch {
+	case err == migrate.ErrNilVersion:
+		return m
+	case err != nil:
+		t.Fatal(err)
+	case dirty:
+		// only a drop undoes a migration a failed run left half applied
+		if err = m.Drop(); err != nil {
+			t.Fatal(err)
+		}
+
+		// migrate needs a new instance once the tables are dropped
+		if m, err = newMigrate(); err != nil {
+			t.Fatal(err)
+		}
+
+		return m
+	}
+
+	if err = m.Down(); err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
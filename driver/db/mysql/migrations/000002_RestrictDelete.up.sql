@@ -0,0 +1,13 @@
+START TRANSACTION;
+
+ALTER TABLE `recruitment`
+    DROP FOREIGN KEY `fk_rec_1`,
+    ADD CONSTRAINT `fk_rec_1` FOREIGN KEY (`job_id`) REFERENCES `job` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION;
+
+ALTER TABLE `candidate_score`
+    DROP FOREIGN KEY `fk_cs_1`,
+    DROP FOREIGN KEY `fk_cs_2`,
+    ADD CONSTRAINT `fk_cs_1` FOREIGN KEY (`candidate_id`) REFERENCES `candidate` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION,
+    ADD CONSTRAINT `fk_cs_2` FOREIGN KEY (`recruitment_id`) REFERENCES `recruitment` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,9 @@
+START TRANSACTION;
+
+ALTER TABLE `candidate_score`
+    DROP FOREIGN KEY `fk_cs_3`,
+    DROP COLUMN `scoring_profile_id`;
+
+DROP TABLE IF EXISTS `scoring_profile`;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,23 @@
+START TRANSACTION;
+
+CREATE TABLE `scoring_profile` (
+    `id` varchar(50) NOT NULL,
+    `job_id` varchar(50) NOT NULL,
+    `version` int NOT NULL,
+    `attitude_weight` DECIMAL(4,3) NOT NULL,
+    `relocation_weight` DECIMAL(4,3) NOT NULL,
+    `skill_weight` DECIMAL(4,3) NOT NULL,
+    `experience_weight` DECIMAL(4,3) NOT NULL,
+    `experience_bands` text NOT NULL,
+    `grade_values` text NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_sp_job_version` (`job_id`, `version`),
+    CONSTRAINT `fk_sp_1` FOREIGN KEY (`job_id`) REFERENCES `job` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `candidate_score`
+    ADD COLUMN `scoring_profile_id` varchar(50) DEFAULT NULL,
+    ADD CONSTRAINT `fk_cs_3` FOREIGN KEY (`scoring_profile_id`) REFERENCES `scoring_profile` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,13 @@
+START TRANSACTION;
+
+ALTER TABLE `candidate_score`
+    DROP COLUMN `interviewer_count`,
+    DROP COLUMN `attitude_spread`,
+    DROP COLUMN `skill_spread`;
+
+ALTER TABLE `recruitment`
+    DROP COLUMN `score_aggregation`;
+
+DROP TABLE IF EXISTS `scorecard`;
+
+COMMIT;
\ No newline at end of file
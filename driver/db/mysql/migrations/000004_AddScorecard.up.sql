@@ -0,0 +1,28 @@
+START TRANSACTION;
+
+CREATE TABLE `scorecard` (
+    `id` varchar(50) NOT NULL,
+    `recruitment_id` varchar(50) NOT NULL,
+    `candidate_id` varchar(50) NOT NULL,
+    `interviewer` varchar(100) NOT NULL,
+    `attitude_grade` varchar(5) NOT NULL,
+    `skill_grade` varchar(5) NOT NULL,
+    `attitude_score` DECIMAL(5,2) DEFAULT '0.00',
+    `skill_score` DECIMAL(5,2) DEFAULT '0.00',
+    `comment` text,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_sc_interviewer` (`recruitment_id`, `candidate_id`, `interviewer`),
+    CONSTRAINT `fk_sc_1` FOREIGN KEY (`recruitment_id`) REFERENCES `recruitment` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION,
+    CONSTRAINT `fk_sc_2` FOREIGN KEY (`candidate_id`) REFERENCES `candidate` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE `recruitment`
+    ADD COLUMN `score_aggregation` ENUM('mean', 'median', 'trimmed_mean') DEFAULT 'mean';
+
+ALTER TABLE `candidate_score`
+    ADD COLUMN `interviewer_count` int DEFAULT '0',
+    ADD COLUMN `attitude_spread` DECIMAL(5,2) DEFAULT '0.00',
+    ADD COLUMN `skill_spread` DECIMAL(5,2) DEFAULT '0.00';
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,7 @@
+START TRANSACTION;
+
+DROP TABLE IF EXISTS `application_stage_event`;
+
+DROP TABLE IF EXISTS `application`;
+
+COMMIT;
\ No newline at end of file
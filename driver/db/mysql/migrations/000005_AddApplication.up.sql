@@ -0,0 +1,29 @@
+START TRANSACTION;
+
+CREATE TABLE `application` (
+    `id` varchar(50) NOT NULL,
+    `recruitment_id` varchar(50) NOT NULL,
+    `candidate_id` varchar(50) NOT NULL,
+    `stage` ENUM('applied', 'screening', 'interview', 'offer', 'hired', 'rejected', 'withdrawn') DEFAULT 'applied',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_app_candidate` (`recruitment_id`, `candidate_id`),
+    CONSTRAINT `fk_app_1` FOREIGN KEY (`recruitment_id`) REFERENCES `recruitment` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION,
+    CONSTRAINT `fk_app_2` FOREIGN KEY (`candidate_id`) REFERENCES `candidate` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE `application_stage_event` (
+    `id` varchar(50) NOT NULL,
+    `application_id` varchar(50) NOT NULL,
+    `from_stage` varchar(20) DEFAULT NULL,
+    `to_stage` varchar(20) NOT NULL,
+    `changed_by` varchar(100) NOT NULL,
+    `note` text,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_ase_application` (`application_id`, `created_at`),
+    CONSTRAINT `fk_ase_1` FOREIGN KEY (`application_id`) REFERENCES `application` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,10 @@
+START TRANSACTION;
+
+DROP TABLE IF EXISTS `scheduler_lease`;
+
+ALTER TABLE `recruitment`
+    DROP KEY `idx_recruitment_deadline`,
+    DROP COLUMN `closed_at`,
+    DROP COLUMN `closed_reason`;
+
+COMMIT;
\ No newline at end of file
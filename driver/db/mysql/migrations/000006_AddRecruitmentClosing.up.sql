@@ -0,0 +1,15 @@
+START TRANSACTION;
+
+ALTER TABLE `recruitment`
+    ADD COLUMN `closed_at` TIMESTAMP NULL DEFAULT NULL,
+    ADD COLUMN `closed_reason` varchar(255) DEFAULT NULL,
+    ADD KEY `idx_recruitment_deadline` (`status`, `deadline`);
+
+CREATE TABLE `scheduler_lease` (
+    `name` varchar(100) NOT NULL,
+    `holder` varchar(100) NOT NULL,
+    `expires_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+COMMIT;
\ No newline at end of file
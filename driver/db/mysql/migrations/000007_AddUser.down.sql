@@ -0,0 +1,7 @@
+START TRANSACTION;
+
+DROP TABLE IF EXISTS `interviewer_assignment`;
+DROP TABLE IF EXISTS `user_token`;
+DROP TABLE IF EXISTS `app_user`;
+
+COMMIT;
\ No newline at end of file
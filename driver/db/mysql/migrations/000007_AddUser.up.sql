@@ -0,0 +1,41 @@
+START TRANSACTION;
+
+CREATE TABLE `app_user` (
+    `id` varchar(50) NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `email` varchar(255) NOT NULL,
+    `password_hash` varchar(100) NOT NULL,
+    `role` ENUM('admin', 'recruiter', 'hiring_manager', 'interviewer') NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_user_email` (`email`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE `user_token` (
+    `id` varchar(50) NOT NULL,
+    `user_id` varchar(50) NOT NULL,
+    `kind` ENUM('session', 'api') NOT NULL,
+    `name` varchar(100) NOT NULL,
+    `token_hash` char(64) NOT NULL,
+    `expires_at` TIMESTAMP NULL DEFAULT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `last_used_at` TIMESTAMP NULL DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_token_hash` (`token_hash`),
+    CONSTRAINT `fk_ut_1` FOREIGN KEY (`user_id`) REFERENCES `app_user` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE `interviewer_assignment` (
+    `id` varchar(50) NOT NULL,
+    `recruitment_id` varchar(50) NOT NULL,
+    `candidate_id` varchar(50) NOT NULL,
+    `user_id` varchar(50) NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uq_ia_interviewer` (`recruitment_id`, `candidate_id`, `user_id`),
+    CONSTRAINT `fk_ia_1` FOREIGN KEY (`recruitment_id`) REFERENCES `recruitment` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION,
+    CONSTRAINT `fk_ia_2` FOREIGN KEY (`candidate_id`) REFERENCES `candidate` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION,
+    CONSTRAINT `fk_ia_3` FOREIGN KEY (`user_id`) REFERENCES `app_user` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+COMMIT;
\ No newline at end of file
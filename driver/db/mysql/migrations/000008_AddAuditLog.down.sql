@@ -0,0 +1,5 @@
+START TRANSACTION;
+
+DROP TABLE IF EXISTS `audit_log`;
+
+COMMIT;
\ No newline at end of file
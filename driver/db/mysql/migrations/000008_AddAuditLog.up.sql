@@ -0,0 +1,18 @@
+START TRANSACTION;
+
+CREATE TABLE `audit_log` (
+    `id` varchar(50) NOT NULL,
+    `actor_id` varchar(50) DEFAULT NULL,
+    `actor` varchar(100) NOT NULL,
+    `entity_type` varchar(50) NOT NULL,
+    `entity_id` varchar(50) NOT NULL,
+    `action` varchar(20) NOT NULL,
+    `before_data` text,
+    `after_data` text,
+    `created_at` TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (`id`),
+    KEY `idx_audit_entity` (`entity_type`, `entity_id`, `created_at`),
+    KEY `idx_audit_actor` (`actor`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+COMMIT;
\ No newline at end of file
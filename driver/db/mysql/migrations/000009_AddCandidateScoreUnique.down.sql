@@ -0,0 +1,6 @@
+START TRANSACTION;
+
+ALTER TABLE `candidate_score`
+    DROP INDEX `uq_cs_candidate`;
+
+COMMIT;
\ No newline at end of file
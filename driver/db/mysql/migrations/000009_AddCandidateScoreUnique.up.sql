@@ -0,0 +1,11 @@
+START TRANSACTION;
+
+-- the index is not created while a candidate has several scores in a
+-- recruitment: which one to keep is up to the recruiters, so the migration
+-- fails on the first duplicate. The duplicates are listed with
+--   SELECT `recruitment_id`, `candidate_id`, COUNT(*) FROM `candidate_score`
+--   GROUP BY `recruitment_id`, `candidate_id` HAVING COUNT(*) > 1;
+ALTER TABLE `candidate_score`
+    ADD UNIQUE KEY `uq_cs_candidate` (`recruitment_id`, `candidate_id`);
+
+COMMIT;
\ No newline at end of file
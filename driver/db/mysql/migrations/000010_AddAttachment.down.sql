@@ -0,0 +1,5 @@
+START TRANSACTION;
+
+DROP TABLE IF EXISTS `attachment`;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,22 @@
+START TRANSACTION;
+
+CREATE TABLE `attachment` (
+    `id` varchar(50) NOT NULL,
+    `candidate_id` varchar(50) NOT NULL,
+    `application_id` varchar(50) DEFAULT NULL,
+    `kind` ENUM('cv', 'portfolio', 'certificate') NOT NULL,
+    `filename` varchar(255) NOT NULL,
+    `content_type` varchar(100) NOT NULL,
+    `size` BIGINT NOT NULL,
+    `sha256` char(64) NOT NULL,
+    `storage_key` varchar(255) NOT NULL,
+    `uploaded_by` varchar(100) NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_att_candidate` (`candidate_id`, `created_at`),
+    KEY `idx_att_sha256` (`sha256`),
+    CONSTRAINT `fk_att_1` FOREIGN KEY (`candidate_id`) REFERENCES `candidate` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION,
+    CONSTRAINT `fk_att_2` FOREIGN KEY (`application_id`) REFERENCES `application` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,5 @@
+START TRANSACTION;
+
+DROP TABLE IF EXISTS `candidate_skills`;
+
+COMMIT;
\ No newline at end of file
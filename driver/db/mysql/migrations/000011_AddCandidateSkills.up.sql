@@ -0,0 +1,11 @@
+START TRANSACTION;
+
+CREATE TABLE `candidate_skills` (
+    `candidate_id` varchar(50) NOT NULL,
+    `skill` varchar(100) NOT NULL,
+    PRIMARY KEY (`candidate_id`, `skill`),
+    KEY `idx_cskill_skill` (`skill`),
+    CONSTRAINT `fk_cskill_1` FOREIGN KEY (`candidate_id`) REFERENCES `candidate` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+START TRANSACTION;
+
+ALTER TABLE `candidate` DROP INDEX `ft_candidate`;
+ALTER TABLE `job` DROP INDEX `ft_job`;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+START TRANSACTION;
+
+ALTER TABLE `candidate` ADD FULLTEXT KEY `ft_candidate` (`name`, `address`);
+ALTER TABLE `job` ADD FULLTEXT KEY `ft_job` (`position`, `department`, `job_description`, `criteria`);
+
+COMMIT;
\ No newline at end of file
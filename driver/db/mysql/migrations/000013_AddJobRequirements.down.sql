@@ -0,0 +1,5 @@
+START TRANSACTION;
+
+ALTER TABLE `job` DROP COLUMN `requirements`;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,5 @@
+START TRANSACTION;
+
+ALTER TABLE `job` ADD COLUMN `requirements` text DEFAULT NULL;
+
+COMMIT;
\ No newline at end of file
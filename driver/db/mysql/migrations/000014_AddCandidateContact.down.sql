@@ -0,0 +1,10 @@
+START TRANSACTION;
+
+DROP TABLE IF EXISTS `candidate_consent`;
+
+ALTER TABLE `candidate` DROP KEY `idx_candidate_phone`;
+ALTER TABLE `candidate` DROP KEY `idx_candidate_email`;
+ALTER TABLE `candidate` DROP COLUMN `phone`;
+ALTER TABLE `candidate` DROP COLUMN `email`;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,23 @@
+START TRANSACTION;
+
+ALTER TABLE `candidate` ADD COLUMN `email` varchar(254) DEFAULT NULL;
+ALTER TABLE `candidate` ADD COLUMN `phone` varchar(30) DEFAULT NULL;
+ALTER TABLE `candidate` ADD KEY `idx_candidate_email` (`email`);
+ALTER TABLE `candidate` ADD KEY `idx_candidate_phone` (`phone`);
+
+CREATE TABLE `candidate_consent` (
+    `id` varchar(50) NOT NULL,
+    `candidate_id` varchar(50) NOT NULL,
+    `purpose` ENUM('contact', 'retention') NOT NULL,
+    `source` varchar(20) NOT NULL,
+    `granted_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `expires_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `withdrawn_at` TIMESTAMP NULL DEFAULT NULL,
+    `recorded_by` varchar(100) NOT NULL,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    KEY `idx_consent_candidate` (`candidate_id`, `purpose`, `expires_at`),
+    CONSTRAINT `fk_consent_1` FOREIGN KEY (`candidate_id`) REFERENCES `candidate` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+COMMIT;
\ No newline at end of file
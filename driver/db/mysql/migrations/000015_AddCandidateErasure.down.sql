@@ -0,0 +1,5 @@
+START TRANSACTION;
+
+ALTER TABLE `candidate` DROP COLUMN `erased_at`;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,5 @@
+START TRANSACTION;
+
+ALTER TABLE `candidate` ADD COLUMN `erased_at` TIMESTAMP NULL DEFAULT NULL;
+
+COMMIT;
\ No newline at end of file
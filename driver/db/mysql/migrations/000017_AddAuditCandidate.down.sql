@@ -0,0 +1,6 @@
+START TRANSACTION;
+
+ALTER TABLE `audit_log` DROP KEY `idx_audit_candidate`;
+ALTER TABLE `audit_log` DROP COLUMN `candidate_id`;
+
+COMMIT;
\ No newline at end of file
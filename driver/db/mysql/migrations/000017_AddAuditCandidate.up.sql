@@ -0,0 +1,14 @@
+START TRANSACTION;
+
+ALTER TABLE `audit_log` ADD COLUMN `candidate_id` varchar(50) DEFAULT NULL;
+ALTER TABLE `audit_log` ADD KEY `idx_audit_candidate` (`candidate_id`, `created_at`);
+
+-- entries are kept under the candidate they are about: the candidate itself,
+-- the one a merge went into, or the candidate the record belongs to
+UPDATE `audit_log` SET `candidate_id` = CASE
+    WHEN `entity_type` <> 'candidate' THEN coalesce(JSON_UNQUOTE(JSON_EXTRACT(`after_data`, '$.candidate_id')), JSON_UNQUOTE(JSON_EXTRACT(`before_data`, '$.candidate_id')))
+    WHEN `action` = 'merge' THEN JSON_UNQUOTE(JSON_EXTRACT(`after_data`, '$.id'))
+    ELSE `entity_id`
+END;
+
+COMMIT;
\ No newline at end of file
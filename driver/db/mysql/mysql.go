@@ -3,12 +3,13 @@ package mysql
 import (
 	"database/sql"
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/golang-migrate/migrate/v4"
+	mysqlMigrate "github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"os"
 	"time"
 )
 
-var DB *sql.DB
-
 func ConnectDB() *sql.DB {
 	user := os.Getenv("MYSQL_USER")
 	pass := os.Getenv("MYSQL_PASS")
@@ -16,7 +17,7 @@ func ConnectDB() *sql.DB {
 	host := os.Getenv("MYSQL_HOST")
 	port := os.Getenv("MYSQL_PORT")
 
-	db, err := sql.Open("mysql", user+":"+pass+"@tcp("+host+":"+port+")/"+dbname+"?parseTime=true&multiStatements=true")
+	db, err := sql.Open("mysql", user+":"+pass+"@tcp("+host+":"+port+")/"+dbname+"?parseTime=true&multiStatements=true&clientFoundRows=true")
 	if err != nil {
 		panic(err)
 	}
@@ -30,6 +31,30 @@ func ConnectDB() *sql.DB {
 		panic(err)
 	}
 
-	DB = db
 	return db
 }
+
+// Migrate applies the MySQL migrations found at source, a migrate source URL
+// such as "file://migrations".
+func Migrate(db *sql.DB, source string) error {
+	m, err := newMigrate(db, source)
+	if err != nil {
+		return err
+	}
+
+	if err = m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	return nil
+}
+
+// newMigrate returns the migrations of db, read from source.
+func newMigrate(db *sql.DB, source string) (*migrate.Migrate, error) {
+	dbInstance, err := mysqlMigrate.WithInstance(db, &mysqlMigrate.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithDatabaseInstance(source, "mysql", dbInstance)
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"database/sql"
+	"github.com/golang-migrate/migrate/v4"
+	"os"
+	"talentapp/driver/db/migrationtest"
+	"testing"
+)
+
+// TestMigrate runs against the database named by TEST_MYSQL_DSN, which
+// it migrates down, and is skipped when it is not set. The migrations are
+// read from the package directory the test runs in.
+func TestMigrate(t *testing.T) {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MYSQL_DSN is not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	migrationtest.Run(t, db, migrationtest.Versions{SeedDefaultScoringProfile: 16, AddAuditCandidate: 17}, func() (*migrate.Migrate, error) { return newMigrate(db, "file://migrations") })
+}
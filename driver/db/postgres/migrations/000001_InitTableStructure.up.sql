@@ -0,0 +1,172 @@
+CREATE TABLE candidate (
+    id varchar(50) NOT NULL,
+    name varchar(100) DEFAULT '',
+    address varchar(250) DEFAULT '',
+    experience SMALLINT DEFAULT 0,
+    willing_to_relocate varchar(3) DEFAULT 'no' CHECK (willing_to_relocate IN ('yes', 'no')),
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE job (
+    id varchar(50) NOT NULL,
+    position varchar(100) DEFAULT '',
+    department varchar(100) DEFAULT '',
+    requester varchar(100) DEFAULT '',
+    job_description text,
+    criteria text,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE recruitment (
+    id varchar(50) NOT NULL,
+    job_id varchar(50) DEFAULT NULL,
+    status varchar(5) DEFAULT 'open' CHECK (status IN ('open', 'close')),
+    deadline TIMESTAMPTZ NULL DEFAULT NULL,
+    score_aggregation varchar(12) DEFAULT 'mean' CHECK (score_aggregation IN ('mean', 'median', 'trimmed_mean')),
+    closed_at TIMESTAMPTZ NULL DEFAULT NULL,
+    closed_reason varchar(255) DEFAULT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_rec_1 FOREIGN KEY (job_id) REFERENCES job (id) ON DELETE RESTRICT ON UPDATE NO ACTION
+);
+
+CREATE INDEX idx_recruitment_deadline ON recruitment (status, deadline);
+
+CREATE TABLE scoring_profile (
+    id varchar(50) NOT NULL,
+    job_id varchar(50) NOT NULL,
+    version INTEGER NOT NULL,
+    attitude_weight NUMERIC(4,3) NOT NULL,
+    relocation_weight NUMERIC(4,3) NOT NULL,
+    skill_weight NUMERIC(4,3) NOT NULL,
+    experience_weight NUMERIC(4,3) NOT NULL,
+    experience_bands text NOT NULL,
+    grade_values text NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT uq_sp_job_version UNIQUE (job_id, version),
+    CONSTRAINT fk_sp_1 FOREIGN KEY (job_id) REFERENCES job (id) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE TABLE candidate_score (
+    id varchar(50) NOT NULL,
+    candidate_id varchar(50) DEFAULT NULL,
+    recruitment_id varchar(50) DEFAULT NULL,
+    willing_to_relocate_score NUMERIC(5,2) DEFAULT 0,
+    attitude_score NUMERIC(5,2) DEFAULT 0,
+    skill_score NUMERIC(5,2) DEFAULT 0,
+    experience_score NUMERIC(5,2) DEFAULT 0,
+    overall_score NUMERIC(5,2) DEFAULT 0,
+    scoring_profile_id varchar(50) DEFAULT NULL,
+    interviewer_count INTEGER DEFAULT 0,
+    attitude_spread NUMERIC(5,2) DEFAULT 0,
+    skill_spread NUMERIC(5,2) DEFAULT 0,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_cs_1 FOREIGN KEY (candidate_id) REFERENCES candidate (id) ON DELETE RESTRICT ON UPDATE NO ACTION,
+    CONSTRAINT fk_cs_2 FOREIGN KEY (recruitment_id) REFERENCES recruitment (id) ON DELETE RESTRICT ON UPDATE NO ACTION,
+    CONSTRAINT fk_cs_3 FOREIGN KEY (scoring_profile_id) REFERENCES scoring_profile (id) ON DELETE RESTRICT ON UPDATE NO ACTION
+);
+
+CREATE TABLE scorecard (
+    id varchar(50) NOT NULL,
+    recruitment_id varchar(50) NOT NULL,
+    candidate_id varchar(50) NOT NULL,
+    interviewer varchar(100) NOT NULL,
+    attitude_grade varchar(5) NOT NULL,
+    skill_grade varchar(5) NOT NULL,
+    attitude_score NUMERIC(5,2) DEFAULT 0,
+    skill_score NUMERIC(5,2) DEFAULT 0,
+    comment text,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT uq_sc_interviewer UNIQUE (recruitment_id, candidate_id, interviewer),
+    CONSTRAINT fk_sc_1 FOREIGN KEY (recruitment_id) REFERENCES recruitment (id) ON DELETE RESTRICT ON UPDATE NO ACTION,
+    CONSTRAINT fk_sc_2 FOREIGN KEY (candidate_id) REFERENCES candidate (id) ON DELETE RESTRICT ON UPDATE NO ACTION
+);
+
+CREATE TABLE application (
+    id varchar(50) NOT NULL,
+    recruitment_id varchar(50) NOT NULL,
+    candidate_id varchar(50) NOT NULL,
+    stage varchar(20) DEFAULT 'applied' CHECK (stage IN ('applied', 'screening', 'interview', 'offer', 'hired', 'rejected', 'withdrawn')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT uq_app_candidate UNIQUE (recruitment_id, candidate_id),
+    CONSTRAINT fk_app_1 FOREIGN KEY (recruitment_id) REFERENCES recruitment (id) ON DELETE RESTRICT ON UPDATE NO ACTION,
+    CONSTRAINT fk_app_2 FOREIGN KEY (candidate_id) REFERENCES candidate (id) ON DELETE RESTRICT ON UPDATE NO ACTION
+);
+
+CREATE TABLE application_stage_event (
+    id varchar(50) NOT NULL,
+    application_id varchar(50) NOT NULL,
+    from_stage varchar(20) DEFAULT NULL,
+    to_stage varchar(20) NOT NULL,
+    changed_by varchar(100) NOT NULL,
+    note text,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_ase_1 FOREIGN KEY (application_id) REFERENCES application (id) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE INDEX idx_ase_application ON application_stage_event (application_id, created_at);
+
+CREATE TABLE scheduler_lease (
+    name varchar(100) NOT NULL,
+    holder varchar(100) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (name)
+);
+
+CREATE TABLE app_user (
+    id varchar(50) NOT NULL,
+    name varchar(100) NOT NULL,
+    email varchar(255) NOT NULL,
+    password_hash varchar(100) NOT NULL,
+    role varchar(20) NOT NULL CHECK (role IN ('admin', 'recruiter', 'hiring_manager', 'interviewer')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT uq_user_email UNIQUE (email)
+);
+
+CREATE TABLE user_token (
+    id varchar(50) NOT NULL,
+    user_id varchar(50) NOT NULL,
+    kind varchar(10) NOT NULL CHECK (kind IN ('session', 'api')),
+    name varchar(100) NOT NULL,
+    token_hash char(64) NOT NULL,
+    expires_at TIMESTAMPTZ NULL DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMPTZ NULL DEFAULT NULL,
+    PRIMARY KEY (id),
+    CONSTRAINT uq_token_hash UNIQUE (token_hash),
+    CONSTRAINT fk_ut_1 FOREIGN KEY (user_id) REFERENCES app_user (id) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE TABLE interviewer_assignment (
+    id varchar(50) NOT NULL,
+    recruitment_id varchar(50) NOT NULL,
+    candidate_id varchar(50) NOT NULL,
+    user_id varchar(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    CONSTRAINT uq_ia_interviewer UNIQUE (recruitment_id, candidate_id, user_id),
+    CONSTRAINT fk_ia_1 FOREIGN KEY (recruitment_id) REFERENCES recruitment (id) ON DELETE CASCADE ON UPDATE NO ACTION,
+    CONSTRAINT fk_ia_2 FOREIGN KEY (candidate_id) REFERENCES candidate (id) ON DELETE CASCADE ON UPDATE NO ACTION,
+    CONSTRAINT fk_ia_3 FOREIGN KEY (user_id) REFERENCES app_user (id) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE TABLE audit_log (
+    id varchar(50) NOT NULL,
+    actor_id varchar(50) DEFAULT NULL,
+    actor varchar(100) NOT NULL,
+    entity_type varchar(50) NOT NULL,
+    entity_id varchar(50) NOT NULL,
+    action varchar(20) NOT NULL,
+    before_data text,
+    after_data text,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_audit_entity ON audit_log (entity_type, entity_id, created_at);
+CREATE INDEX idx_audit_actor ON audit_log (actor, created_at);
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- the index is not created while a candidate has several scores in a
+-- recruitment: which one to keep is up to the recruiters, so the migration
+-- fails and reports every duplicate.
+DO $$
+DECLARE
+    duplicates text;
+BEGIN
+    SELECT string_agg(format('candidate %s in recruitment %s (%s scores)', candidate_id, recruitment_id, total), ', ')
+    INTO duplicates
+    FROM (
+        SELECT recruitment_id, candidate_id, COUNT(*) AS total FROM candidate_score
+        GROUP BY recruitment_id, candidate_id HAVING COUNT(*) > 1
+    ) d;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION 'candidate_score has duplicate scores, keep one of each before migrating: %', duplicates;
+    END IF;
+END
+$$;
+
+CREATE UNIQUE INDEX uq_cs_candidate ON candidate_score (recruitment_id, candidate_id);
\ No newline at end of file
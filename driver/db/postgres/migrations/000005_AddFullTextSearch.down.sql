@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_job_search;
+
+DROP INDEX IF EXISTS idx_candidate_search;
\ No newline at end of file
@@ -0,0 +1,3 @@
+CREATE INDEX idx_candidate_search ON candidate USING GIN (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(address, '')));
+
+CREATE INDEX idx_job_search ON job USING GIN (to_tsvector('simple', coalesce(position, '') || ' ' || coalesce(department, '') || ' ' || coalesce(job_description, '') || ' ' || coalesce(criteria, '')));
\ No newline at end of file
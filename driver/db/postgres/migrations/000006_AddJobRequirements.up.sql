@@ -0,0 +1 @@
+ALTER TABLE job ADD COLUMN requirements text;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE candidate ADD COLUMN erased_at TIMESTAMPTZ DEFAULT NULL;
\ No newline at end of file
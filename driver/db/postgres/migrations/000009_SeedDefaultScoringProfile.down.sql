@@ -0,0 +1,3 @@
+UPDATE candidate_score SET scoring_profile_id = NULL WHERE scoring_profile_id LIKE 'default-%';
+
+DELETE FROM scoring_profile WHERE id LIKE 'default-%';
\ No newline at end of file
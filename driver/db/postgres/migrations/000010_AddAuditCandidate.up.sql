@@ -0,0 +1,11 @@
+ALTER TABLE audit_log ADD COLUMN candidate_id varchar(50) DEFAULT NULL;
+
+-- entries are kept under the candidate they are about: the candidate itself,
+-- the one a merge went into, or the candidate the record belongs to
+UPDATE audit_log SET candidate_id = CASE
+    WHEN entity_type <> 'candidate' THEN coalesce(after_data::json ->> 'candidate_id', before_data::json ->> 'candidate_id')
+    WHEN action = 'merge' THEN after_data::json ->> 'id'
+    ELSE entity_id
+END;
+
+CREATE INDEX idx_audit_candidate ON audit_log (candidate_id, created_at);
\ No newline at end of file
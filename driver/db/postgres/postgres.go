@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"database/sql"
+	"embed"
+	"github.com/golang-migrate/migrate/v4"
+	postgresMigrate "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/lib/pq"
+	"net/url"
+	"os"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// ConnectDB opens the PostgreSQL database configured by the POSTGRES_*
+// environment variables. The session time zone is UTC, as MySQL stores
+// times.
+func ConnectDB() *sql.DB {
+	sslmode := os.Getenv("POSTGRES_SSLMODE")
+	if sslmode == "" {
+		sslmode = "disable"
+	}
+
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(os.Getenv("POSTGRES_USER"), os.Getenv("POSTGRES_PASS")),
+		Host:   os.Getenv("POSTGRES_HOST") + ":" + os.Getenv("POSTGRES_PORT"),
+		Path:   os.Getenv("POSTGRES_DATABASE"),
+		RawQuery: url.Values{
+			"sslmode":  {sslmode},
+			"timezone": {"UTC"},
+		}.Encode(),
+	}
+
+	db, err := sql.Open("postgres", dsn.String())
+	if err != nil {
+		panic(err)
+	}
+
+	db.SetConnMaxLifetime(time.Minute * 3)
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(10)
+	err = db.Ping()
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
+
+// Migrate applies the embedded PostgreSQL migrations.
+func Migrate(db *sql.DB) error {
+	m, err := newMigrate(db)
+	if err != nil {
+		return err
+	}
+
+	if err = m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	return nil
+}
+
+// newMigrate returns the migrations of db, read from the embedded files.
+func newMigrate(db *sql.DB) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	dbInstance, err := postgresMigrate.WithInstance(db, &postgresMigrate.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithInstance("iofs", source, "postgres", dbInstance)
+}
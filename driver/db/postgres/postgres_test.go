@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"database/sql"
+	"github.com/golang-migrate/migrate/v4"
+	"os"
+	"talentapp/driver/db/migrationtest"
+	"testing"
+)
+
+// TestMigrate runs against the database named by TEST_POSTGRES_DSN, which
+// it migrates down, and is skipped when it is not set.
+func TestMigrate(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	migrationtest.Run(t, db, migrationtest.Versions{SeedDefaultScoringProfile: 9, AddAuditCandidate: 10}, func() (*migrate.Migrate, error) { return newMigrate(db) })
+}
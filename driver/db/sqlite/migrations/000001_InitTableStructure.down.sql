@@ -0,0 +1,25 @@
+DROP TABLE IF EXISTS `audit_log`;
+
+DROP TABLE IF EXISTS `interviewer_assignment`;
+
+DROP TABLE IF EXISTS `user_token`;
+
+DROP TABLE IF EXISTS `app_user`;
+
+DROP TABLE IF EXISTS `scheduler_lease`;
+
+DROP TABLE IF EXISTS `application_stage_event`;
+
+DROP TABLE IF EXISTS `application`;
+
+DROP TABLE IF EXISTS `scorecard`;
+
+DROP TABLE IF EXISTS `candidate_score`;
+
+DROP TABLE IF EXISTS `scoring_profile`;
+
+DROP TABLE IF EXISTS `recruitment`;
+
+DROP TABLE IF EXISTS `job`;
+
+DROP TABLE IF EXISTS `candidate`;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS `uq_cs_candidate`;
\ No newline at end of file
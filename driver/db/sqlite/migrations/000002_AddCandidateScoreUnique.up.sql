@@ -0,0 +1,6 @@
+-- the index is not created while a candidate has several scores in a
+-- recruitment: which one to keep is up to the recruiters, so the migration
+-- fails on the first duplicate. The duplicates are listed with
+--   SELECT `recruitment_id`, `candidate_id`, COUNT(*) FROM `candidate_score`
+--   GROUP BY `recruitment_id`, `candidate_id` HAVING COUNT(*) > 1;
+CREATE UNIQUE INDEX `uq_cs_candidate` ON `candidate_score` (`recruitment_id`, `candidate_id`);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `attachment`;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE `attachment` (
+    `id` varchar(50) NOT NULL,
+    `candidate_id` varchar(50) NOT NULL,
+    `application_id` varchar(50) DEFAULT NULL,
+    `kind` varchar(20) NOT NULL CHECK (`kind` IN ('cv', 'portfolio', 'certificate')),
+    `filename` varchar(255) NOT NULL,
+    `content_type` varchar(100) NOT NULL,
+    `size` BIGINT NOT NULL,
+    `sha256` char(64) NOT NULL,
+    `storage_key` varchar(255) NOT NULL,
+    `uploaded_by` varchar(100) NOT NULL,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_att_1` FOREIGN KEY (`candidate_id`) REFERENCES `candidate` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION,
+    CONSTRAINT `fk_att_2` FOREIGN KEY (`application_id`) REFERENCES `application` (`id`) ON DELETE RESTRICT ON UPDATE NO ACTION
+);
+
+CREATE INDEX `idx_att_candidate` ON `attachment` (`candidate_id`, `created_at`);
+CREATE INDEX `idx_att_sha256` ON `attachment` (`sha256`);
\ No newline at end of file
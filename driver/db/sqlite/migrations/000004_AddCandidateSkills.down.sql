@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `candidate_skills`;
\ No newline at end of file
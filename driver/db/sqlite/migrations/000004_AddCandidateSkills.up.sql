@@ -0,0 +1,8 @@
+CREATE TABLE `candidate_skills` (
+    `candidate_id` varchar(50) NOT NULL,
+    `skill` varchar(100) NOT NULL,
+    PRIMARY KEY (`candidate_id`, `skill`),
+    CONSTRAINT `fk_cskill_1` FOREIGN KEY (`candidate_id`) REFERENCES `candidate` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE INDEX `idx_cskill_skill` ON `candidate_skills` (`skill`);
\ No newline at end of file
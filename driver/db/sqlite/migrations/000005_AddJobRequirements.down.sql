@@ -0,0 +1 @@
+ALTER TABLE `job` DROP COLUMN `requirements`;
\ No newline at end of file
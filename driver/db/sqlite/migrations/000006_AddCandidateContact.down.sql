@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS `candidate_consent`;
+
+DROP INDEX IF EXISTS `idx_candidate_phone`;
+DROP INDEX IF EXISTS `idx_candidate_email`;
+
+ALTER TABLE `candidate` DROP COLUMN `phone`;
+ALTER TABLE `candidate` DROP COLUMN `email`;
\ No newline at end of file
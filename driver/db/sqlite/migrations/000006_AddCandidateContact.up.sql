@@ -0,0 +1,21 @@
+ALTER TABLE `candidate` ADD COLUMN `email` varchar(254) DEFAULT NULL;
+ALTER TABLE `candidate` ADD COLUMN `phone` varchar(30) DEFAULT NULL;
+
+CREATE INDEX `idx_candidate_email` ON `candidate` (`email`);
+CREATE INDEX `idx_candidate_phone` ON `candidate` (`phone`);
+
+CREATE TABLE `candidate_consent` (
+    `id` varchar(50) NOT NULL,
+    `candidate_id` varchar(50) NOT NULL,
+    `purpose` varchar(20) NOT NULL CHECK (`purpose` IN ('contact', 'retention')),
+    `source` varchar(20) NOT NULL,
+    `granted_at` DATETIME NOT NULL,
+    `expires_at` DATETIME NOT NULL,
+    `withdrawn_at` DATETIME DEFAULT NULL,
+    `recorded_by` varchar(100) NOT NULL,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_consent_1` FOREIGN KEY (`candidate_id`) REFERENCES `candidate` (`id`) ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE INDEX `idx_consent_candidate` ON `candidate_consent` (`candidate_id`, `purpose`, `expires_at`);
\ No newline at end of file
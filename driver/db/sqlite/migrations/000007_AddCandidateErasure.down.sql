@@ -0,0 +1 @@
+ALTER TABLE `candidate` DROP COLUMN `erased_at`;
\ No newline at end of file
@@ -0,0 +1,14 @@
+-- jobs without a profile were scored with the default one, which is now
+-- stored as their version 1
+INSERT INTO `scoring_profile` (`id`, `job_id`, `version`, `attitude_weight`, `relocation_weight`, `skill_weight`, `experience_weight`, `experience_bands`, `grade_values`)
+SELECT 'default-' || j.`id`, j.`id`, 1, 0.2, 0.2, 0.3, 0.3,
+    '[{"min_years":0,"score":50},{"min_years":2,"score":70},{"min_years":3,"score":80},{"min_years":4,"score":90},{"min_years":5,"score":100}]',
+    '{"A":100,"B":85,"C":70,"D":60,"E":50}'
+FROM `job` j
+WHERE NOT EXISTS (SELECT 1 FROM `scoring_profile` sp WHERE sp.`job_id` = j.`id`);
+
+UPDATE `candidate_score` SET `scoring_profile_id` = (
+    SELECT sp.`id` FROM `recruitment` r JOIN `scoring_profile` sp ON sp.`id` = 'default-' || r.`job_id`
+    WHERE r.`id` = `candidate_score`.`recruitment_id`
+)
+WHERE `scoring_profile_id` IS NULL;
\ No newline at end of file
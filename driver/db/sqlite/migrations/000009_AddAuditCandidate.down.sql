@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS `idx_audit_candidate`;
+
+ALTER TABLE `audit_log` DROP COLUMN `candidate_id`;
\ No newline at end of file
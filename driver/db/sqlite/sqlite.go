@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"github.com/golang-migrate/migrate/v4"
+	sqliteMigrate "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "modernc.org/sqlite"
+	"time"
+)
+
+// MemoryDSN opens a database that lives in memory and is gone when the app
+// stops.
+const MemoryDSN = ":memory:"
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// ConnectDB opens the SQLite database at dsn, a file path or MemoryDSN.
+// Foreign keys are enforced and times are stored in UTC, as MySQL does.
+func ConnectDB(dsn string) *sql.DB {
+	// the driver registered by the sqlite package, which also carries its
+	// user defined functions
+	registered, err := sql.Open("sqlite", "")
+	if err != nil {
+		panic(err)
+	}
+	defer registered.Close()
+
+	db := sql.OpenDB(connector{
+		driver: registered.Driver(),
+		dsn:    dsn + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite",
+	})
+
+	// SQLite has a single writer, and an in-memory database exists only as
+	// long as its one connection, so the pool is kept to one open connection.
+	db.SetMaxOpenConns(1)
+	db.SetMaxIdleConns(1)
+	db.SetConnMaxLifetime(0)
+
+	if err := db.Ping(); err != nil {
+		panic(err)
+	}
+
+	return db
+}
+
+// Migrate applies the embedded SQLite migrations.
+func Migrate(db *sql.DB) error {
+	m, err := newMigrate(db)
+	if err != nil {
+		return err
+	}
+
+	if err = m.Up(); err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	return nil
+}
+
+// newMigrate returns the migrations of db, read from the embedded files.
+func newMigrate(db *sql.DB) (*migrate.Migrate, error) {
+	source, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	dbInstance, err := sqliteMigrate.WithInstance(db, &sqliteMigrate.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithInstance("iofs", source, "sqlite", dbInstance)
+}
+
+type connector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c connector) Connect(context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	return utcConn{conn.(sqliteConn)}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// sqliteConn lists the interfaces implemented by the connections of the
+// SQLite driver, so utcConn keeps them.
+type sqliteConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+}
+
+// utcConn converts time arguments to UTC. SQLite stores times as text, so
+// values written in different zones would not compare correctly.
+type utcConn struct {
+	sqliteConn
+}
+
+func (c utcConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if t, ok := nv.Value.(time.Time); ok {
+		nv.Value = t.UTC()
+		return nil
+	}
+
+	return driver.ErrSkip
+}
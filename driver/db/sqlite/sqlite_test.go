@@ -0,0 +1,15 @@
+package sqlite
+
+import (
+	"github.com/golang-migrate/migrate/v4"
+	"path/filepath"
+	"talentapp/driver/db/migrationtest"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	db := ConnectDB(filepath.Join(t.TempDir(), "talentapp.db"))
+	defer db.Close()
+
+	migrationtest.Run(t, db, migrationtest.Versions{SeedDefaultScoringProfile: 8, AddAuditCandidate: 9}, func() (*migrate.Migrate, error) { return newMigrate(db) })
+}
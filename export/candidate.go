@@ -0,0 +1,65 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"talentapp/model"
+)
+
+// CandidateFilename names the export file of the data of a candidate.
+func CandidateFilename(data *model.CandidateExport) string {
+	return fmt.Sprintf("candidate-%s-%s.zip", data.Candidate.ID, data.ExportedAt.Format("20060102"))
+}
+
+// WriteCandidate writes the data of a candidate to w as a zip archive:
+// candidate.json holds data, and the content of every attachment, read with
+// open, is under attachments/ with the id of the attachment before its name.
+func WriteCandidate(w io.Writer, data *model.CandidateExport, open func(attachment model.Attachment) (io.ReadCloser, error)) error {
+	archive := zip.NewWriter(w)
+
+	file, err := archive.Create("candidate.json")
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(data); err != nil {
+		return err
+	}
+
+	for _, attachment := range data.Attachments {
+		if err = writeAttachment(archive, attachment, open); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func writeAttachment(archive *zip.Writer, attachment model.Attachment, open func(attachment model.Attachment) (io.ReadCloser, error)) error {
+	content, err := open(attachment)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	// uploaded names may hold separators, which would nest the file
+	name := strings.NewReplacer("/", "_", "\\", "_").Replace(attachment.Filename)
+	file, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     path.Join("attachments", attachment.ID+"-"+name),
+		Method:   zip.Deflate,
+		Modified: attachment.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(file, content)
+
+	return err
+}
@@ -0,0 +1,296 @@
+// Package export writes reports to files that can be downloaded and
+// forwarded.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/go-pdf/fpdf"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"strconv"
+	"strings"
+	"talentapp/model"
+)
+
+var scoreColumns = []string{
+	"Rank",
+	"Candidate",
+	"Address",
+	"Experience",
+	"Willing To Relocate",
+	"Willing To Relocate Score",
+	"Attitude Score",
+	"Skill Score",
+	"Experience Score",
+	"Overall Score",
+	"Interviewers",
+}
+
+// ContentType returns the MIME type of format.
+func ContentType(format string) string {
+	switch format {
+	case "csv":
+		return "text/csv"
+	case "xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case "pdf":
+		return "application/pdf"
+	case "zip":
+		return "application/zip"
+	}
+
+	return "application/octet-stream"
+}
+
+// ScoreFilename names the export file of a recruitment ranking.
+func ScoreFilename(report *model.CandidateScoreReport, format string) string {
+	return fmt.Sprintf("ranking-%s-%s.%s", report.Recruitment.ID, report.GeneratedAt.Format("20060102"), format)
+}
+
+// WriteScores writes the ranking of a recruitment to w as csv, xlsx or pdf.
+func WriteScores(w io.Writer, format string, report *model.CandidateScoreReport) error {
+	switch format {
+	case "csv":
+		return writeScoresCSV(w, report)
+	case "xlsx":
+		return writeScoresXLSX(w, report)
+	case "pdf":
+		return writeScoresPDF(w, report)
+	}
+
+	return fmt.Errorf("unsupported export format %q", format)
+}
+
+// scoreHeader returns the job and recruitment details printed above the
+// ranking as label and value pairs.
+func scoreHeader(report *model.CandidateScoreReport) [][2]string {
+	var (
+		recruitment = report.Recruitment
+		job         = recruitment.Job
+		header      [][2]string
+	)
+
+	if job != nil {
+		header = append(header,
+			[2]string{"Position", job.Position},
+			[2]string{"Department", job.Department},
+			[2]string{"Requester", job.Requester},
+		)
+	}
+
+	return append(header,
+		[2]string{"Recruitment", recruitment.ID},
+		[2]string{"Status", recruitment.Status},
+		[2]string{"Deadline", recruitment.Deadline.Format("2006-01-02")},
+		[2]string{"Generated At", report.GeneratedAt.Format("2006-01-02 15:04:05")},
+	)
+}
+
+func scoreRow(score model.CandidateScore) []string {
+	var candidate model.Candidate
+	if score.Candidate != nil {
+		candidate = *score.Candidate
+	}
+
+	return []string{
+		strconv.Itoa(score.Rank),
+		candidate.Name,
+		candidate.Address,
+		strconv.Itoa(candidate.Experience),
+		candidate.WillingToRelocate,
+		formatScore(score.WillingToRelocateScore),
+		formatScore(score.AttitudeScore),
+		formatScore(score.SkillScore),
+		formatScore(score.ExperienceScore),
+		formatScore(score.OverallScore),
+		strconv.Itoa(score.InterviewerCount),
+	}
+}
+
+// spreadsheetText keeps a spreadsheet from reading value as a formula, by
+// quoting it when it starts with a character that begins one.
+func spreadsheetText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', 2, 64)
+}
+
+func writeScoresCSV(w io.Writer, report *model.CandidateScoreReport) error {
+	writer := csv.NewWriter(w)
+
+	for _, field := range scoreHeader(report) {
+		if err := writer.Write([]string{field[0], spreadsheetText(field[1])}); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Write(nil); err != nil {
+		return err
+	}
+
+	if err := writer.Write(scoreColumns); err != nil {
+		return err
+	}
+
+	for _, score := range report.Scores {
+		row := scoreRow(score)
+		for i := range row {
+			row[i] = spreadsheetText(row[i])
+		}
+
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func writeScoresXLSX(w io.Writer, report *model.CandidateScoreReport) error {
+	const sheet = "Ranking"
+
+	file := excelize.NewFile()
+	defer file.Close()
+
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		return err
+	}
+
+	line := 1
+	for _, field := range scoreHeader(report) {
+		if err := file.SetSheetRow(sheet, cell(1, line), &[]interface{}{field[0], spreadsheetText(field[1])}); err != nil {
+			return err
+		}
+		line++
+	}
+
+	line++
+	if err := file.SetSheetRow(sheet, cell(1, line), &scoreColumns); err != nil {
+		return err
+	}
+
+	for _, score := range report.Scores {
+		line++
+
+		var candidate model.Candidate
+		if score.Candidate != nil {
+			candidate = *score.Candidate
+		}
+
+		row := []interface{}{
+			score.Rank,
+			spreadsheetText(candidate.Name),
+			spreadsheetText(candidate.Address),
+			candidate.Experience,
+			spreadsheetText(candidate.WillingToRelocate),
+			score.WillingToRelocateScore,
+			score.AttitudeScore,
+			score.SkillScore,
+			score.ExperienceScore,
+			score.OverallScore,
+			score.InterviewerCount,
+		}
+		if err := file.SetSheetRow(sheet, cell(1, line), &row); err != nil {
+			return err
+		}
+	}
+
+	return file.Write(w)
+}
+
+func cell(col, row int) string {
+	name, _ := excelize.CoordinatesToCellName(col, row)
+	return name
+}
+
+// pdfScoreColumns shortens scoreColumns so the table fits a landscape A4
+// page. Widths are in millimetres.
+var pdfScoreColumns = []struct {
+	title string
+	width float64
+	align string
+}{
+	{"Rank", 12, "R"},
+	{"Candidate", 42, "L"},
+	{"Address", 60, "L"},
+	{"Experience", 18, "R"},
+	{"Relocate", 16, "L"},
+	{"Relocate Sc.", 20, "R"},
+	{"Attitude", 18, "R"},
+	{"Skill", 18, "R"},
+	{"Experience Sc.", 22, "R"},
+	{"Overall", 18, "R"},
+	{"Interviewers", 19, "R"},
+}
+
+func writeScoresPDF(w io.Writer, report *model.CandidateScoreReport) error {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Candidate Ranking", true)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, "Candidate Ranking", "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, field := range scoreHeader(report) {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(35, 6, tr(field[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, tr(field[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(4)
+
+	header := func() {
+		pdf.SetFont("Helvetica", "B", 8)
+		pdf.SetFillColor(233, 236, 239)
+		for _, column := range pdfScoreColumns {
+			pdf.CellFormat(column.width, 8, column.title, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 8)
+	}
+
+	header()
+	for _, score := range report.Scores {
+		if pdf.GetY()+7 > 195 {
+			pdf.AddPage()
+			header()
+		}
+
+		for i, value := range scoreRow(score) {
+			column := pdfScoreColumns[i]
+			pdf.CellFormat(column.width, 7, truncate(pdf, tr(value), column.width-2), "1", 0, column.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	if len(report.Scores) == 0 {
+		pdf.CellFormat(0, 7, "No candidate has been scored yet.", "1", 1, "C", false, 0, "")
+	}
+
+	return pdf.Output(w)
+}
+
+// truncate shortens s so it fits into width millimetres of the current font.
+func truncate(pdf *fpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+
+	for len(s) > 0 && pdf.GetStringWidth(s+"...") > width {
+		s = s[:len(s)-1]
+	}
+
+	return s + "..."
+}
@@ -0,0 +1,60 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"github.com/xuri/excelize/v2"
+	"talentapp/model"
+	"testing"
+)
+
+func TestWriteScoresQuotesFormulas(t *testing.T) {
+	report := &model.CandidateScoreReport{
+		Recruitment: &model.Recruitment{ID: "recruitment", Job: &model.Job{Position: "=1+1"}},
+		Scores: []model.CandidateScore{{
+			Rank:      1,
+			Candidate: &model.Candidate{Name: "=HYPERLINK(\"http://evil\")", Address: "@SUM(A1)", WillingToRelocate: "yes"},
+		}},
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteScores(&buffer, "csv", report); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := csv.NewReader(&buffer)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := records[0][1]; got != "'=1+1" {
+		t.Errorf("csv position: got %q, want it quoted", got)
+	}
+
+	row := records[len(records)-1]
+	if row[1] != "'=HYPERLINK(\"http://evil\")" || row[2] != "'@SUM(A1)" || row[4] != "yes" {
+		t.Errorf("csv row: got %q, want the name and address quoted", row)
+	}
+
+	buffer.Reset()
+	if err = WriteScores(&buffer, "xlsx", report); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := excelize.OpenReader(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	rows, err := file.GetRows("Ranking")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := rows[len(rows)-1][1]; got != "'=HYPERLINK(\"http://evil\")" {
+		t.Errorf("xlsx name: got %q, want it quoted", got)
+	}
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"talentapp/middleware"
+	"talentapp/model"
+	"talentapp/usecase"
+	"talentapp/utils"
+)
+
+type applicationHandler struct {
+	applicationUsecase usecase.ApplicationUsecase
+	recruitmentUsecase usecase.RecruitmentUsecase
+	candidateUsecase   usecase.CandidateUsecase
+}
+
+func NewApplicationHandler(
+	applicationUsecase usecase.ApplicationUsecase,
+	recruitmentUsecase usecase.RecruitmentUsecase,
+	candidateUsecase usecase.CandidateUsecase,
+) *applicationHandler {
+	return &applicationHandler{
+		applicationUsecase: applicationUsecase,
+		recruitmentUsecase: recruitmentUsecase,
+		candidateUsecase:   candidateUsecase,
+	}
+}
+
+func (h *applicationHandler) Router(app *fiber.App, auth *middleware.Auth) {
+	app.Get("/web/recruitment/show/:id/board", auth.Authenticate, auth.Require(model.PermApplicationRead), h.Board)
+	app.Post("/web/recruitment/:id/application", auth.Authenticate, auth.Require(model.PermApplicationWrite), h.Create)
+
+	application := app.Group("/web/application", auth.Authenticate)
+	application.Post("/:id/stage", auth.Require(model.PermApplicationWrite), h.Move)
+	application.Get("/show/:id/events", auth.Require(model.PermApplicationRead), auth.RequireApplicationAssignment, h.Events)
+}
+
+func (h *applicationHandler) Board(ctx *fiber.Ctx) error {
+	return h.renderBoard(ctx, ctx.Params("id"), nil)
+}
+
+func (h *applicationHandler) Create(ctx *fiber.Ctx) error {
+	var (
+		payload model.ApplicationCreateRequest
+		err     error
+		ok      bool
+		id      = ctx.Params("id")
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return h.renderBoard(ctx, id, err)
+	}
+
+	payload.ChangedBy = middleware.CurrentUser(ctx).Name
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return h.renderBoard(ctx, id, err)
+	}
+
+	_, err = h.applicationUsecase.CreateNewApplication(ctx.Context(), id, payload)
+	if err != nil {
+		return h.renderBoard(ctx, id, err)
+	}
+
+	return ctx.Redirect("/web/recruitment/show/"+id+"/board", http.StatusFound)
+}
+
+func (h *applicationHandler) Move(ctx *fiber.Ctx) error {
+	var (
+		payload model.ApplicationMoveRequest
+		err     error
+		ok      bool
+		id      = ctx.Params("id")
+	)
+
+	application, err := h.applicationUsecase.GetApplicationByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return h.renderBoard(ctx, application.RecruitmentID, err)
+	}
+
+	payload.ChangedBy = middleware.CurrentUser(ctx).Name
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return h.renderBoard(ctx, application.RecruitmentID, err)
+	}
+
+	_, err = h.applicationUsecase.MoveApplication(ctx.Context(), id, payload)
+	if err != nil {
+		return h.renderBoard(ctx, application.RecruitmentID, err)
+	}
+
+	return ctx.Redirect("/web/recruitment/show/"+application.RecruitmentID+"/board", http.StatusFound)
+}
+
+func (h *applicationHandler) Events(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	application, err := h.applicationUsecase.GetApplicationByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	candidate, err := h.candidateUsecase.GetCandidateByID(ctx.Context(), application.CandidateID)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	result, err := h.applicationUsecase.GetApplicationEvents(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	return ctx.Render(
+		"application_events",
+		fiber.Map{
+			"application": application,
+			"candidate":   candidate,
+			"events":      result,
+		},
+	)
+}
+
+// renderBoard renders the board of a recruitment, showing formErr above it
+// when a form submitted from the board failed.
+func (h *applicationHandler) renderBoard(ctx *fiber.Ctx, id string, formErr error) error {
+	recruitment, err := h.recruitmentUsecase.GetRecruitmentByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	board, err := h.applicationUsecase.GetApplicationBoard(ctx.Context(), id, middleware.AssignedTo(ctx))
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	candidates, err := selectCandidates(ctx.Context(), h.candidateUsecase, middleware.AssignedTo(ctx))
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	data := fiber.Map{
+		"recruitment": recruitment,
+		"board":       board,
+		"candidates":  candidates,
+	}
+
+	if formErr != nil {
+		data["error"] = formErr.Error()
+	}
+
+	return ctx.Render("application_board", data)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"talentapp/middleware"
+	"talentapp/model"
+	"talentapp/usecase"
+	"talentapp/utils"
+)
+
+type auditHandler struct {
+	auditUsecase usecase.AuditUsecase
+}
+
+func NewAuditHandler(auditUsecase usecase.AuditUsecase) *auditHandler {
+	return &auditHandler{
+		auditUsecase: auditUsecase,
+	}
+}
+
+func (h *auditHandler) Router(app *fiber.App, auth *middleware.Auth) {
+	audit := app.Group("/web/audit", auth.Authenticate, auth.Require(model.PermAuditRead))
+	audit.Get("", h.Index)
+}
+
+func (h *auditHandler) Index(ctx *fiber.Ctx) error {
+	var (
+		filter model.AuditLogListRequest
+		err    error
+		ok     bool
+	)
+
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	result, meta, err := h.auditUsecase.GetAuditLogs(ctx.Context(), filter)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	utils.SetPageLinks(ctx, meta)
+
+	return ctx.Render(
+		"audit_index",
+		fiber.Map{
+			"logs":        result,
+			"filter":      filter,
+			"meta":        meta,
+			"entityTypes": model.EntityTypes,
+			"actions":     model.Actions,
+		},
+	)
+}
@@ -1,63 +1,168 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"github.com/gofiber/fiber/v2"
+	"io"
 	"net/http"
+	"talentapp/export"
+	"talentapp/middleware"
 	"talentapp/model"
 	"talentapp/usecase"
 	"talentapp/utils"
 )
 
 type candidateHandler struct {
-	candidateUsecase usecase.CandidateUsecase
+	candidateUsecase  usecase.CandidateUsecase
+	attachmentUsecase usecase.AttachmentUsecase
+	privacyUsecase    usecase.PrivacyUsecase
 }
 
-func NewCandidateHandler(candidateUsecase usecase.CandidateUsecase) *candidateHandler {
+func NewCandidateHandler(candidateUsecase usecase.CandidateUsecase, attachmentUsecase usecase.AttachmentUsecase, privacyUsecase usecase.PrivacyUsecase) *candidateHandler {
 	return &candidateHandler{
-		candidateUsecase: candidateUsecase,
+		candidateUsecase:  candidateUsecase,
+		attachmentUsecase: attachmentUsecase,
+		privacyUsecase:    privacyUsecase,
 	}
 }
 
-func (h *candidateHandler) Router(app *fiber.App) {
-	candidate := app.Group("/web/candidate")
-	candidate.Get("", h.Index)
-	candidate.Get("/new", h.New)
-	candidate.Get("/show/:id", h.GetByID)
-	candidate.Post("", h.Create)
+func (h *candidateHandler) Router(app *fiber.App, auth *middleware.Auth) {
+	candidate := app.Group("/web/candidate", auth.Authenticate)
+	candidate.Get("", auth.Require(model.PermCandidateRead), h.Index)
+	candidate.Get("/new", auth.Require(model.PermCandidateWrite), h.New)
+	candidate.Post("/new/resume", auth.Require(model.PermCandidateWrite), h.PrefillNew)
+	candidate.Get("/show/:id", auth.Require(model.PermCandidateRead), auth.RequireCandidateAssignment, h.GetByID)
+	candidate.Get("/show/:id/duplicate", auth.Require(model.PermCandidateRead), auth.RequireEveryCandidate, h.Duplicates)
+	candidate.Post("", auth.Require(model.PermCandidateWrite), h.Create)
+	candidate.Get("/import", auth.Require(model.PermCandidateWrite), h.ImportForm)
+	candidate.Post("/import", auth.Require(model.PermCandidateWrite), h.Import)
+	candidate.Get("/edit/:id", auth.Require(model.PermCandidateWrite), h.Edit)
+	candidate.Post("/edit/:id", auth.Require(model.PermCandidateWrite), h.Update)
+	candidate.Post("/delete/:id", auth.Require(model.PermCandidateWrite), h.Delete)
+	candidate.Post("/:id/merge", auth.Require(model.PermCandidateWrite), h.Merge)
+	candidate.Post("/:id/attachment", auth.Require(model.PermCandidateWrite), h.UploadAttachment)
+	candidate.Get("/:id/attachment/:attachment_id", auth.Require(model.PermCandidateRead), auth.RequireCandidateAssignment, h.DownloadAttachment)
+	candidate.Post("/:id/attachment/:attachment_id/delete", auth.Require(model.PermCandidateWrite), h.DeleteAttachment)
+	candidate.Post("/:id/consent", auth.Require(model.PermCandidateWrite), h.CreateConsent)
+	candidate.Post("/:id/consent/:consent_id/withdraw", auth.Require(model.PermCandidateWrite), h.WithdrawConsent)
+	candidate.Get("/:id/contact", auth.Require(model.PermCandidateRead), auth.RequireCandidateAssignment, h.Contact)
+	candidate.Get("/show/:id/export", auth.Require(model.PermCandidateWrite), auth.Require(model.PermAuditRead), h.Export)
+	candidate.Post("/:id/erase", auth.Require(model.PermCandidateWrite), h.Erase)
 }
 
 func (h *candidateHandler) Index(ctx *fiber.Ctx) error {
-	result, err := h.candidateUsecase.GetCandidates(ctx.Context())
+	var (
+		filter model.CandidateListRequest
+		err    error
+		ok     bool
+	)
+
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	filter.AssignedTo = middleware.AssignedTo(ctx)
+
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	result, meta, err := h.candidateUsecase.GetCandidates(ctx.Context(), filter)
 	if err != nil {
 		return ctx.Render("error", nil)
 	}
 
+	utils.SetPageLinks(ctx, meta)
+
 	return ctx.Render(
 		"candidate_index",
 		fiber.Map{
 			"candidates": result,
+			"meta":       meta,
+			"filter":     filter,
 		},
 	)
 }
 
 func (h *candidateHandler) New(ctx *fiber.Ctx) error {
-	return ctx.Render("candidate_new", nil)
+	return ctx.Render("candidate_new", fiber.Map{
+		"candidate": model.CandidateCreateRequest{},
+	})
+}
+
+// PrefillNew fills the new candidate form with what could be read from an
+// uploaded resume. The resume itself is not stored.
+func (h *candidateHandler) PrefillNew(ctx *fiber.Ctx) error {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.Render("candidate_new", fiber.Map{
+			"error":     "please choose a PDF, DOCX or TXT resume",
+			"candidate": model.CandidateCreateRequest{},
+		})
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return ctx.Render("candidate_new", fiber.Map{
+			"error":     err.Error(),
+			"candidate": model.CandidateCreateRequest{},
+		})
+	}
+	defer file.Close()
+
+	parsed, err := h.attachmentUsecase.ParseResume(ctx.Context(), header.Filename, file)
+	if err != nil {
+		return ctx.Render("candidate_new", fiber.Map{
+			"error":     err.Error(),
+			"candidate": model.CandidateCreateRequest{},
+		})
+	}
+
+	var form model.CandidateCreateRequest
+	parsed.Prefill(&form)
+
+	return ctx.Render("candidate_new", fiber.Map{
+		"candidate": form,
+		"resume":    parsed,
+	})
 }
 
 func (h *candidateHandler) GetByID(ctx *fiber.Ctx) error {
-	var id = ctx.Params("id")
+	return h.renderShow(ctx, ctx.Params("id"), nil)
+}
 
-	result, err := h.candidateUsecase.GetCandidateByID(ctx.Context(), id)
+// renderShow renders the candidate page, with formErr on top when an upload,
+// a consent or a contact failed.
+func (h *candidateHandler) renderShow(ctx *fiber.Ctx, id string, formErr error) error {
+	result, err := h.candidateUsecase.GetCandidateHistory(ctx.Context(), id, middleware.AssignedTo(ctx))
 	if err != nil {
 		return ctx.Render("error", nil)
 	}
 
-	return ctx.Render(
-		"candidate_show",
-		fiber.Map{
-			"candidate": result,
-		},
-	)
+	attachments, err := h.attachmentUsecase.GetAttachments(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	data := fiber.Map{
+		"candidate":   result.Candidate,
+		"history":     result.Recruitments,
+		"attachments": attachments,
+		"kinds":       model.AttachmentKinds,
+		"purposes":    model.ConsentPurposes,
+		"sources":     model.ConsentSources,
+	}
+
+	if formErr != nil {
+		data["error"] = formErr.Error()
+	}
+
+	return ctx.Render("candidate_show", data)
 }
 
 func (h *candidateHandler) Create(ctx *fiber.Ctx) error {
@@ -69,22 +174,418 @@ func (h *candidateHandler) Create(ctx *fiber.Ctx) error {
 
 	if err = ctx.BodyParser(&payload); err != nil {
 		return ctx.Render("candidate_new", fiber.Map{
-			"error": err,
+			"error":     err,
+			"candidate": payload,
 		})
 	}
 
 	if ok, err = utils.IsRequestValid(payload); !ok {
 		return ctx.Render("candidate_new", fiber.Map{
-			"error": err,
+			"error":     err,
+			"candidate": payload,
 		})
 	}
 
 	_, err = h.candidateUsecase.CreateNewCandidate(ctx.Context(), payload)
+	if duplicate := new(usecase.DuplicateError); errors.As(err, &duplicate) {
+		return ctx.Render("candidate_new", fiber.Map{
+			"duplicates": duplicate.Duplicates,
+			"candidate":  payload,
+		})
+	}
+
 	if err != nil {
 		return ctx.Render("candidate_new", fiber.Map{
-			"error": err,
+			"error":     err,
+			"candidate": payload,
 		})
 	}
 
 	return ctx.Redirect("/web/candidate", http.StatusFound)
 }
+
+// Edit shows the candidate form. With the resume query param, naming a
+// stored resume of the candidate, the form is prefilled from that resume.
+func (h *candidateHandler) Edit(ctx *fiber.Ctx) error {
+	var (
+		id       = ctx.Params("id")
+		resumeID = ctx.Query("resume")
+	)
+
+	result, err := h.candidateUsecase.GetCandidateByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	data := fiber.Map{
+		"candidate": result,
+	}
+
+	if resumeID != "" {
+		parsed, err := h.attachmentUsecase.ParseAttachment(ctx.Context(), id, resumeID)
+		if err != nil {
+			data["error"] = err.Error()
+			return ctx.Render("candidate_edit", data)
+		}
+
+		form := model.CandidateCreateRequest{
+			Name:              result.Name,
+			Address:           result.Address,
+			Experience:        result.Experience,
+			WillingToRelocate: result.WillingToRelocate,
+			Email:             result.Email,
+			Phone:             result.Phone,
+			Skills:            result.Skills,
+		}
+		parsed.Prefill(&form)
+
+		result.Name, result.Address, result.Experience, result.Skills = form.Name, form.Address, form.Experience, form.Skills
+		result.Email, result.Phone = form.Email, form.Phone
+		data["resume"] = parsed
+	}
+
+	return ctx.Render("candidate_edit", data)
+}
+
+func (h *candidateHandler) Update(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.CandidateCreateRequest
+		err     error
+		ok      bool
+	)
+
+	result, err := h.candidateUsecase.GetCandidateByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Render("candidate_edit", fiber.Map{
+			"error":     err.Error(),
+			"candidate": result,
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Render("candidate_edit", fiber.Map{
+			"error":     err.Error(),
+			"candidate": result,
+		})
+	}
+
+	// the form always sends skills and contact details, so empty fields
+	// remove them
+	if payload.Skills == nil {
+		payload.Skills = []string{}
+	}
+
+	update := payload.UpdateRequest()
+	update.Email, update.Phone = &payload.Email, &payload.Phone
+
+	_, err = h.candidateUsecase.UpdateCandidate(ctx.Context(), id, update)
+	if err != nil {
+		return ctx.Render("candidate_edit", fiber.Map{
+			"error":     err.Error(),
+			"candidate": result,
+		})
+	}
+
+	return ctx.Redirect("/web/candidate/show/"+id, http.StatusFound)
+}
+
+func (h *candidateHandler) Delete(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	err := h.candidateUsecase.DeleteCandidate(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Redirect("/web/candidate", http.StatusFound)
+}
+
+func (h *candidateHandler) Duplicates(ctx *fiber.Ctx) error {
+	return h.renderDuplicates(ctx, ctx.Params("id"), nil)
+}
+
+// renderDuplicates renders the look-alikes of a candidate, with formErr
+// above them when a merge failed.
+func (h *candidateHandler) renderDuplicates(ctx *fiber.Ctx, id string, formErr error) error {
+	candidate, err := h.candidateUsecase.GetCandidateByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	result, err := h.candidateUsecase.GetDuplicates(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	data := fiber.Map{
+		"candidate":  candidate,
+		"duplicates": result,
+	}
+
+	if formErr != nil {
+		data["error"] = formErr.Error()
+	}
+
+	return ctx.Render("candidate_duplicate", data)
+}
+
+// Merge merges a duplicate into the candidate and shows the candidate.
+func (h *candidateHandler) Merge(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.CandidateMergeRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return h.renderDuplicates(ctx, id, err)
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return h.renderDuplicates(ctx, id, err)
+	}
+
+	if _, err = h.candidateUsecase.MergeCandidates(ctx.Context(), id, payload); err != nil {
+		return h.renderDuplicates(ctx, id, err)
+	}
+
+	return ctx.Redirect("/web/candidate/show/"+id, http.StatusFound)
+}
+
+func (h *candidateHandler) ImportForm(ctx *fiber.Ctx) error {
+	return ctx.Render("candidate_import", fiber.Map{
+		"mode": model.ImportModeAll,
+	})
+}
+
+// Import previews or imports an uploaded file and shows the per-row report.
+func (h *candidateHandler) Import(ctx *fiber.Ctx) error {
+	var (
+		payload model.CandidateImportRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Render("candidate_import", fiber.Map{
+			"error": err.Error(),
+			"mode":  model.ImportModeAll,
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Render("candidate_import", fiber.Map{
+			"error": err.Error(),
+			"mode":  model.ImportModeAll,
+		})
+	}
+
+	payload.Normalize()
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		return ctx.Render("candidate_import", fiber.Map{
+			"error":           "please choose a CSV or XLSX file",
+			"mode":            payload.Mode,
+			"allowDuplicates": payload.AllowDuplicates,
+		})
+	}
+
+	rows, err := utils.ReadSpreadsheetFile(file)
+	if err != nil {
+		return ctx.Render("candidate_import", fiber.Map{
+			"error":           err.Error(),
+			"mode":            payload.Mode,
+			"allowDuplicates": payload.AllowDuplicates,
+		})
+	}
+
+	result, err := h.candidateUsecase.ImportCandidates(ctx.Context(), rows, payload)
+	if err != nil {
+		return ctx.Render("candidate_import", fiber.Map{
+			"error":           err.Error(),
+			"mode":            payload.Mode,
+			"allowDuplicates": payload.AllowDuplicates,
+		})
+	}
+
+	var formErr string
+	if !result.DryRun && result.Mode == model.ImportModeAll && result.Invalid > 0 {
+		formErr = "nothing was imported, fix the invalid rows or choose to skip them"
+	}
+
+	return ctx.Render("candidate_import", fiber.Map{
+		"error":           formErr,
+		"mode":            payload.Mode,
+		"allowDuplicates": payload.AllowDuplicates,
+		"result":          result,
+	})
+}
+
+func (h *candidateHandler) UploadAttachment(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.AttachmentCreateRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return h.renderShow(ctx, id, err)
+	}
+
+	payload.UploadedBy = middleware.CurrentUser(ctx).Name
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return h.renderShow(ctx, id, err)
+	}
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return h.renderShow(ctx, id, errors.New("please choose a file"))
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return h.renderShow(ctx, id, err)
+	}
+	defer file.Close()
+
+	_, err = h.attachmentUsecase.UploadAttachment(ctx.Context(), id, header.Filename, file, payload)
+	if err != nil {
+		return h.renderShow(ctx, id, err)
+	}
+
+	return ctx.Redirect("/web/candidate/show/"+id, http.StatusFound)
+}
+
+func (h *candidateHandler) DownloadAttachment(ctx *fiber.Ctx) error {
+	var (
+		id           = ctx.Params("id")
+		attachmentID = ctx.Params("attachment_id")
+	)
+
+	result, content, err := h.attachmentUsecase.OpenAttachment(ctx.Context(), id, attachmentID)
+	if err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return utils.SendFile(ctx, result.Filename, result.ContentType, result.Size, content)
+}
+
+func (h *candidateHandler) DeleteAttachment(ctx *fiber.Ctx) error {
+	var (
+		id           = ctx.Params("id")
+		attachmentID = ctx.Params("attachment_id")
+	)
+
+	err := h.attachmentUsecase.DeleteAttachment(ctx.Context(), id, attachmentID)
+	if err != nil {
+		return h.renderShow(ctx, id, err)
+	}
+
+	return ctx.Redirect("/web/candidate/show/"+id, http.StatusFound)
+}
+
+func (h *candidateHandler) CreateConsent(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.CandidateConsentCreateRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return h.renderShow(ctx, id, err)
+	}
+
+	payload.RecordedBy = middleware.CurrentUser(ctx).Name
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return h.renderShow(ctx, id, err)
+	}
+
+	_, err = h.candidateUsecase.CreateConsent(ctx.Context(), id, payload)
+	if err != nil {
+		return h.renderShow(ctx, id, err)
+	}
+
+	return ctx.Redirect("/web/candidate/show/"+id, http.StatusFound)
+}
+
+func (h *candidateHandler) WithdrawConsent(ctx *fiber.Ctx) error {
+	var (
+		id        = ctx.Params("id")
+		consentID = ctx.Params("consent_id")
+	)
+
+	_, err := h.candidateUsecase.WithdrawConsent(ctx.Context(), id, consentID)
+	if err != nil {
+		return h.renderShow(ctx, id, err)
+	}
+
+	return ctx.Redirect("/web/candidate/show/"+id, http.StatusFound)
+}
+
+// Contact opens a mail, or a call when there is no email, to the candidate
+// once their consent allows it.
+func (h *candidateHandler) Contact(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.candidateUsecase.GetContact(ctx.Context(), id)
+	if err != nil {
+		return h.renderShow(ctx, id, err)
+	}
+
+	if result.Email != "" {
+		return ctx.Redirect("mailto:"+result.Email, http.StatusFound)
+	}
+
+	return ctx.Redirect("tel:"+result.Phone, http.StatusFound)
+}
+
+// Export downloads everything stored about the candidate as a zip archive.
+func (h *candidateHandler) Export(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	result, err := h.privacyUsecase.ExportCandidate(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return utils.SendAttachment(ctx, export.CandidateFilename(result), export.ContentType("zip"), func(w io.Writer) error {
+		return export.WriteCandidate(w, result, func(attachment model.Attachment) (io.ReadCloser, error) {
+			return h.privacyUsecase.OpenAttachment(context.Background(), attachment)
+		})
+	})
+}
+
+// Erase anonymizes the candidate and shows what is left of them.
+func (h *candidateHandler) Erase(ctx *fiber.Ctx) error {
+	var (
+		id = ctx.Params("id")
+	)
+
+	_, err := h.privacyUsecase.EraseCandidate(ctx.Context(), id)
+	if err != nil {
+		return h.renderShow(ctx, id, err)
+	}
+
+	return ctx.Redirect("/web/candidate/show/"+id, http.StatusFound)
+}
@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"net/http"
+	"strconv"
+	"strings"
+	"talentapp/middleware"
 	"talentapp/model"
 	"talentapp/usecase"
 	"talentapp/utils"
@@ -18,30 +22,59 @@ func NewJobHandler(jobUsecase usecase.JobUsecase) *jobHandler {
 	}
 }
 
-func (h *jobHandler) Router(app *fiber.App) {
-	job := app.Group("/web/job")
-	job.Get("", h.Index)
-	job.Get("/new", h.New)
-	job.Get("/show/:id", h.GetByID)
-	job.Post("", h.Create)
+func (h *jobHandler) Router(app *fiber.App, auth *middleware.Auth) {
+	job := app.Group("/web/job", auth.Authenticate)
+	job.Get("", auth.Require(model.PermJobRead), h.Index)
+	job.Get("/new", auth.Require(model.PermJobWrite), h.New)
+	job.Get("/show/:id", auth.Require(model.PermJobRead), h.GetByID)
+	job.Post("", auth.Require(model.PermJobWrite), h.Create)
+	job.Get("/edit/:id", auth.Require(model.PermJobWrite), h.Edit)
+	job.Post("/edit/:id", auth.Require(model.PermJobWrite), h.Update)
+	job.Post("/delete/:id", auth.Require(model.PermJobWrite), h.Delete)
+	job.Get("/:id/scoring-profile/edit", auth.Require(model.PermJobWrite), h.EditScoringProfile)
+	job.Post("/:id/scoring-profile", auth.Require(model.PermJobWrite), h.UpdateScoringProfile)
 }
 
 func (h *jobHandler) Index(ctx *fiber.Ctx) error {
-	result, err := h.jobUsecase.GetJobs(ctx.Context())
+	var (
+		filter model.JobListRequest
+		err    error
+		ok     bool
+	)
+
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	result, meta, err := h.jobUsecase.GetJobs(ctx.Context(), filter)
 	if err != nil {
 		return ctx.Render("error", nil)
 	}
 
+	utils.SetPageLinks(ctx, meta)
+
 	return ctx.Render(
 		"job_index",
 		fiber.Map{
-			"jobs": result,
+			"jobs":   result,
+			"meta":   meta,
+			"filter": filter,
 		},
 	)
 }
 
 func (h *jobHandler) New(ctx *fiber.Ctx) error {
-	return ctx.Render("job_new", nil)
+	return ctx.Render("job_new", fiber.Map{
+		"requirements": model.JobRequirements{},
+	})
 }
 
 func (h *jobHandler) GetByID(ctx *fiber.Ctx) error {
@@ -52,39 +85,306 @@ func (h *jobHandler) GetByID(ctx *fiber.Ctx) error {
 		return ctx.Render("error", nil)
 	}
 
+	profile, err := h.jobUsecase.GetScoringProfile(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
 	return ctx.Render(
 		"job_show",
 		fiber.Map{
-			"job": result,
+			"job":     result,
+			"profile": profile,
 		},
 	)
 }
 
 func (h *jobHandler) Create(ctx *fiber.Ctx) error {
 	var (
-		payload model.JobCreateRequest
-		err     error
-		ok      bool
+		payload      model.JobCreateRequest
+		requirements model.JobRequirements
+		err          error
+		ok           bool
 	)
 
-	if err = ctx.BodyParser(&payload); err != nil {
+	if err = ctx.BodyParser(&payload); err == nil {
+		err = ctx.BodyParser(&requirements)
+	}
+	splitSkills(&requirements)
+
+	if err != nil {
 		return ctx.Render("job_new", fiber.Map{
-			"error": err.Error(),
+			"error":        err.Error(),
+			"requirements": requirements,
 		})
 	}
 
+	payload.Requirements = &requirements
+
 	if ok, err = utils.IsRequestValid(payload); !ok {
 		return ctx.Render("job_new", fiber.Map{
-			"error": err.Error(),
+			"error":        err.Error(),
+			"requirements": requirements,
 		})
 	}
 
 	_, err = h.jobUsecase.CreateNewJob(ctx.Context(), payload)
 	if err != nil {
 		return ctx.Render("job_new", fiber.Map{
+			"error":        err.Error(),
+			"requirements": requirements,
+		})
+	}
+
+	return ctx.Redirect("/web/job", http.StatusFound)
+}
+
+func (h *jobHandler) Edit(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	result, err := h.jobUsecase.GetJobByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	return ctx.Render(
+		"job_edit",
+		fiber.Map{
+			"job": result,
+		},
+	)
+}
+
+func (h *jobHandler) Update(ctx *fiber.Ctx) error {
+	var (
+		id           = ctx.Params("id")
+		payload      model.JobCreateRequest
+		requirements model.JobRequirements
+		err          error
+		ok           bool
+	)
+
+	result, err := h.jobUsecase.GetJobByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	if err = ctx.BodyParser(&payload); err == nil {
+		err = ctx.BodyParser(&requirements)
+	}
+	splitSkills(&requirements)
+
+	if err != nil {
+		return ctx.Render("job_edit", fiber.Map{
+			"error": err.Error(),
+			"job":   result,
+		})
+	}
+
+	// the form always sends the requirements, so emptied fields clear them
+	payload.Requirements = &requirements
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Render("job_edit", fiber.Map{
+			"error": err.Error(),
+			"job":   result,
+		})
+	}
+
+	_, err = h.jobUsecase.UpdateJob(ctx.Context(), id, payload.UpdateRequest())
+	if err != nil {
+		return ctx.Render("job_edit", fiber.Map{
+			"error": err.Error(),
+			"job":   result,
+		})
+	}
+
+	return ctx.Redirect("/web/job/show/"+id, http.StatusFound)
+}
+
+func (h *jobHandler) Delete(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	err := h.jobUsecase.DeleteJob(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
 	return ctx.Redirect("/web/job", http.StatusFound)
 }
+
+// scoringProfileForm is the web form of model.ScoringProfileRequest where
+// experience bands and grade values are typed as "key=value" lists, for
+// example "0=50, 2=70, 5=100" and "A=100, B=85, C=70".
+type scoringProfileForm struct {
+	AttitudeWeight   float64 `form:"attitude_weight"`
+	RelocationWeight float64 `form:"relocation_weight"`
+	SkillWeight      float64 `form:"skill_weight"`
+	ExperienceWeight float64 `form:"experience_weight"`
+	ExperienceBands  string  `form:"experience_bands"`
+	GradeValues      string  `form:"grade_values"`
+}
+
+func newScoringProfileForm(profile *model.ScoringProfile) scoringProfileForm {
+	var bands, grades []string
+
+	for _, band := range profile.ExperienceBands {
+		bands = append(bands, fmt.Sprintf("%d=%g", band.MinYears, band.Score))
+	}
+
+	for _, grade := range profile.Grades() {
+		grades = append(grades, fmt.Sprintf("%s=%g", grade, profile.GradeValues[grade]))
+	}
+
+	return scoringProfileForm{
+		AttitudeWeight:   profile.AttitudeWeight,
+		RelocationWeight: profile.RelocationWeight,
+		SkillWeight:      profile.SkillWeight,
+		ExperienceWeight: profile.ExperienceWeight,
+		ExperienceBands:  strings.Join(bands, ", "),
+		GradeValues:      strings.Join(grades, ", "),
+	}
+}
+
+func (f scoringProfileForm) request() (model.ScoringProfileRequest, error) {
+	result := model.ScoringProfileRequest{
+		AttitudeWeight:   f.AttitudeWeight,
+		RelocationWeight: f.RelocationWeight,
+		SkillWeight:      f.SkillWeight,
+		ExperienceWeight: f.ExperienceWeight,
+		GradeValues:      map[string]float64{},
+	}
+
+	for _, pair := range splitPairs(f.ExperienceBands) {
+		years, err := strconv.Atoi(pair[0])
+		if err != nil {
+			return result, fmt.Errorf("invalid experience band %q", strings.Join(pair, "="))
+		}
+
+		score, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil {
+			return result, fmt.Errorf("invalid experience band %q", strings.Join(pair, "="))
+		}
+
+		result.ExperienceBands = append(result.ExperienceBands, model.ExperienceBand{MinYears: years, Score: score})
+	}
+
+	for _, pair := range splitPairs(f.GradeValues) {
+		value, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil {
+			return result, fmt.Errorf("invalid grade value %q", strings.Join(pair, "="))
+		}
+
+		result.GradeValues[strings.ToUpper(pair[0])] = value
+	}
+
+	return result, nil
+}
+
+func splitPairs(value string) [][]string {
+	var result [][]string
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		pair := strings.SplitN(item, "=", 2)
+		if len(pair) != 2 {
+			pair = append(pair, "")
+		}
+
+		result = append(result, []string{strings.TrimSpace(pair[0]), strings.TrimSpace(pair[1])})
+	}
+
+	return result
+}
+
+// splitSkills splits the comma separated skill fields of the requirements
+// form, which the body parser leaves whole.
+func splitSkills(requirements *model.JobRequirements) {
+	for _, skills := range []*[]string{&requirements.RequiredSkills, &requirements.NiceToHaveSkills} {
+		var result []string
+		for _, value := range *skills {
+			result = append(result, strings.Split(value, ",")...)
+		}
+
+		*skills = result
+	}
+}
+
+func (h *jobHandler) EditScoringProfile(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	result, err := h.jobUsecase.GetJobByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	profile, err := h.jobUsecase.GetScoringProfile(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	return ctx.Render(
+		"scoring_profile_edit",
+		fiber.Map{
+			"job":     result,
+			"profile": profile,
+			"form":    newScoringProfileForm(profile),
+		},
+	)
+}
+
+func (h *jobHandler) UpdateScoringProfile(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		form    scoringProfileForm
+		payload model.ScoringProfileRequest
+		err     error
+		ok      bool
+	)
+
+	result, err := h.jobUsecase.GetJobByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	if err = ctx.BodyParser(&form); err != nil {
+		return ctx.Render("scoring_profile_edit", fiber.Map{
+			"error": err.Error(),
+			"job":   result,
+			"form":  form,
+		})
+	}
+
+	if payload, err = form.request(); err != nil {
+		return ctx.Render("scoring_profile_edit", fiber.Map{
+			"error": err.Error(),
+			"job":   result,
+			"form":  form,
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Render("scoring_profile_edit", fiber.Map{
+			"error": err.Error(),
+			"job":   result,
+			"form":  form,
+		})
+	}
+
+	_, err = h.jobUsecase.UpdateScoringProfile(ctx.Context(), id, payload)
+	if err != nil {
+		return ctx.Render("scoring_profile_edit", fiber.Map{
+			"error": err.Error(),
+			"job":   result,
+			"form":  form,
+		})
+	}
+
+	return ctx.Redirect("/web/job/show/"+id, http.StatusFound)
+}
@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"context"
 	"github.com/gofiber/fiber/v2"
+	"io"
 	"net/http"
+	"strings"
+	"talentapp/export"
+	"talentapp/middleware"
 	"talentapp/model"
 	"talentapp/usecase"
 	"talentapp/utils"
@@ -27,35 +32,69 @@ func NewRecruitmentHandler(
 	}
 }
 
-func (h *recruitmentHandler) Router(app *fiber.App) {
-	recruitment := app.Group("/web/recruitment")
-	recruitment.Get("", h.Index)
-	recruitment.Get("/new", h.New)
-	recruitment.Get("/show/:id", h.GetByID)
-	recruitment.Post("", h.Create)
-	recruitment.Get("/show/:id/score", h.ScoreList)
-	recruitment.Get("/:id/score/new", h.NewScore)
-	recruitment.Post("/edit/:id/status", h.UpdateStatus)
-	recruitment.Post("/:id/score", h.CreateScore)
+func (h *recruitmentHandler) Router(app *fiber.App, auth *middleware.Auth) {
+	recruitment := app.Group("/web/recruitment", auth.Authenticate)
+	recruitment.Get("", auth.Require(model.PermRecruitmentRead), h.Index)
+	recruitment.Get("/new", auth.Require(model.PermRecruitmentWrite), h.New)
+	recruitment.Get("/show/:id", auth.Require(model.PermRecruitmentRead), h.GetByID)
+	recruitment.Post("", auth.Require(model.PermRecruitmentWrite), h.Create)
+	recruitment.Get("/show/:id/score", auth.Require(model.PermScoreRead), h.ScoreList)
+	recruitment.Get("/show/:id/score/export/:format", auth.Require(model.PermScoreRead), h.ExportScore)
+	recruitment.Get("/show/:id/suggestion", auth.Require(model.PermRecruitmentRead), auth.Require(model.PermCandidateRead), auth.RequireEveryCandidate, h.Suggestions)
+	recruitment.Get("/:id/score/new", auth.Require(model.PermScoreWrite), h.NewScore)
+	recruitment.Post("/edit/:id/status", auth.Require(model.PermRecruitmentWrite), h.UpdateStatus)
+	recruitment.Post("/:id/score", auth.Require(model.PermScoreWrite), h.CreateScore)
+	recruitment.Get("/edit/:id", auth.Require(model.PermRecruitmentWrite), h.Edit)
+	recruitment.Post("/edit/:id", auth.Require(model.PermRecruitmentWrite), h.Update)
+	recruitment.Post("/delete/:id", auth.Require(model.PermRecruitmentWrite), h.Delete)
+	recruitment.Get("/:id/candidate/:candidate_id/score", auth.Require(model.PermScoreRead), auth.RequireAssignment, h.ShowScore)
+	recruitment.Get("/:id/candidate/:candidate_id/score/edit", auth.Require(model.PermScoreWrite), h.EditScore)
+	recruitment.Post("/:id/candidate/:candidate_id/score/edit", auth.Require(model.PermScoreWrite), h.UpdateScore)
+	recruitment.Post("/:id/candidate/:candidate_id/score/delete", auth.Require(model.PermScoreWrite), h.DeleteScore)
+	recruitment.Get("/:id/scorecard/new", auth.Require(model.PermScorecardWrite), h.NewScorecard)
+	recruitment.Post("/:id/scorecard", auth.Require(model.PermScorecardWrite), auth.RequireAssignment, h.CreateScorecard)
+	recruitment.Get("/:id/candidate/:candidate_id/scorecard", auth.Require(model.PermScoreRead), auth.RequireAssignment, h.ScorecardList)
+	recruitment.Post("/:id/candidate/:candidate_id/scorecard/:scorecard_id/delete", auth.Require(model.PermScoreWrite), h.DeleteScorecard)
 }
 
 func (h *recruitmentHandler) Index(ctx *fiber.Ctx) error {
-	result, err := h.recruitmentUsecase.GetRecruitments(ctx.Context())
+	var (
+		filter model.RecruitmentListRequest
+		err    error
+		ok     bool
+	)
+
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	result, meta, err := h.recruitmentUsecase.GetRecruitments(ctx.Context(), filter)
 	if err != nil {
 		return ctx.Render("error", nil)
 	}
 
+	utils.SetPageLinks(ctx, meta)
+
 	return ctx.Render(
 		"recruitment_index",
 		fiber.Map{
 			"recruitments": result,
+			"meta":         meta,
 		},
 	)
 }
 
 func (h *recruitmentHandler) New(ctx *fiber.Ctx) error {
 	now := time.Now().Format("2006-01-02")
-	jobs, err := h.jobUsecase.GetJobs(ctx.Context())
+	jobs, err := selectJobs(ctx.Context(), h.jobUsecase)
 	if err != nil {
 		return ctx.Render("error", nil)
 	}
@@ -114,26 +153,140 @@ func (h *recruitmentHandler) Create(ctx *fiber.Ctx) error {
 }
 
 func (h *recruitmentHandler) ScoreList(ctx *fiber.Ctx) error {
-	var id = ctx.Params("id")
+	var (
+		id     = ctx.Params("id")
+		filter model.CandidateScoreListRequest
+		err    error
+		ok     bool
+	)
+
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	filter.AssignedTo = middleware.AssignedTo(ctx)
 
-	result, err := h.recruitmentUsecase.GetRecruitmentScores(ctx.Context(), id)
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	result, meta, err := h.recruitmentUsecase.GetRecruitmentScores(ctx.Context(), id, filter)
 	if err != nil {
 		return ctx.Render("error", nil)
 	}
 
+	utils.SetPageLinks(ctx, meta)
+
+	exportPath := "/web/recruitment/show/" + id + "/score/export"
+
 	return ctx.Render(
 		"score_index",
 		fiber.Map{
 			"scores":        result,
+			"meta":          meta,
 			"recruitmentID": id,
+			"exportCSV":     utils.ExportURL(ctx, exportPath, "csv"),
+			"exportXLSX":    utils.ExportURL(ctx, exportPath, "xlsx"),
+			"exportPDF":     utils.ExportURL(ctx, exportPath, "pdf"),
 		},
 	)
 }
 
+// Suggestions lists the candidates matching the requirements of the job. A
+// job without requirements shows how to add them instead.
+func (h *recruitmentHandler) Suggestions(ctx *fiber.Ctx) error {
+	var (
+		id     = ctx.Params("id")
+		filter model.CandidateMatchListRequest
+		err    error
+		ok     bool
+	)
+
+	if err = ctx.QueryParser(&filter); err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(filter); !ok {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	recruitment, err := h.recruitmentUsecase.GetRecruitmentByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	data := fiber.Map{
+		"recruitment": recruitment,
+		"filter":      filter,
+	}
+
+	if recruitment.Job.Requirements.IsZero() {
+		return ctx.Render("recruitment_suggestion", data)
+	}
+
+	result, meta, err := h.recruitmentUsecase.GetSuggestedCandidates(ctx.Context(), id, filter)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	utils.SetPageLinks(ctx, meta)
+	data["matches"], data["meta"] = result, meta
+
+	return ctx.Render("recruitment_suggestion", data)
+}
+
+func (h *recruitmentHandler) ExportScore(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.CandidateScoreExportRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.QueryParser(&payload); err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	payload.AssignedTo = middleware.AssignedTo(ctx)
+
+	// the format is used by the stream writer after the handler returned, so
+	// it must not point into the reused request buffer
+	payload.Format = strings.Clone(ctx.Params("format"))
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	result, err := h.recruitmentUsecase.GetCandidateScoreReport(ctx.Context(), id, payload.CandidateScoreListRequest)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	return utils.SendAttachment(ctx, export.ScoreFilename(result, payload.Format), export.ContentType(payload.Format), func(w io.Writer) error {
+		return export.WriteScores(w, payload.Format, result)
+	})
+}
+
 func (h *recruitmentHandler) NewScore(ctx *fiber.Ctx) error {
 	var id = ctx.Params("id")
 
-	candidates, err := h.candidateUsecase.GetCandidates(ctx.Context())
+	candidates, err := selectCandidates(ctx.Context(), h.candidateUsecase, middleware.AssignedTo(ctx))
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	grades, err := h.scoreGrades(ctx, id)
 	if err != nil {
 		return ctx.Render("error", nil)
 	}
@@ -143,6 +296,7 @@ func (h *recruitmentHandler) NewScore(ctx *fiber.Ctx) error {
 		fiber.Map{
 			"candidates":    candidates,
 			"recruitmentID": id,
+			"grades":        grades,
 		})
 }
 
@@ -154,7 +308,7 @@ func (h *recruitmentHandler) UpdateStatus(ctx *fiber.Ctx) error {
 		err     error
 	)
 
-	result, err := h.recruitmentUsecase.GetRecruitments(ctx.Context())
+	result, _, err := h.recruitmentUsecase.GetRecruitments(ctx.Context(), model.RecruitmentListRequest{})
 	if err != nil {
 		return ctx.Render("error", nil)
 	}
@@ -198,7 +352,12 @@ func (h *recruitmentHandler) CreateScore(ctx *fiber.Ctx) error {
 		id      = ctx.Params("id")
 	)
 
-	candidates, err := h.candidateUsecase.GetCandidates(ctx.Context())
+	candidates, err := selectCandidates(ctx.Context(), h.candidateUsecase, middleware.AssignedTo(ctx))
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	grades, err := h.scoreGrades(ctx, id)
 	if err != nil {
 		return ctx.Render("error", nil)
 	}
@@ -208,6 +367,7 @@ func (h *recruitmentHandler) CreateScore(ctx *fiber.Ctx) error {
 			"error":         err.Error(),
 			"candidates":    candidates,
 			"recruitmentID": id,
+			"grades":        grades,
 		})
 	}
 
@@ -216,6 +376,7 @@ func (h *recruitmentHandler) CreateScore(ctx *fiber.Ctx) error {
 			"error":         err.Error(),
 			"candidates":    candidates,
 			"recruitmentID": id,
+			"grades":        grades,
 		})
 	}
 
@@ -225,8 +386,380 @@ func (h *recruitmentHandler) CreateScore(ctx *fiber.Ctx) error {
 			"error":         err.Error(),
 			"candidates":    candidates,
 			"recruitmentID": id,
+			"grades":        grades,
+		})
+	}
+
+	return ctx.Redirect("/web/recruitment/show/"+id+"/score", http.StatusFound)
+}
+
+func (h *recruitmentHandler) Edit(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	result, err := h.recruitmentUsecase.GetRecruitmentByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	jobs, err := selectJobs(ctx.Context(), h.jobUsecase)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	return ctx.Render(
+		"recruitment_edit",
+		fiber.Map{
+			"recruitment": result,
+			"jobs":        jobs,
+		},
+	)
+}
+
+func (h *recruitmentHandler) Update(ctx *fiber.Ctx) error {
+	var (
+		id      = ctx.Params("id")
+		payload model.RecruitmentUpdateRequest
+		err     error
+		ok      bool
+	)
+
+	result, err := h.recruitmentUsecase.GetRecruitmentByID(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	jobs, err := selectJobs(ctx.Context(), h.jobUsecase)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Render("recruitment_edit", fiber.Map{
+			"error":       err.Error(),
+			"recruitment": result,
+			"jobs":        jobs,
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Render("recruitment_edit", fiber.Map{
+			"error":       err.Error(),
+			"recruitment": result,
+			"jobs":        jobs,
+		})
+	}
+
+	_, err = h.recruitmentUsecase.UpdateRecruitment(ctx.Context(), id, payload)
+	if err != nil {
+		return ctx.Render("recruitment_edit", fiber.Map{
+			"error":       err.Error(),
+			"recruitment": result,
+			"jobs":        jobs,
+		})
+	}
+
+	return ctx.Redirect("/web/recruitment/show/"+id, http.StatusFound)
+}
+
+func (h *recruitmentHandler) Delete(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	err := h.recruitmentUsecase.DeleteRecruitment(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Redirect("/web/recruitment", http.StatusFound)
+}
+
+func (h *recruitmentHandler) ShowScore(ctx *fiber.Ctx) error {
+	var (
+		id          = ctx.Params("id")
+		candidateID = ctx.Params("candidate_id")
+	)
+
+	result, err := h.recruitmentUsecase.GetCandidateScoreByID(ctx.Context(), id, candidateID)
+	if err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Render(
+		"score_show",
+		fiber.Map{
+			"score": result,
+		})
+}
+
+func (h *recruitmentHandler) EditScore(ctx *fiber.Ctx) error {
+	var (
+		id          = ctx.Params("id")
+		candidateID = ctx.Params("candidate_id")
+	)
+
+	candidate, err := h.candidateUsecase.GetCandidateByID(ctx.Context(), candidateID)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	grades, err := h.scoreGrades(ctx, id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	return ctx.Render(
+		"score_edit",
+		fiber.Map{
+			"candidate":     candidate,
+			"recruitmentID": id,
+			"grades":        grades,
+		})
+}
+
+func (h *recruitmentHandler) UpdateScore(ctx *fiber.Ctx) error {
+	var (
+		id          = ctx.Params("id")
+		candidateID = ctx.Params("candidate_id")
+		payload     model.CandidateScoreUpdateRequest
+		err         error
+		ok          bool
+	)
+
+	candidate, err := h.candidateUsecase.GetCandidateByID(ctx.Context(), candidateID)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	grades, err := h.scoreGrades(ctx, id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Render("score_edit", fiber.Map{
+			"error":         err.Error(),
+			"candidate":     candidate,
+			"recruitmentID": id,
+			"grades":        grades,
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Render("score_edit", fiber.Map{
+			"error":         err.Error(),
+			"candidate":     candidate,
+			"recruitmentID": id,
+			"grades":        grades,
+		})
+	}
+
+	_, err = h.recruitmentUsecase.UpdateCandidateScore(ctx.Context(), id, candidateID, payload)
+	if err != nil {
+		return ctx.Render("score_edit", fiber.Map{
+			"error":         err.Error(),
+			"candidate":     candidate,
+			"recruitmentID": id,
+			"grades":        grades,
+		})
+	}
+
+	return ctx.Redirect("/web/recruitment/show/"+id+"/score", http.StatusFound)
+}
+
+func (h *recruitmentHandler) DeleteScore(ctx *fiber.Ctx) error {
+	var (
+		id          = ctx.Params("id")
+		candidateID = ctx.Params("candidate_id")
+	)
+
+	err := h.recruitmentUsecase.DeleteCandidateScore(ctx.Context(), id, candidateID)
+	if err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
 	return ctx.Redirect("/web/recruitment/show/"+id+"/score", http.StatusFound)
 }
+
+// scoreGrades returns the letter grades of the scoring profile used by a
+// recruitment, best grade first, for the score forms.
+func (h *recruitmentHandler) scoreGrades(ctx *fiber.Ctx, id string) ([]string, error) {
+	recruitment, err := h.recruitmentUsecase.GetRecruitmentByID(ctx.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := h.jobUsecase.GetScoringProfile(ctx.Context(), recruitment.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile.Grades(), nil
+}
+
+func (h *recruitmentHandler) NewScorecard(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	candidates, err := selectCandidates(ctx.Context(), h.candidateUsecase, middleware.AssignedTo(ctx))
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	grades, err := h.scoreGrades(ctx, id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	return ctx.Render(
+		"scorecard_new",
+		fiber.Map{
+			"candidates":    candidates,
+			"recruitmentID": id,
+			"grades":        grades,
+		})
+}
+
+func (h *recruitmentHandler) CreateScorecard(ctx *fiber.Ctx) error {
+	var (
+		payload     model.ScorecardCreateRequest
+		err         error
+		ok          bool
+		id          = ctx.Params("id")
+		candidateID = ctx.FormValue("CandidateID")
+	)
+
+	candidates, err := selectCandidates(ctx.Context(), h.candidateUsecase, middleware.AssignedTo(ctx))
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	grades, err := h.scoreGrades(ctx, id)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Render("scorecard_new", fiber.Map{
+			"error":         err.Error(),
+			"candidates":    candidates,
+			"recruitmentID": id,
+			"grades":        grades,
+		})
+	}
+
+	if user := middleware.CurrentUser(ctx); user.Role == model.RoleInterviewer {
+		payload.Interviewer = user.Name
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Render("scorecard_new", fiber.Map{
+			"error":         err.Error(),
+			"candidates":    candidates,
+			"recruitmentID": id,
+			"grades":        grades,
+		})
+	}
+
+	_, err = h.recruitmentUsecase.SubmitScorecard(ctx.Context(), id, candidateID, payload)
+	if err != nil {
+		return ctx.Render("scorecard_new", fiber.Map{
+			"error":         err.Error(),
+			"candidates":    candidates,
+			"recruitmentID": id,
+			"grades":        grades,
+		})
+	}
+
+	return ctx.Redirect("/web/recruitment/"+id+"/candidate/"+candidateID+"/scorecard", http.StatusFound)
+}
+
+func (h *recruitmentHandler) ScorecardList(ctx *fiber.Ctx) error {
+	var (
+		id          = ctx.Params("id")
+		candidateID = ctx.Params("candidate_id")
+	)
+
+	candidate, err := h.candidateUsecase.GetCandidateByID(ctx.Context(), candidateID)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	result, err := h.recruitmentUsecase.GetScorecards(ctx.Context(), id, candidateID)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	return ctx.Render(
+		"scorecard_index",
+		fiber.Map{
+			"summary":       result,
+			"candidate":     candidate,
+			"recruitmentID": id,
+		},
+	)
+}
+
+func (h *recruitmentHandler) DeleteScorecard(ctx *fiber.Ctx) error {
+	var (
+		id          = ctx.Params("id")
+		candidateID = ctx.Params("candidate_id")
+		scorecardID = ctx.Params("scorecard_id")
+	)
+
+	err := h.recruitmentUsecase.DeleteScorecard(ctx.Context(), id, candidateID, scorecardID)
+	if err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Redirect("/web/recruitment/"+id+"/candidate/"+candidateID+"/scorecard", http.StatusFound)
+}
+
+// selectJobs and selectCandidates fill the select inputs of the recruitment
+// and score forms. They read every page, so no option is left out.
+func selectJobs(ctx context.Context, jobUsecase usecase.JobUsecase) (*[]model.Job, error) {
+	var (
+		result []model.Job
+		filter = model.JobListRequest{Pagination: model.Pagination{Page: 1, Size: model.MaxPageSize}}
+	)
+
+	for {
+		jobs, meta, err := jobUsecase.GetJobs(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, *jobs...)
+		if !meta.HasNext() {
+			return &result, nil
+		}
+
+		filter.Page++
+	}
+}
+
+func selectCandidates(ctx context.Context, candidateUsecase usecase.CandidateUsecase, assignedTo string) (*[]model.Candidate, error) {
+	var (
+		result []model.Candidate
+		filter = model.CandidateListRequest{Pagination: model.Pagination{Page: 1, Size: model.MaxPageSize}, AssignedTo: assignedTo}
+	)
+
+	for {
+		candidates, meta, err := candidateUsecase.GetCandidates(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, *candidates...)
+		if !meta.HasNext() {
+			return &result, nil
+		}
+
+		filter.Page++
+	}
+}
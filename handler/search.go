@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"talentapp/middleware"
+	"talentapp/model"
+	"talentapp/usecase"
+	"talentapp/utils"
+)
+
+type searchHandler struct {
+	searchUsecase usecase.SearchUsecase
+}
+
+func NewSearchHandler(searchUsecase usecase.SearchUsecase) *searchHandler {
+	return &searchHandler{
+		searchUsecase: searchUsecase,
+	}
+}
+
+func (h *searchHandler) Router(app *fiber.App, auth *middleware.Auth) {
+	search := app.Group("/web/search", auth.Authenticate, auth.Require(model.PermCandidateRead), auth.Require(model.PermJobRead))
+	search.Get("", h.Index)
+}
+
+func (h *searchHandler) Index(ctx *fiber.Ctx) error {
+	var (
+		payload model.SearchRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.QueryParser(&payload); err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	payload.AssignedTo = middleware.AssignedTo(ctx)
+
+	// an empty search box shows the page without results
+	if payload.Query == "" {
+		return ctx.Render("search_index", fiber.Map{})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	result, err := h.searchUsecase.Search(ctx.Context(), payload)
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	return ctx.Render("search_index", fiber.Map{
+		"search": result,
+	})
+}
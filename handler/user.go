@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"strings"
+	"talentapp/middleware"
+	"talentapp/model"
+	"talentapp/usecase"
+	"talentapp/utils"
+	"time"
+)
+
+type userHandler struct {
+	userUsecase usecase.UserUsecase
+}
+
+func NewUserHandler(userUsecase usecase.UserUsecase) *userHandler {
+	return &userHandler{
+		userUsecase: userUsecase,
+	}
+}
+
+func (h *userHandler) Router(app *fiber.App, auth *middleware.Auth) {
+	app.Get("/web/login", h.LoginForm)
+	app.Post("/web/login", h.Login)
+	app.Post("/web/logout", h.Logout)
+
+	user := app.Group("/web/user", auth.Authenticate, auth.Require(model.PermUserManage))
+	user.Get("", h.Index)
+	user.Post("", h.Create)
+	user.Post("/delete/:id", h.Delete)
+}
+
+func (h *userHandler) LoginForm(ctx *fiber.Ctx) error {
+	return ctx.Render("login", fiber.Map{
+		"next": ctx.Query("next"),
+	})
+}
+
+func (h *userHandler) Login(ctx *fiber.Ctx) error {
+	var (
+		payload model.LoginRequest
+		err     error
+		ok      bool
+		next    = ctx.FormValue("next")
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return ctx.Render("login", fiber.Map{
+			"error": err.Error(),
+			"next":  next,
+		})
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return ctx.Render("login", fiber.Map{
+			"error": err.Error(),
+			"next":  next,
+		})
+	}
+
+	token, _, err := h.userUsecase.Login(ctx.Context(), payload)
+	if err != nil {
+		return ctx.Render("login", fiber.Map{
+			"error": err.Error(),
+			"next":  next,
+		})
+	}
+
+	ctx.Cookie(&fiber.Cookie{
+		Name:     middleware.SessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  time.Now().Add(usecase.SessionTTL),
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+
+	// Only redirect inside the app.
+	if !strings.HasPrefix(next, "/web/") {
+		next = "/web/recruitment"
+	}
+
+	return ctx.Redirect(next, http.StatusFound)
+}
+
+func (h *userHandler) Logout(ctx *fiber.Ctx) error {
+	if err := h.userUsecase.Logout(ctx.Context(), ctx.Cookies(middleware.SessionCookie)); err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	ctx.ClearCookie(middleware.SessionCookie)
+
+	return ctx.Redirect("/web/login", http.StatusFound)
+}
+
+func (h *userHandler) Index(ctx *fiber.Ctx) error {
+	return h.renderIndex(ctx, nil)
+}
+
+func (h *userHandler) Create(ctx *fiber.Ctx) error {
+	var (
+		payload model.UserCreateRequest
+		err     error
+		ok      bool
+	)
+
+	if err = ctx.BodyParser(&payload); err != nil {
+		return h.renderIndex(ctx, err)
+	}
+
+	if ok, err = utils.IsRequestValid(payload); !ok {
+		return h.renderIndex(ctx, err)
+	}
+
+	_, err = h.userUsecase.CreateUser(ctx.Context(), payload)
+	if err != nil {
+		return h.renderIndex(ctx, err)
+	}
+
+	return ctx.Redirect("/web/user", http.StatusFound)
+}
+
+func (h *userHandler) Delete(ctx *fiber.Ctx) error {
+	var id = ctx.Params("id")
+
+	err := h.userUsecase.DeleteUser(ctx.Context(), id)
+	if err != nil {
+		return ctx.Render("error", fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return ctx.Redirect("/web/user", http.StatusFound)
+}
+
+func (h *userHandler) renderIndex(ctx *fiber.Ctx, formErr error) error {
+	result, err := h.userUsecase.GetUsers(ctx.Context())
+	if err != nil {
+		return ctx.Render("error", nil)
+	}
+
+	data := fiber.Map{
+		"users": result,
+	}
+
+	if formErr != nil {
+		data["error"] = formErr.Error()
+	}
+
+	return ctx.Render("user_index", data)
+}
@@ -1,58 +1,246 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/template/html"
-	"github.com/golang-migrate/migrate/v4"
-	mysqlMigrate "github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	_ "github.com/mattes/migrate/source/file"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"talentapp/delivery"
 	"talentapp/driver/db/mysql"
+	"talentapp/driver/db/postgres"
+	"talentapp/driver/db/sqlite"
 	"talentapp/handler"
+	"talentapp/middleware"
+	"talentapp/model"
 	"talentapp/repository"
+	"talentapp/repository/memory"
+	"talentapp/scheduler"
+	"talentapp/storage"
 	"talentapp/usecase"
+	"talentapp/utils"
+	"time"
 )
 
-var db *sql.DB
+const (
+	// defaultSchedulerInterval is used when SCHEDULER_INTERVAL is not set.
+	defaultSchedulerInterval = time.Minute
 
-func init() {
-	err := godotenv.Load()
-	if err != nil {
+	// defaultSQLitePath is used when DB_DRIVER is sqlite and SQLITE_PATH is
+	// not set.
+	defaultSQLitePath = "talentapp.db"
+
+	// defaultStoragePath is used when STORAGE_DRIVER is local and
+	// STORAGE_PATH is not set.
+	defaultStoragePath = "uploads"
+
+	// bodyLimit leaves room for the form fields of a multipart upload next
+	// to the largest attachment.
+	bodyLimit = model.MaxAttachmentSize + 1<<20
+)
+
+// repositories are the stores of every record, backed by a SQL database or
+// by process memory.
+type repositories struct {
+	job                   repository.JobRepository
+	candidateScore        repository.CandidateScoreRepository
+	recruitment           repository.RecruitmentRepository
+	candidate             repository.CandidateRepository
+	scoringProfile        repository.ScoringProfileRepository
+	scorecard             repository.ScorecardRepository
+	application           repository.ApplicationRepository
+	lease                 repository.LeaseRepository
+	user                  repository.UserRepository
+	interviewerAssignment repository.InterviewerAssignmentRepository
+	audit                 repository.AuditRepository
+	attachment            repository.AttachmentRepository
+	candidateConsent      repository.CandidateConsentRepository
+	transactor            repository.Transactor
+}
+
+func newSQLRepositories(db *repository.DB) repositories {
+	return repositories{
+		job:                   repository.NewJobRepository(db),
+		candidateScore:        repository.NewCandidateScoreRepository(db),
+		recruitment:           repository.NewRecruitmentRepository(db),
+		candidate:             repository.NewCandidateRepository(db),
+		scoringProfile:        repository.NewScoringProfileRepository(db),
+		scorecard:             repository.NewScorecardRepository(db),
+		application:           repository.NewApplicationRepository(db),
+		lease:                 repository.NewLeaseRepository(db),
+		user:                  repository.NewUserRepository(db),
+		interviewerAssignment: repository.NewInterviewerAssignmentRepository(db),
+		audit:                 repository.NewAuditRepository(db),
+		attachment:            repository.NewAttachmentRepository(db),
+		candidateConsent:      repository.NewCandidateConsentRepository(db),
+		transactor:            repository.NewTransactor(db),
+	}
+}
+
+func newMemoryRepositories(store *memory.Store) repositories {
+	return repositories{
+		job:                   memory.NewJobRepository(store),
+		candidateScore:        memory.NewCandidateScoreRepository(store),
+		recruitment:           memory.NewRecruitmentRepository(store),
+		candidate:             memory.NewCandidateRepository(store),
+		scoringProfile:        memory.NewScoringProfileRepository(store),
+		scorecard:             memory.NewScorecardRepository(store),
+		application:           memory.NewApplicationRepository(store),
+		lease:                 memory.NewLeaseRepository(store),
+		user:                  memory.NewUserRepository(store),
+		interviewerAssignment: memory.NewInterviewerAssignmentRepository(store),
+		audit:                 memory.NewAuditRepository(store),
+		attachment:            memory.NewAttachmentRepository(store),
+		candidateConsent:      memory.NewCandidateConsentRepository(store),
+		transactor:            memory.NewTransactor(store),
+	}
+}
+
+// connectRepositories opens the repositories chosen by DB_DRIVER: mysql, the
+// default, postgres, sqlite for a database file at SQLITE_PATH, or memory for
+// records kept in process memory, without any database, that only live as
+// long as the app. The databases are migrated first.
+func connectRepositories() repositories {
+	switch driver := os.Getenv("DB_DRIVER"); driver {
+	case "", "mysql":
+		conn := mysql.ConnectDB()
+		if err := mysql.Migrate(conn, "file://migrations"); err != nil {
+			log.Fatal(err)
+		}
+
+		return newSQLRepositories(repository.NewDB(conn, repository.MySQL))
+	case "postgres":
+		conn := postgres.ConnectDB()
+		if err := postgres.Migrate(conn); err != nil {
+			log.Fatal(err)
+		}
+
+		return newSQLRepositories(repository.NewDB(conn, repository.Postgres))
+	case "sqlite":
+		path := os.Getenv("SQLITE_PATH")
+		if path == "" {
+			path = defaultSQLitePath
+		}
+
+		conn := sqlite.ConnectDB(path)
+		if err := sqlite.Migrate(conn); err != nil {
+			log.Fatal(err)
+		}
+
+		return newSQLRepositories(repository.NewDB(conn, repository.SQLite))
+	case "memory":
+		return newMemoryRepositories(memory.NewStore())
+	default:
+		log.Fatalf("unknown DB_DRIVER %q, expected mysql, postgres, sqlite or memory", driver)
+		return repositories{}
+	}
+}
+
+// connectStorage opens the file storage chosen by STORAGE_DRIVER: local, the
+// default, for a directory at STORAGE_PATH, or s3 for a bucket of an S3
+// compatible store such as MinIO.
+func connectStorage() storage.Storage {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		path := os.Getenv("STORAGE_PATH")
+		if path == "" {
+			path = defaultStoragePath
+		}
+
+		local, err := storage.NewLocal(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return local
+	case "s3":
+		useSSL, _ := strconv.ParseBool(os.Getenv("S3_USE_SSL"))
+		s3, err := storage.NewS3(context.Background(), storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			UseSSL:    useSSL,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return s3
+	default:
+		log.Fatalf("unknown STORAGE_DRIVER %q, expected local or s3", driver)
+		return nil
+	}
+}
+
+func main() {
+	if err := godotenv.Load(); err != nil {
 		panic(err)
 	}
 
-	db = mysql.ConnectDB()
+	repos := connectRepositories()
+	fileStorage := connectStorage()
 
-	dbInstance, err := mysqlMigrate.WithInstance(db, &mysqlMigrate.Config{})
-	if err != nil {
-		log.Fatal(err)
+	// the first admin is created from the environment on a fresh database
+	var admin *model.UserCreateRequest
+	if os.Getenv("ADMIN_EMAIL") != "" {
+		admin = &model.UserCreateRequest{
+			Name:     os.Getenv("ADMIN_NAME"),
+			Email:    os.Getenv("ADMIN_EMAIL"),
+			Password: os.Getenv("ADMIN_PASSWORD"),
+			Role:     model.RoleAdmin,
+		}
+		if ok, err := utils.IsRequestValid(*admin); !ok {
+			log.Fatalf("invalid ADMIN_NAME, ADMIN_EMAIL or ADMIN_PASSWORD: %v", err)
+		}
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://migrations",
-		"mysql",
-		dbInstance,
-	)
+	app, jobScheduler, err := newApp(repos, fileStorage, admin)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	if err = m.Up(); err != nil && err.Error() != "no change" {
+	jobScheduler.Start()
+
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+		<-quit
+
+		if err := app.Shutdown(); err != nil {
+			log.Println(err)
+		}
+	}()
+
+	err = app.Listen(":" + os.Getenv("PORT"))
+	jobScheduler.Stop()
+	if err != nil {
 		log.Fatal(err)
 	}
 }
 
-func main() {
+// newApp wires the usecases, deliveries and handlers over repos and returns
+// the app with the scheduler of its background jobs, not started yet. The
+// admin, when given, is created unless users exist already.
+func newApp(repos repositories, fileStorage storage.Storage, admin *model.UserCreateRequest) (*fiber.App, *scheduler.Scheduler, error) {
 	engine := html.New("./templates", ".html")
 
 	app := fiber.New(fiber.Config{
-		Views: engine,
+		Views:             engine,
+		PassLocalsToViews: true,
+		BodyLimit:         bodyLimit,
+		// params and form values outlive the request in the memory
+		// repositories, so they must not point into reused buffers
+		Immutable: true,
 	})
 
 	app.Use(cors.New())
@@ -65,43 +253,123 @@ func main() {
 		return c.SendString("Welcome to talentapp")
 	})
 
-	// dependencies
-	// repository
-	jobRepository := repository.NewJobRepository(db)
-	candidateScoreRepository := repository.NewCandidateScoreRepository(db)
-	recruitmentRepository := repository.NewRecruitmentRepository(db)
-	candidateRepository := repository.NewCandidateRepository(db)
-
 	// usecase
-	jobUsecase := usecase.NewJobUsecase(jobRepository)
+	jobUsecase := usecase.NewJobUsecase(repos.job, repos.recruitment, repos.scoringProfile, repos.transactor)
 	recruitmentUsecase := usecase.NewRecruitmentUsecase(
-		recruitmentRepository,
-		candidateScoreRepository,
-		candidateRepository,
-		jobRepository,
+		repos.recruitment,
+		repos.candidateScore,
+		repos.candidate,
+		repos.job,
+		repos.scoringProfile,
+		repos.scorecard,
+		repos.application,
+		repos.transactor,
+	)
+	candidateUsecase := usecase.NewCandidateUsecase(
+		repos.candidate,
+		repos.candidateScore,
+		repos.scorecard,
+		repos.application,
+		repos.scoringProfile,
+		repos.attachment,
+		repos.candidateConsent,
+		repos.interviewerAssignment,
+		repos.transactor,
 	)
-	candidateUsecase := usecase.NewCandidateUsecase(candidateRepository)
+	applicationUsecase := usecase.NewApplicationUsecase(repos.application, repos.recruitment, repos.candidate, repos.interviewerAssignment, repos.transactor)
+	userUsecase := usecase.NewUserUsecase(repos.user, repos.interviewerAssignment, repos.recruitment, repos.candidate)
+	auditUsecase := usecase.NewAuditUsecase(repos.audit)
+	searchUsecase := usecase.NewSearchUsecase(repos.candidate, repos.job)
+	attachmentUsecase := usecase.NewAttachmentUsecase(repos.attachment, repos.candidate, repos.application, fileStorage, repos.transactor)
+	privacyUsecase := usecase.NewPrivacyUsecase(
+		repos.candidate,
+		repos.candidateConsent,
+		repos.candidateScore,
+		repos.scorecard,
+		repos.application,
+		repos.attachment,
+		repos.audit,
+		fileStorage,
+		repos.transactor,
+	)
+
+	if admin != nil {
+		if err := userUsecase.EnsureAdmin(context.Background(), *admin); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// middleware
+	apiAuth := middleware.NewAPIAuth(userUsecase)
+	webAuth := middleware.NewWebAuth(userUsecase)
 
 	// delivery
 	jobDelivery := delivery.NewJobDelivery(jobUsecase)
 	recruitmentDelivery := delivery.NewRecruitmentDelivery(recruitmentUsecase)
 	candidateDelivery := delivery.NewCandidateDelivery(candidateUsecase)
+	applicationDelivery := delivery.NewApplicationDelivery(applicationUsecase)
+	userDelivery := delivery.NewUserDelivery(userUsecase)
+	auditDelivery := delivery.NewAuditDelivery(auditUsecase)
+	searchDelivery := delivery.NewSearchDelivery(searchUsecase)
+	attachmentDelivery := delivery.NewAttachmentDelivery(attachmentUsecase)
+	privacyDelivery := delivery.NewPrivacyDelivery(privacyUsecase)
 
 	// handler
 	recruitmentHandler := handler.NewRecruitmentHandler(recruitmentUsecase, jobUsecase, candidateUsecase)
 	jobHandler := handler.NewJobHandler(jobUsecase)
-	candidateHandler := handler.NewCandidateHandler(candidateUsecase)
+	candidateHandler := handler.NewCandidateHandler(candidateUsecase, attachmentUsecase, privacyUsecase)
+	applicationHandler := handler.NewApplicationHandler(applicationUsecase, recruitmentUsecase, candidateUsecase)
+	userHandler := handler.NewUserHandler(userUsecase)
+	auditHandler := handler.NewAuditHandler(auditUsecase)
+	searchHandler := handler.NewSearchHandler(searchUsecase)
 
 	// router
-	jobDelivery.Router(app)
-	recruitmentDelivery.Router(app)
-	candidateDelivery.Router(app)
-	recruitmentHandler.Router(app)
-	jobHandler.Router(app)
-	candidateHandler.Router(app)
-
-	err := app.Listen(":" + os.Getenv("PORT"))
+	jobDelivery.Router(app, apiAuth)
+	recruitmentDelivery.Router(app, apiAuth)
+	candidateDelivery.Router(app, apiAuth)
+	applicationDelivery.Router(app, apiAuth)
+	userDelivery.Router(app, apiAuth)
+	auditDelivery.Router(app, apiAuth)
+	searchDelivery.Router(app, apiAuth)
+	attachmentDelivery.Router(app, apiAuth)
+	privacyDelivery.Router(app, apiAuth)
+	recruitmentHandler.Router(app, webAuth)
+	jobHandler.Router(app, webAuth)
+	candidateHandler.Router(app, webAuth)
+	applicationHandler.Router(app, webAuth)
+	userHandler.Router(app, webAuth)
+	auditHandler.Router(app, webAuth)
+	searchHandler.Router(app, webAuth)
+
+	// scheduler
+	jobScheduler := scheduler.NewScheduler(repos.lease, schedulerHolder())
+	jobScheduler.Register("close-expired-recruitments", schedulerInterval(), func(ctx context.Context) error {
+		closed, err := recruitmentUsecase.CloseExpiredRecruitments(ctx)
+		if closed > 0 {
+			log.Printf("closed %d expired recruitment(s)", closed)
+		}
+
+		return err
+	})
+
+	return app, jobScheduler, nil
+}
+
+func schedulerInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("SCHEDULER_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return defaultSchedulerInterval
+	}
+
+	return interval
+}
+
+// schedulerHolder identifies this app instance when it holds a lease.
+func schedulerHolder() string {
+	hostname, err := os.Hostname()
 	if err != nil {
-		log.Fatal(err)
+		hostname = "talentapp"
 	}
+
+	return fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
 }
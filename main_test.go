@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"talentapp/model"
+	"talentapp/repository/memory"
+	"talentapp/storage"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	testAdminEmail    = "admin@talentapp.test"
+	testAdminPassword = "password"
+)
+
+// client calls the app of a test as one user, through its bearer token.
+type client struct {
+	t     *testing.T
+	app   *fiber.App
+	token string
+}
+
+// newTestApp returns the app over fresh memory repositories, signed in as
+// the admin.
+func newTestApp(t *testing.T) *client {
+	t.Helper()
+
+	fileStorage, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app, _, err := newApp(newMemoryRepositories(memory.NewStore()), fileStorage, &model.UserCreateRequest{
+		Name:     "Admin",
+		Email:    testAdminEmail,
+		Password: testAdminPassword,
+		Role:     model.RoleAdmin,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin := &client{t: t, app: app}
+	admin.signIn(testAdminEmail, testAdminPassword)
+
+	return admin
+}
+
+// signIn replaces the token of c with a new one of the user.
+func (c *client) signIn(email, password string) {
+	c.t.Helper()
+
+	var result struct {
+		Token string `json:"token"`
+	}
+
+	c.token = ""
+	c.expect(http.MethodPost, "/auth/token", map[string]string{"email": email, "password": password, "name": "test"}, http.StatusCreated, &result)
+	c.token = result.Token
+}
+
+// as returns a client of the app signed in as another user.
+func (c *client) as(email, password string) *client {
+	other := &client{t: c.t, app: c.app}
+	other.signIn(email, password)
+
+	return other
+}
+
+// call sends body as JSON and returns the status with the raw response.
+func (c *client) call(method, path string, body interface{}) (int, []byte) {
+	c.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+
+		reader = bytes.NewReader(payload)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	res, err := c.app.Test(req, -1)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	return res.StatusCode, raw
+}
+
+// expect fails the test unless the call answers status, and decodes the
+// data of the response into result when given.
+func (c *client) expect(method, path string, body interface{}, status int, result interface{}) {
+	c.t.Helper()
+
+	code, raw := c.call(method, path, body)
+	if code != status {
+		c.t.Fatalf("%s %s: got status %d, want %d: %s", method, path, code, status, raw)
+	}
+
+	if result == nil {
+		return
+	}
+
+	var response struct {
+		Data json.RawMessage `json:"data"`
+	}
+
+	if err := json.Unmarshal(raw, &response); err != nil {
+		c.t.Fatalf("%s %s: %v: %s", method, path, err, raw)
+	}
+
+	if err := json.Unmarshal(response.Data, result); err != nil {
+		c.t.Fatalf("%s %s: %v: %s", method, path, err, raw)
+	}
+}
+
+func (c *client) postJob(position string) string {
+	var job model.Job
+	c.expect(http.MethodPost, "/job", map[string]interface{}{
+		"position":        position,
+		"department":      "Engineering",
+		"requester":       "Hiring Manager",
+		"job_description": "Builds the product",
+		"criteria":        "Go",
+	}, http.StatusCreated, &job)
+
+	return job.ID
+}
+
+func (c *client) postRecruitment(jobID string) string {
+	var recruitment model.Recruitment
+	c.expect(http.MethodPost, "/recruitment", map[string]interface{}{"job_id": jobID, "deadline": "2099-01-01"}, http.StatusCreated, &recruitment)
+
+	return recruitment.ID
+}
+
+func (c *client) postCandidate(name string, experience int) string {
+	var candidate model.Candidate
+	c.expect(http.MethodPost, "/candidate", map[string]interface{}{
+		"name":                name,
+		"address":             "Main Street 1",
+		"experience":          experience,
+		"willing_to_relocate": "yes",
+	}, http.StatusCreated, &candidate)
+
+	return candidate.ID
+}
+
+func (c *client) postScore(recruitmentID, candidateID, attitude, skill string, experience int) model.CandidateScore {
+	var score model.CandidateScore
+	c.expect(http.MethodPost, "/recruitment/"+recruitmentID+"/score", map[string]interface{}{
+		"candidate_id":              candidateID,
+		"willing_to_relocate_score": "yes",
+		"attitude_score":            attitude,
+		"skill_score":               skill,
+		"experience":                experience,
+	}, http.StatusCreated, &score)
+
+	return score
+}
+
+func TestAuthentication(t *testing.T) {
+	admin := newTestApp(t)
+
+	anonymous := &client{t: t, app: admin.app}
+	anonymous.expect(http.MethodGet, "/candidate", nil, http.StatusUnauthorized, nil)
+
+	var me model.User
+	admin.expect(http.MethodGet, "/auth/me", nil, http.StatusOK, &me)
+	if me.Email != testAdminEmail || me.Role != model.RoleAdmin {
+		t.Errorf("got %s as %s, want the admin", me.Email, me.Role)
+	}
+
+	code, _ := anonymous.call(http.MethodPost, "/auth/token", map[string]string{"email": testAdminEmail, "password": "wrong password", "name": "test"})
+	if code != http.StatusUnauthorized {
+		t.Errorf("got status %d for a wrong password, want %d", code, http.StatusUnauthorized)
+	}
+}
+
+func TestScoringAndRanking(t *testing.T) {
+	admin := newTestApp(t)
+
+	recruitmentID := admin.postRecruitment(admin.postJob("Backend Developer"))
+	ann := admin.postCandidate("Ann Archer", 5)
+	bob := admin.postCandidate("Bob Baker", 1)
+
+	if score := admin.postScore(recruitmentID, ann, "A", "A", 5); score.ScoringProfileVersion != 1 {
+		t.Errorf("got scoring profile version %d, want 1", score.ScoringProfileVersion)
+	}
+
+	// a candidate without experience is accepted, but a grade must be on
+	// the scale of the scoring profile
+	cal := admin.postCandidate("Cal Carter", 0)
+	admin.expect(http.MethodPost, "/recruitment/"+recruitmentID+"/score", map[string]interface{}{
+		"candidate_id":              cal,
+		"willing_to_relocate_score": "yes",
+		"attitude_score":            "Z",
+		"skill_score":               "A",
+		"experience":                0,
+	}, http.StatusUnprocessableEntity, nil)
+	admin.expect(http.MethodPost, "/recruitment/"+recruitmentID+"/candidate/"+cal+"/scorecard",
+		map[string]string{"interviewer": "Ivy Interviewer", "attitude_score": "A", "skill_score": "Z"}, http.StatusUnprocessableEntity, nil)
+
+	admin.postScore(recruitmentID, bob, "C", "B", 1)
+
+	// a candidate is scored once per recruitment
+	admin.expect(http.MethodPost, "/recruitment/"+recruitmentID+"/score", map[string]interface{}{
+		"candidate_id":              ann,
+		"willing_to_relocate_score": "yes",
+		"attitude_score":            "A",
+		"skill_score":               "A",
+		"experience":                5,
+	}, http.StatusConflict, nil)
+
+	var scores []model.CandidateScore
+	admin.expect(http.MethodGet, "/recruitment/"+recruitmentID+"/score", nil, http.StatusOK, &scores)
+	if len(scores) != 2 {
+		t.Fatalf("got %d scores, want 2", len(scores))
+	}
+
+	for i, want := range []string{ann, bob} {
+		if scores[i].CandidateID != want || scores[i].Rank != i+1 {
+			t.Errorf("score %d: got candidate %s ranked %d, want %s ranked %d", i, scores[i].CandidateID, scores[i].Rank, want, i+1)
+		}
+	}
+
+	var history model.CandidateHistory
+	admin.expect(http.MethodGet, "/candidate/"+bob+"/history", nil, http.StatusOK, &history)
+	if len(history.Recruitments) != 1 || history.Recruitments[0].Score == nil || history.Recruitments[0].Score.Rank != 2 {
+		t.Errorf("got history %+v, want one recruitment ranked 2", history.Recruitments)
+	}
+
+	admin.expect(http.MethodDelete, "/recruitment/"+recruitmentID+"/candidate/"+bob+"/score", nil, http.StatusOK, nil)
+	admin.expect(http.MethodGet, "/recruitment/"+recruitmentID+"/candidate/"+bob+"/score", nil, http.StatusNotFound, nil)
+}
+
+func TestDeleteInUse(t *testing.T) {
+	admin := newTestApp(t)
+
+	jobID := admin.postJob("Data Engineer")
+	recruitmentID := admin.postRecruitment(jobID)
+	candidateID := admin.postCandidate("Cora Cole", 3)
+	admin.postScore(recruitmentID, candidateID, "B", "B", 3)
+
+	admin.expect(http.MethodDelete, "/job/"+jobID, nil, http.StatusConflict, nil)
+	admin.expect(http.MethodDelete, "/candidate/"+candidateID, nil, http.StatusConflict, nil)
+
+	admin.expect(http.MethodDelete, "/recruitment/"+recruitmentID+"/candidate/"+candidateID+"/score", nil, http.StatusOK, nil)
+	admin.expect(http.MethodDelete, "/recruitment/"+recruitmentID, nil, http.StatusOK, nil)
+	admin.expect(http.MethodDelete, "/job/"+jobID, nil, http.StatusOK, nil)
+	admin.expect(http.MethodGet, "/job/"+jobID, nil, http.StatusNotFound, nil)
+}
+
+func TestCandidateSearch(t *testing.T) {
+	admin := newTestApp(t)
+
+	admin.postCandidate("Dana Dunn", 2)
+	admin.postCandidate("Eli Evans", 7)
+	admin.postCandidate("Danny Drake", 4)
+
+	var candidates []model.Candidate
+	admin.expect(http.MethodGet, "/candidate?q=dan&sort=experience", nil, http.StatusOK, &candidates)
+
+	var names []string
+	for _, candidate := range candidates {
+		names = append(names, candidate.Name)
+	}
+
+	if fmt.Sprint(names) != "[Dana Dunn Danny Drake]" {
+		t.Errorf("got %v, want [Dana Dunn Danny Drake]", names)
+	}
+}
+
+func TestApplicationBoard(t *testing.T) {
+	admin := newTestApp(t)
+
+	recruitmentID := admin.postRecruitment(admin.postJob("Designer"))
+	candidateID := admin.postCandidate("Finn Ford", 2)
+
+	var application model.Application
+	admin.expect(http.MethodPost, "/recruitment/"+recruitmentID+"/application", map[string]string{"candidate_id": candidateID}, http.StatusCreated, &application)
+	if application.Stage != model.StageApplied {
+		t.Errorf("got stage %s, want %s", application.Stage, model.StageApplied)
+	}
+
+	admin.expect(http.MethodPost, "/recruitment/"+recruitmentID+"/application", map[string]string{"candidate_id": candidateID}, http.StatusConflict, nil)
+	admin.expect(http.MethodPut, "/application/"+application.ID+"/stage", map[string]string{"stage": model.StageScreening, "note": "looks good"}, http.StatusOK, nil)
+
+	admin.expect(http.MethodGet, "/application/"+application.ID, nil, http.StatusOK, &application)
+	if application.Stage != model.StageScreening {
+		t.Errorf("got stage %s, want %s", application.Stage, model.StageScreening)
+	}
+}
+
+func TestInterviewerScope(t *testing.T) {
+	admin := newTestApp(t)
+
+	recruitmentID := admin.postRecruitment(admin.postJob("Support Engineer"))
+	assigned := admin.postCandidate("Gail Grant", 3)
+	other := admin.postCandidate("Hugo Hart", 3)
+
+	var interviewer model.User
+	admin.expect(http.MethodPost, "/user", map[string]string{
+		"name":     "Ivy Interviewer",
+		"email":    "ivy@talentapp.test",
+		"password": "interviewer",
+		"role":     model.RoleInterviewer,
+	}, http.StatusCreated, &interviewer)
+
+	admin.expect(http.MethodPost, "/recruitment/"+recruitmentID+"/interviewer", map[string]string{"user_id": interviewer.ID, "candidate_id": assigned}, http.StatusCreated, nil)
+
+	ivy := admin.as("ivy@talentapp.test", "interviewer")
+	ivy.expect(http.MethodGet, "/candidate/"+assigned, nil, http.StatusOK, nil)
+
+	if code, raw := ivy.call(http.MethodGet, "/candidate/"+other, nil); code == http.StatusOK {
+		t.Errorf("an interviewer read a candidate they are not assigned to: %s", raw)
+	}
+}
+
+func TestMergeAndErase(t *testing.T) {
+	admin := newTestApp(t)
+
+	recruitmentID := admin.postRecruitment(admin.postJob("Analyst"))
+	kept := admin.postCandidate("Jo Jones", 4)
+	again := map[string]interface{}{"name": "Jo Jones", "address": "Main Street 1", "experience": 4, "willing_to_relocate": "yes"}
+	admin.expect(http.MethodPost, "/candidate", again, http.StatusConflict, nil)
+
+	var candidate model.Candidate
+	again["allow_duplicate"] = true
+	admin.expect(http.MethodPost, "/candidate", again, http.StatusCreated, &candidate)
+	duplicate := candidate.ID
+
+	admin.postScore(recruitmentID, duplicate, "A", "B", 4)
+
+	admin.expect(http.MethodPost, "/candidate/"+kept+"/merge", map[string]string{"duplicate_id": duplicate}, http.StatusOK, nil)
+	admin.expect(http.MethodGet, "/candidate/"+duplicate, nil, http.StatusNotFound, nil)
+	admin.expect(http.MethodGet, "/recruitment/"+recruitmentID+"/candidate/"+kept+"/score", nil, http.StatusOK, nil)
+
+	admin.expect(http.MethodPost, "/candidate/"+kept+"/erase", nil, http.StatusOK, nil)
+
+	admin.expect(http.MethodGet, "/candidate/"+kept, nil, http.StatusOK, &candidate)
+	if !candidate.Erased() || candidate.Name == "Jo Jones" {
+		t.Errorf("got %+v, want the candidate erased", candidate)
+	}
+
+	_, raw := admin.call(http.MethodGet, "/audit/candidate/"+kept, nil)
+	if bytes.Contains(raw, []byte("Jo Jones")) {
+		t.Errorf("the audit log still holds the erased name: %s", raw)
+	}
+}
@@ -0,0 +1,194 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"talentapp/model"
+	"talentapp/usecase"
+)
+
+const (
+	// UserKey is the key of the authenticated user in the request locals.
+	// Locals are passed to the views, so templates read it as .currentUser.
+	UserKey = model.UserContextKey
+
+	// SessionCookie holds the session token of the web UI.
+	SessionCookie = "talentapp_session"
+)
+
+// Auth authenticates requests and checks the permissions of the user. The
+// API flavour reads a bearer token and answers with JSON, the web flavour
+// reads the session cookie and redirects to the login page.
+type Auth struct {
+	userUsecase usecase.UserUsecase
+	web         bool
+}
+
+func NewAPIAuth(userUsecase usecase.UserUsecase) *Auth {
+	return &Auth{userUsecase: userUsecase}
+}
+
+func NewWebAuth(userUsecase usecase.UserUsecase) *Auth {
+	return &Auth{userUsecase: userUsecase, web: true}
+}
+
+// CurrentUser returns the user authenticated by Authenticate.
+func CurrentUser(ctx *fiber.Ctx) *model.User {
+	user, _ := ctx.Locals(UserKey).(*model.User)
+	return user
+}
+
+// Authenticate resolves the user of the request. It runs once even when a
+// route is covered by more than one group that authenticates.
+func (a *Auth) Authenticate(ctx *fiber.Ctx) error {
+	if CurrentUser(ctx) != nil {
+		return ctx.Next()
+	}
+
+	var (
+		kind   = model.TokenAPI
+		header = ctx.Get(fiber.HeaderAuthorization)
+		token  string
+	)
+
+	if strings.HasPrefix(header, "Bearer ") {
+		token = strings.TrimPrefix(header, "Bearer ")
+	}
+
+	if a.web {
+		kind = model.TokenSession
+		token = ctx.Cookies(SessionCookie)
+	}
+
+	if token == "" {
+		return a.unauthenticated(ctx, usecase.ErrInvalidCredentials)
+	}
+
+	user, err := a.userUsecase.Authenticate(ctx.Context(), kind, token)
+	if err != nil {
+		return a.unauthenticated(ctx, err)
+	}
+
+	ctx.Locals(UserKey, user)
+
+	return ctx.Next()
+}
+
+// Require lets the request through when the role of the user grants
+// permission.
+func (a *Auth) Require(permission string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		user := CurrentUser(ctx)
+		if user == nil || !user.Can(permission) {
+			return a.forbidden(ctx, "your role is not allowed to do this")
+		}
+
+		return ctx.Next()
+	}
+}
+
+// AssignedTo returns the id of the current user when they only read the
+// candidates they are assigned to, for the lists to keep those, and "" when
+// they read every candidate.
+func AssignedTo(ctx *fiber.Ctx) string {
+	user := CurrentUser(ctx)
+	if user == nil || !user.AssignedOnly() {
+		return ""
+	}
+
+	return user.ID
+}
+
+// RequireAssignment lets interviewers through only for the candidates they
+// are assigned to. The recruitment comes from the id param and the
+// candidate from the candidate_id param or the CandidateID form field.
+func (a *Auth) RequireAssignment(ctx *fiber.Ctx) error {
+	return a.requireAssigned(ctx, func(userID string) (bool, error) {
+		candidateID := ctx.Params("candidate_id")
+		if candidateID == "" {
+			candidateID = ctx.FormValue("CandidateID")
+		}
+
+		return a.userUsecase.IsAssigned(ctx.Context(), ctx.Params("id"), candidateID, userID)
+	})
+}
+
+// RequireCandidateAssignment lets interviewers through only for the
+// candidates they are assigned to in any recruitment. The candidate comes
+// from the id param.
+func (a *Auth) RequireCandidateAssignment(ctx *fiber.Ctx) error {
+	return a.requireAssigned(ctx, func(userID string) (bool, error) {
+		return a.userUsecase.IsAssignedToCandidate(ctx.Context(), ctx.Params("id"), userID)
+	})
+}
+
+// RequireApplicationAssignment lets interviewers through only for the
+// applications of the candidates they are assigned to in its recruitment.
+// The application comes from the id param.
+func (a *Auth) RequireApplicationAssignment(ctx *fiber.Ctx) error {
+	return a.requireAssigned(ctx, func(userID string) (bool, error) {
+		return a.userUsecase.IsAssignedToApplication(ctx.Context(), ctx.Params("id"), userID)
+	})
+}
+
+// RequireEveryCandidate keeps interviewers out of the pages that compare a
+// candidate with the whole pool, such as duplicates and suggestions, which
+// cannot be narrowed to their assignments.
+func (a *Auth) RequireEveryCandidate(ctx *fiber.Ctx) error {
+	user := CurrentUser(ctx)
+	if user == nil || user.AssignedOnly() {
+		return a.forbidden(ctx, "you can only see the candidates you are assigned to")
+	}
+
+	return ctx.Next()
+}
+
+// requireAssigned lets through the users who read every candidate, and the
+// others when assigned reports they are assigned.
+func (a *Auth) requireAssigned(ctx *fiber.Ctx, assigned func(userID string) (bool, error)) error {
+	user := CurrentUser(ctx)
+	if user == nil {
+		return a.forbidden(ctx, "your role is not allowed to do this")
+	}
+
+	if !user.AssignedOnly() {
+		return ctx.Next()
+	}
+
+	ok, err := assigned(user.ID)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return a.forbidden(ctx, "you are not assigned to this candidate")
+	}
+
+	return ctx.Next()
+}
+
+func (a *Auth) unauthenticated(ctx *fiber.Ctx, err error) error {
+	if a.web {
+		return ctx.Redirect("/web/login?next="+url.QueryEscape(ctx.OriginalURL()), http.StatusFound)
+	}
+
+	return ctx.Status(http.StatusUnauthorized).JSON(fiber.Map{
+		"message": "unauthorized",
+		"error":   err.Error(),
+	})
+}
+
+func (a *Auth) forbidden(ctx *fiber.Ctx, reason string) error {
+	if a.web {
+		return ctx.Status(http.StatusForbidden).Render("error", fiber.Map{
+			"error": reason,
+		})
+	}
+
+	return ctx.Status(http.StatusForbidden).JSON(fiber.Map{
+		"message": "forbidden",
+		"error":   reason,
+	})
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	StageApplied   = "applied"
+	StageScreening = "screening"
+	StageInterview = "interview"
+	StageOffer     = "offer"
+	StageHired     = "hired"
+	StageRejected  = "rejected"
+	StageWithdrawn = "withdrawn"
+)
+
+// Stages lists every application stage in pipeline order.
+var Stages = []string{
+	StageApplied,
+	StageScreening,
+	StageInterview,
+	StageOffer,
+	StageHired,
+	StageRejected,
+	StageWithdrawn,
+}
+
+// IsFinalStage reports whether stage ends an application.
+func IsFinalStage(stage string) bool {
+	return stage == StageHired || stage == StageRejected || stage == StageWithdrawn
+}
+
+// stageTransitions holds the stages an application may move to from each
+// stage. Hired, rejected and withdrawn are final.
+var stageTransitions = map[string][]string{
+	StageApplied:   {StageScreening, StageRejected, StageWithdrawn},
+	StageScreening: {StageInterview, StageRejected, StageWithdrawn},
+	StageInterview: {StageOffer, StageRejected, StageWithdrawn},
+	StageOffer:     {StageHired, StageRejected, StageWithdrawn},
+}
+
+// Application links a candidate to a recruitment and tracks the stage of the
+// candidate in its pipeline.
+type Application struct {
+	ID            string       `json:"id"`
+	RecruitmentID string       `json:"recruitment_id"`
+	CandidateID   string       `json:"candidate_id"`
+	Candidate     *Candidate   `json:"candidate,omitempty"`
+	Recruitment   *Recruitment `json:"recruitment,omitempty"`
+	Stage         string       `json:"stage"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+type ApplicationStageEvent struct {
+	ID            string    `json:"id"`
+	ApplicationID string    `json:"application_id"`
+	FromStage     string    `json:"from_stage"`
+	ToStage       string    `json:"to_stage"`
+	ChangedBy     string    `json:"changed_by"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ApplicationBoard groups the applications of a recruitment by stage.
+type ApplicationBoard struct {
+	RecruitmentID string             `json:"recruitment_id"`
+	Columns       []ApplicationStage `json:"columns"`
+}
+
+type ApplicationStage struct {
+	Stage        string        `json:"stage"`
+	Applications []Application `json:"applications"`
+}
+
+// ChangedBy of the requests below is filled with the authenticated user,
+// never from the request body.
+type (
+	ApplicationCreateRequest struct {
+		CandidateID string `json:"candidate_id" validate:"required"`
+		ChangedBy   string `json:"-" validate:"required"`
+	}
+
+	ApplicationMoveRequest struct {
+		Stage     string `json:"stage" validate:"required,oneof=applied screening interview offer hired rejected withdrawn"`
+		ChangedBy string `json:"-" validate:"required"`
+		Note      string `json:"note"`
+	}
+)
+
+// CanMoveTo reports whether the application may move to stage.
+func (a Application) CanMoveTo(stage string) bool {
+	for _, next := range stageTransitions[a.Stage] {
+		if next == stage {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextStages returns the stages the application may move to.
+func (a Application) NextStages() []string {
+	return stageTransitions[a.Stage]
+}
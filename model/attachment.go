@@ -0,0 +1,56 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	AttachmentKindCV          = "cv"
+	AttachmentKindPortfolio   = "portfolio"
+	AttachmentKindCertificate = "certificate"
+)
+
+// AttachmentKinds lists every kind of document a candidate can upload.
+var AttachmentKinds = []string{AttachmentKindCV, AttachmentKindPortfolio, AttachmentKindCertificate}
+
+// MaxAttachmentSize is the largest file accepted as an attachment, in bytes.
+const MaxAttachmentSize = 10 << 20
+
+// Attachment is a document of a candidate, optionally uploaded for one of
+// the candidate's applications. The content is kept in storage under
+// StorageKey, which is derived from SHA256 so identical files are stored
+// once.
+type Attachment struct {
+	ID            string    `json:"id"`
+	CandidateID   string    `json:"candidate_id"`
+	ApplicationID string    `json:"application_id,omitempty"`
+	Kind          string    `json:"kind"`
+	Filename      string    `json:"filename"`
+	ContentType   string    `json:"content_type"`
+	Size          int64     `json:"size"`
+	SHA256        string    `json:"sha256"`
+	StorageKey    string    `json:"-"`
+	UploadedBy    string    `json:"uploaded_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DisplaySize returns Size for people to read, as in "1.5 MB".
+func (a Attachment) DisplaySize() string {
+	switch {
+	case a.Size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(a.Size)/(1<<20))
+	case a.Size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(a.Size)/(1<<10))
+	}
+
+	return fmt.Sprintf("%d B", a.Size)
+}
+
+// AttachmentCreateRequest describes an upload. UploadedBy is filled with the
+// authenticated user, never from the form.
+type AttachmentCreateRequest struct {
+	Kind          string `form:"kind" validate:"required,oneof=cv portfolio certificate"`
+	ApplicationID string `form:"application_id"`
+	UploadedBy    string `form:"-" validate:"required"`
+}
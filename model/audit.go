@@ -0,0 +1,91 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	EntityCandidate             = "candidate"
+	EntityJob                   = "job"
+	EntityRecruitment           = "recruitment"
+	EntityCandidateScore        = "candidate_score"
+	EntityScoringProfile        = "scoring_profile"
+	EntityScorecard             = "scorecard"
+	EntityApplication           = "application"
+	EntityInterviewerAssignment = "interviewer_assignment"
+	EntityUser                  = "user"
+	EntityAttachment            = "attachment"
+	EntityCandidateConsent      = "candidate_consent"
+)
+
+// EntityTypes lists every audited entity type.
+var EntityTypes = []string{
+	EntityCandidate,
+	EntityJob,
+	EntityRecruitment,
+	EntityCandidateScore,
+	EntityScoringProfile,
+	EntityScorecard,
+	EntityApplication,
+	EntityInterviewerAssignment,
+	EntityUser,
+	EntityAttachment,
+	EntityCandidateConsent,
+}
+
+const (
+	ActionCreate       = "create"
+	ActionUpdate       = "update"
+	ActionDelete       = "delete"
+	ActionStatusChange = "status_change"
+	ActionMerge        = "merge"
+	ActionErase        = "erase"
+)
+
+// Actions lists every audited action.
+var Actions = []string{ActionCreate, ActionUpdate, ActionDelete, ActionStatusChange, ActionMerge, ActionErase}
+
+// ActorSystem is recorded for changes made without a logged in user, such
+// as the scheduler closing expired recruitments.
+const ActorSystem = "system"
+
+// UserContextKey is the key under which the authenticated user is kept in
+// the request context.
+const UserContextKey = "currentUser"
+
+// AuditLog records one change of an entity. Before is empty for creates and
+// After is empty for deletes. A merge is recorded under the candidate merged
+// away: Before is that candidate and After the one it was merged into. An
+// erasure has no Before, and the entries about the erased candidate keep no
+// personal data. CandidateID is the candidate the entry is about, if any:
+// the candidate itself, the one a merge went into, or the candidate the
+// record belongs to.
+type AuditLog struct {
+	ID          string          `json:"id"`
+	ActorID     string          `json:"actor_id,omitempty"`
+	Actor       string          `json:"actor"`
+	EntityType  string          `json:"entity_type"`
+	EntityID    string          `json:"entity_id"`
+	CandidateID string          `json:"candidate_id,omitempty"`
+	Action      string          `json:"action"`
+	Before      json.RawMessage `json:"before,omitempty"`
+	After       json.RawMessage `json:"after,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type AuditLogListRequest struct {
+	Pagination
+	EntityType string `query:"entity_type" validate:"omitempty,oneof=candidate job recruitment candidate_score scoring_profile scorecard application interviewer_assignment user attachment candidate_consent"`
+	EntityID   string `query:"entity_id"`
+	Actor      string `query:"actor"`
+	Action     string `query:"action" validate:"omitempty,oneof=create update delete status_change merge erase"`
+}
+
+// UserFromContext returns the authenticated user of the request ctx belongs
+// to, or nil outside of an authenticated request.
+func UserFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(UserContextKey).(*User)
+	return user
+}
@@ -1,18 +1,135 @@
 package model
 
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
 type Candidate struct {
 	ID                string `json:"candidate"`
 	Name              string `json:"name"`
 	Address           string `json:"address"`
 	Experience        int    `json:"experience"`
 	WillingToRelocate string `json:"willing_to_relocate"`
+
+	// Email and Phone are never serialized with the candidate. They are
+	// handed out as a CandidateContact, once the candidate has consented to
+	// be contacted.
+	Email string `json:"-"`
+	Phone string `json:"-"`
+
+	Skills []string `json:"skills,omitempty"`
+
+	// ErasedAt is set once the candidate has been anonymized, see
+	// ErasedCandidateName.
+	ErasedAt *time.Time `json:"erased_at,omitempty"`
+
+	// Contactable tells whether one of the Consents allows contacting the
+	// candidate, as set by ApplyConsents.
+	Consents    []CandidateConsent `json:"consents,omitempty"`
+	Contactable bool               `json:"contactable"`
+
+	// Relevance and Highlights are only set on search results.
+	Relevance  float64           `json:"relevance,omitempty"`
+	Highlights []SearchHighlight `json:"highlights,omitempty"`
 }
 
 type (
+	// CandidateCreateRequest is refused when the candidate looks like one
+	// already stored, unless AllowDuplicate is set.
 	CandidateCreateRequest struct {
-		Name              string `json:"name" validate:"required"`
-		Address           string `json:"address" validate:"required"`
-		Experience        int    `json:"experience" validate:"required"`
-		WillingToRelocate string `json:"willing_to_relocate" validate:"required"`
+		Name              string   `json:"name" validate:"required"`
+		Address           string   `json:"address" validate:"required"`
+		Experience        int      `json:"experience" validate:"gte=0"`
+		WillingToRelocate string   `json:"willing_to_relocate" validate:"required,oneof=yes no"`
+		Email             string   `json:"email" validate:"omitempty,email,max=254"`
+		Phone             string   `json:"phone" validate:"omitempty,max=30"`
+		Skills            []string `json:"skills" validate:"omitempty,dive,max=100"`
+		AllowDuplicate    bool     `json:"allow_duplicate,omitempty" form:"allow_duplicate"`
+	}
+
+	CandidateUpdateRequest struct {
+		Name              *string   `json:"name" validate:"omitempty,min=1"`
+		Address           *string   `json:"address" validate:"omitempty,min=1"`
+		Experience        *int      `json:"experience" validate:"omitempty,gte=0"`
+		WillingToRelocate *string   `json:"willing_to_relocate" validate:"omitempty,oneof=yes no"`
+		Email             *string   `json:"email" validate:"omitempty,max=254"`
+		Phone             *string   `json:"phone" validate:"omitempty,max=30"`
+		Skills            *[]string `json:"skills" validate:"omitempty,dive,max=100"`
+	}
+
+	CandidateListRequest struct {
+		Pagination
+		Query             string `query:"q" validate:"omitempty,max=200"`
+		Sort              string `query:"sort" validate:"omitempty,oneof=name -name experience -experience"`
+		ExperienceMin     *int   `query:"experience_min" validate:"omitempty,gte=0"`
+		ExperienceMax     *int   `query:"experience_max" validate:"omitempty,gte=0"`
+		WillingToRelocate string `query:"willing_to_relocate" validate:"omitempty,oneof=yes no"`
+		// AssignedTo keeps the candidates the user is assigned to in any
+		// recruitment, when set
+		AssignedTo string `query:"-"`
 	}
 )
+
+// UpdateRequest turns a full replacement payload into an update request that
+// sets every field. Skills and contact details are only replaced when the
+// payload has them, so clients that do not know about them keep them.
+func (r CandidateCreateRequest) UpdateRequest() CandidateUpdateRequest {
+	result := CandidateUpdateRequest{
+		Name:              &r.Name,
+		Address:           &r.Address,
+		Experience:        &r.Experience,
+		WillingToRelocate: &r.WillingToRelocate,
+	}
+
+	if r.Email != "" {
+		result.Email = &r.Email
+	}
+
+	if r.Phone != "" {
+		result.Phone = &r.Phone
+	}
+
+	if r.Skills != nil {
+		result.Skills = &r.Skills
+	}
+
+	return result
+}
+
+// ApplyConsents sets the status of every consent of the candidate at now,
+// and whether one of them allows contacting the candidate.
+func (c *Candidate) ApplyConsents(now time.Time) {
+	for i := range c.Consents {
+		c.Consents[i].Status = c.Consents[i].StatusAt(now)
+	}
+
+	c.Contactable = ValidConsent(c.Consents, ConsentPurposeContact, now) != nil
+}
+
+// NormalizeSkills trims the skills, drops empty ones and those repeated in
+// another case, and sorts the rest.
+func NormalizeSkills(skills []string) []string {
+	var (
+		seen   = map[string]bool{}
+		result = []string{}
+	)
+
+	for _, skill := range skills {
+		skill = strings.TrimSpace(skill)
+		key := strings.ToLower(skill)
+		if skill == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		result = append(result, skill)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i]) < strings.ToLower(result[j])
+	})
+
+	return result
+}
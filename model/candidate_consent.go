@@ -0,0 +1,139 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// ConsentPurposeContact allows emailing or calling the candidate about
+	// opportunities.
+	ConsentPurposeContact = "contact"
+	// ConsentPurposeRetention allows keeping the candidate on file.
+	ConsentPurposeRetention = "retention"
+)
+
+// ConsentPurposes lists every purpose consent is given for.
+var ConsentPurposes = []string{ConsentPurposeContact, ConsentPurposeRetention}
+
+// ConsentSources lists where consent can be given.
+var ConsentSources = []string{"application_form", "email", "phone", "in_person", "job_board", "other"}
+
+const (
+	ConsentValid     = "valid"
+	ConsentExpired   = "expired"
+	ConsentWithdrawn = "withdrawn"
+	ConsentPending   = "pending"
+)
+
+// DefaultConsentMonths is how long consent lasts when no expiry is given.
+const DefaultConsentMonths = 24
+
+const (
+	// MinPhoneDigits and MaxPhoneDigits bound the digits of a phone number,
+	// from short local numbers to the longest international ones.
+	MinPhoneDigits = 7
+	MaxPhoneDigits = 15
+)
+
+type (
+	// CandidateConsent records that a candidate agreed to a purpose, when
+	// and how. It is valid from GrantedAt until it expires or is withdrawn.
+	CandidateConsent struct {
+		ID          string     `json:"id"`
+		CandidateID string     `json:"candidate_id"`
+		Purpose     string     `json:"purpose"`
+		Source      string     `json:"source"`
+		GrantedAt   time.Time  `json:"granted_at"`
+		ExpiresAt   time.Time  `json:"expires_at"`
+		WithdrawnAt *time.Time `json:"withdrawn_at,omitempty"`
+		RecordedBy  string     `json:"recorded_by"`
+		CreatedAt   time.Time  `json:"created_at"`
+		Status      string     `json:"status"`
+	}
+
+	// CandidateConsentCreateRequest takes dates as YYYY-MM-DD. GrantedAt
+	// defaults to now and ExpiresAt to DefaultConsentMonths later.
+	CandidateConsentCreateRequest struct {
+		Purpose    string `json:"purpose" form:"purpose" validate:"required,oneof=contact retention"`
+		Source     string `json:"source" form:"source" validate:"required,oneof=application_form email phone in_person job_board other"`
+		GrantedAt  string `json:"granted_at" form:"granted_at" validate:"omitempty,datetime=2006-01-02"`
+		ExpiresAt  string `json:"expires_at" form:"expires_at" validate:"omitempty,datetime=2006-01-02"`
+		RecordedBy string `json:"-" validate:"required"`
+	}
+
+	// CandidateContact is how a candidate who consented to be contacted is
+	// reached.
+	CandidateContact struct {
+		CandidateID string           `json:"candidate_id"`
+		Name        string           `json:"name"`
+		Email       string           `json:"email"`
+		Phone       string           `json:"phone"`
+		Consent     CandidateConsent `json:"consent"`
+	}
+)
+
+// StatusAt tells whether the consent is valid at now, and why not.
+func (c CandidateConsent) StatusAt(now time.Time) string {
+	switch {
+	case c.WithdrawnAt != nil && !c.WithdrawnAt.After(now):
+		return ConsentWithdrawn
+	case !c.ExpiresAt.After(now):
+		return ConsentExpired
+	case c.GrantedAt.After(now):
+		return ConsentPending
+	}
+
+	return ConsentValid
+}
+
+// ValidConsent returns the valid consent for purpose lasting the longest,
+// or nil when there is none.
+func ValidConsent(consents []CandidateConsent, purpose string, now time.Time) *CandidateConsent {
+	var result *CandidateConsent
+	for i, consent := range consents {
+		if consent.Purpose != purpose || consent.StatusAt(now) != ConsentValid {
+			continue
+		}
+
+		if result == nil || consent.ExpiresAt.After(result.ExpiresAt) {
+			result = &consents[i]
+		}
+	}
+
+	return result
+}
+
+// NormalizeEmail trims an email address and lowercases it.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizePhone keeps the digits of a phone number and its leading plus,
+// dropping spaces, dashes, dots and parentheses. It reports false for a
+// number with other characters or without MinPhoneDigits to MaxPhoneDigits
+// digits. An empty number is left empty.
+func NormalizePhone(phone string) (string, bool) {
+	phone = strings.TrimSpace(phone)
+	if phone == "" {
+		return "", true
+	}
+
+	var b strings.Builder
+	for i, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')' || r == '/':
+		default:
+			return phone, false
+		}
+	}
+
+	result := b.String()
+	digits := len(strings.TrimPrefix(result, "+"))
+
+	return result, digits >= MinPhoneDigits && digits <= MaxPhoneDigits
+}
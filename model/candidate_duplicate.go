@@ -0,0 +1,44 @@
+package model
+
+import "strings"
+
+const (
+	DuplicateReasonSameName       = "same_name"
+	DuplicateReasonSimilarName    = "similar_name"
+	DuplicateReasonSameAddress    = "same_address"
+	DuplicateReasonSimilarAddress = "similar_address"
+	DuplicateReasonSameEmail      = "same_email"
+	DuplicateReasonSamePhone      = "same_phone"
+)
+
+type (
+	// CandidateDuplicate is a stored candidate that looks like the same
+	// person as another one. Similarity goes from 0 to 1, and Reasons name
+	// the fields that look alike.
+	CandidateDuplicate struct {
+		Candidate  Candidate `json:"candidate"`
+		Similarity float64   `json:"similarity"`
+		Reasons    []string  `json:"reasons"`
+	}
+
+	// CandidateMergeRequest names the duplicate merged into a candidate. The
+	// duplicate is deleted once its records belong to the candidate.
+	CandidateMergeRequest struct {
+		DuplicateID string `json:"duplicate_id" form:"duplicate_id" validate:"required"`
+	}
+)
+
+// ReasonLabels names the reasons for people.
+func (d CandidateDuplicate) ReasonLabels() string {
+	labels := make([]string, len(d.Reasons))
+	for i, reason := range d.Reasons {
+		labels[i] = strings.ReplaceAll(reason, "_", " ")
+	}
+
+	return strings.Join(labels, ", ")
+}
+
+// SimilarityPercent returns the similarity as a whole percentage.
+func (d CandidateDuplicate) SimilarityPercent() int {
+	return int(d.Similarity*100 + 0.5)
+}
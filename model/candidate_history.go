@@ -0,0 +1,27 @@
+package model
+
+const (
+	// OutcomeInProgress is the outcome while the recruitment is open and
+	// the application, if any, has not reached a final stage.
+	OutcomeInProgress = "in_progress"
+	// OutcomeNotHired is the outcome of a closed recruitment that the
+	// candidate left without a final stage.
+	OutcomeNotHired = "not_hired"
+)
+
+// CandidateHistory lists the recruitments a candidate took part in, by
+// being scored or by applying, latest deadline first.
+type CandidateHistory struct {
+	Candidate    *Candidate              `json:"candidate"`
+	Recruitments []CandidateHistoryEntry `json:"recruitments"`
+}
+
+// CandidateHistoryEntry is one recruitment of a candidate. Outcome is the
+// final application stage, hired, rejected or withdrawn, or else
+// OutcomeInProgress or OutcomeNotHired.
+type CandidateHistoryEntry struct {
+	Recruitment *Recruitment    `json:"recruitment"`
+	Score       *CandidateScore `json:"score,omitempty"`
+	Application *Application    `json:"application,omitempty"`
+	Outcome     string          `json:"outcome"`
+}
@@ -0,0 +1,54 @@
+package model
+
+const (
+	// ImportModeAll inserts nothing unless every row is valid.
+	ImportModeAll = "all"
+	// ImportModeSkipInvalid inserts the valid rows and reports the others.
+	ImportModeSkipInvalid = "skip_invalid"
+)
+
+type (
+	// CandidateImportRequest reports rows that look like a stored candidate
+	// or an earlier row as invalid, unless AllowDuplicates is set.
+	CandidateImportRequest struct {
+		Mode            string `query:"mode" form:"mode" validate:"omitempty,oneof=all skip_invalid"`
+		DryRun          bool   `query:"dry_run" form:"dry_run"`
+		AllowDuplicates bool   `query:"allow_duplicates" form:"allow_duplicates"`
+	}
+
+	CandidateImportError struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	}
+
+	// CandidateImportRow is one data row of an import file. Line is the line
+	// number in the file, counting the header as line 1.
+	CandidateImportRow struct {
+		Line      int                    `json:"line"`
+		Candidate CandidateCreateRequest `json:"candidate"`
+		Errors    []CandidateImportError `json:"errors,omitempty"`
+		Imported  bool                   `json:"imported"`
+	}
+
+	CandidateImportResult struct {
+		Mode     string               `json:"mode"`
+		DryRun   bool                 `json:"dry_run"`
+		Total    int                  `json:"total"`
+		Valid    int                  `json:"valid"`
+		Invalid  int                  `json:"invalid"`
+		Imported int                  `json:"imported"`
+		Rows     []CandidateImportRow `json:"rows"`
+	}
+)
+
+// Normalize applies the default mode.
+func (r *CandidateImportRequest) Normalize() {
+	if r.Mode == "" {
+		r.Mode = ImportModeAll
+	}
+}
+
+// Valid reports whether the row passed validation.
+func (r CandidateImportRow) Valid() bool {
+	return len(r.Errors) == 0
+}
@@ -0,0 +1,58 @@
+package model
+
+const (
+	CriterionExperience       = "experience"
+	CriterionRequiredSkills   = "required_skills"
+	CriterionNiceToHaveSkills = "nice_to_have_skills"
+	CriterionLocation         = "location"
+	CriterionRelocation       = "relocation"
+)
+
+type (
+	// MatchCriterion tells how a candidate fares against one requirement of
+	// a job. Score runs from 0 to 1. Missing a required criterion keeps the
+	// candidate from being qualified, missing the others only lowers the
+	// score.
+	MatchCriterion struct {
+		Criterion string   `json:"criterion"`
+		Required  bool     `json:"required"`
+		Met       bool     `json:"met"`
+		Score     float64  `json:"score"`
+		Detail    string   `json:"detail"`
+		Matched   []string `json:"matched,omitempty"`
+		Missing   []string `json:"missing,omitempty"`
+	}
+
+	// CandidateMatch scores a candidate against the requirements of a job
+	// from 0 to 100. Qualified candidates meet every required criterion.
+	CandidateMatch struct {
+		Candidate Candidate        `json:"candidate"`
+		Score     float64          `json:"score"`
+		Qualified bool             `json:"qualified"`
+		Criteria  []MatchCriterion `json:"criteria"`
+	}
+
+	CandidateMatchListRequest struct {
+		Pagination
+		QualifiedOnly bool    `query:"qualified_only"`
+		MinScore      float64 `query:"min_score" validate:"gte=0,lte=100"`
+	}
+)
+
+// Label names the criterion for people.
+func (c MatchCriterion) Label() string {
+	switch c.Criterion {
+	case CriterionExperience:
+		return "Experience"
+	case CriterionRequiredSkills:
+		return "Required skills"
+	case CriterionNiceToHaveSkills:
+		return "Nice to have skills"
+	case CriterionLocation:
+		return "Location"
+	case CriterionRelocation:
+		return "Relocation"
+	}
+
+	return c.Criterion
+}
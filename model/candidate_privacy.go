@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// ErasedCandidateName replaces the name of an erased candidate. Erasing a
+// candidate also clears their address, email, phone, skills, consents,
+// attachments and the comments made about them, but keeps their scores and
+// applications so the statistics of past recruitments do not change.
+const ErasedCandidateName = "Erased candidate"
+
+// ErasedFilename replaces the name of the files of an erased candidate in
+// the audit log.
+const ErasedFilename = "erased"
+
+type (
+	// CandidateExport is everything stored about a candidate, to answer
+	// their request for a copy of their data. Candidate carries the skills
+	// and consents, Email and Phone the contact details it leaves out, and
+	// AuditLog every change made to the candidate and to the records about
+	// them.
+	CandidateExport struct {
+		ExportedAt   time.Time                    `json:"exported_at"`
+		Candidate    Candidate                    `json:"candidate"`
+		Email        string                       `json:"email"`
+		Phone        string                       `json:"phone"`
+		Scores       []CandidateScore             `json:"scores"`
+		Scorecards   []Scorecard                  `json:"scorecards"`
+		Applications []CandidateExportApplication `json:"applications"`
+		Attachments  []Attachment                 `json:"attachments"`
+		AuditLog     []AuditLog                   `json:"audit_log"`
+	}
+
+	// CandidateExportApplication is an application with the stages it went
+	// through.
+	CandidateExportApplication struct {
+		Application
+		Events []ApplicationStageEvent `json:"events"`
+	}
+)
+
+// Erased reports whether the candidate has been anonymized.
+func (c Candidate) Erased() bool {
+	return c.ErasedAt != nil
+}
@@ -1,8 +1,8 @@
 package model
 
 import (
-	"context"
-	"talentapp/driver/db/mysql"
+	"math"
+	"time"
 )
 
 type CandidateScore struct {
@@ -16,6 +16,47 @@ type CandidateScore struct {
 	SkillScore             float64      `json:"skill_score"`
 	ExperienceScore        float64      `json:"experience_score"`
 	OverallScore           float64      `json:"overall_score"`
+	ScoringProfileID       string       `json:"scoring_profile_id,omitempty"`
+	ScoringProfileVersion  int          `json:"scoring_profile_version"`
+	InterviewerCount       int          `json:"interviewer_count"`
+	AttitudeSpread         float64      `json:"attitude_spread"`
+	SkillSpread            float64      `json:"skill_spread"`
+	Rank                   int          `json:"rank,omitempty"`
+
+	// ScoringProfile and Breakdown are only filled when a single score
+	// is read.
+	ScoringProfile *ScoringProfile  `json:"scoring_profile,omitempty"`
+	Breakdown      []ScoreComponent `json:"breakdown,omitempty"`
+}
+
+// ScoreComponent is one criterion of an overall score: its score, its weight
+// in the scoring profile and the share of the overall score it makes up.
+type ScoreComponent struct {
+	Criterion string  `json:"criterion"`
+	Score     float64 `json:"score"`
+	Weight    float64 `json:"weight"`
+	Weighted  float64 `json:"weighted"`
+}
+
+// SetBreakdown fills ScoringProfile with profile, the profile the score was
+// computed with, and Breakdown with the share of each criterion.
+func (s *CandidateScore) SetBreakdown(profile *ScoringProfile) {
+	component := func(criterion string, score, weight float64) ScoreComponent {
+		return ScoreComponent{
+			Criterion: criterion,
+			Score:     score,
+			Weight:    weight,
+			Weighted:  math.Round(score*weight*100) / 100,
+		}
+	}
+
+	s.ScoringProfile = profile
+	s.Breakdown = []ScoreComponent{
+		component("Willing To Relocate", s.WillingToRelocateScore, profile.RelocationWeight),
+		component("Experience", s.ExperienceScore, profile.ExperienceWeight),
+		component("Attitude", s.AttitudeScore, profile.AttitudeWeight),
+		component("Skill", s.SkillScore, profile.SkillWeight),
+	}
 }
 
 type (
@@ -24,36 +65,37 @@ type (
 		WillingToRelocate string `json:"willing_to_relocate_score" validate:"required,eq=yes|eq=no"`
 		AttitudeScore     string `json:"attitude_score" validate:"required"`
 		SkillScore        string `json:"skill_score" validate:"required"`
-		Experience        int    `json:"experience" validate:"required"`
+		Experience        int    `json:"experience" validate:"gte=0"`
 	}
-)
-
-func (cs *CandidateScore) LoadCandidate(ctx context.Context) error {
-	var result Candidate
-	SQL := "SELECT id, name, address, experience, willing_to_relocate FROM candidate WHERE id = ?"
-	row := mysql.DB.QueryRowContext(ctx, SQL, cs.CandidateID)
 
-	err := row.Scan(&result.ID, &result.Name, &result.Address, &result.Experience, &result.WillingToRelocate)
-	if err != nil {
-		return err
+	CandidateScoreUpdateRequest struct {
+		WillingToRelocate string `json:"willing_to_relocate_score" validate:"required,eq=yes|eq=no"`
+		AttitudeScore     string `json:"attitude_score" validate:"required"`
+		SkillScore        string `json:"skill_score" validate:"required"`
+		Experience        int    `json:"experience" validate:"gte=0"`
 	}
 
-	cs.Candidate = &result
-
-	return nil
-}
-
-func (cs *CandidateScore) LoadRecruitment(ctx context.Context) error {
-	var result Recruitment
-	SQL := "SELECT id, job_id, status, deadline FROM recruitment WHERE id = ?"
-	row := mysql.DB.QueryRowContext(ctx, SQL, cs.RecruitmentID)
-
-	err := row.Scan(&result.ID, &result.JobID, &result.Status, &result.Deadline)
-	if err != nil {
-		return err
+	CandidateScoreListRequest struct {
+		Pagination
+		Sort              string `query:"sort" validate:"omitempty,oneof=overall_score -overall_score name -name experience -experience"`
+		ExperienceMin     *int   `query:"experience_min" validate:"omitempty,gte=0"`
+		ExperienceMax     *int   `query:"experience_max" validate:"omitempty,gte=0"`
+		WillingToRelocate string `query:"willing_to_relocate" validate:"omitempty,oneof=yes no"`
+		// AssignedTo keeps the candidates the user is assigned to in the
+		// recruitment, when set
+		AssignedTo string `query:"-"`
 	}
 
-	cs.Recruitment = &result
+	CandidateScoreExportRequest struct {
+		CandidateScoreListRequest
+		Format string `query:"-" validate:"required,oneof=csv xlsx pdf"`
+	}
+)
 
-	return nil
+// CandidateScoreReport is the full ranking of a recruitment, as exported to a
+// file.
+type CandidateScoreReport struct {
+	Recruitment *Recruitment     `json:"recruitment"`
+	Scores      []CandidateScore `json:"scores"`
+	GeneratedAt time.Time        `json:"generated_at"`
 }
@@ -1,5 +1,7 @@
 package model
 
+import "strings"
+
 type Job struct {
 	ID             string `json:"id"`
 	Position       string `json:"position"`
@@ -7,6 +9,24 @@ type Job struct {
 	Requester      string `json:"requester"`
 	JobDescription string `json:"job_description"`
 	Criteria       string `json:"criteria"`
+	// Requirements are the criteria candidates are matched against, while
+	// Criteria describes them for people.
+	Requirements JobRequirements `json:"requirements"`
+
+	// Relevance and Highlights are only set on search results.
+	Relevance  float64           `json:"relevance,omitempty"`
+	Highlights []SearchHighlight `json:"highlights,omitempty"`
+}
+
+// JobRequirements are what a job asks of candidates. Zero values ask for
+// nothing. Location is met by candidates living there or willing to move,
+// and RelocationRequired asks every candidate to be willing to relocate.
+type JobRequirements struct {
+	MinExperience      int      `json:"min_experience" form:"min_experience" validate:"gte=0,lte=60"`
+	RequiredSkills     []string `json:"required_skills" form:"required_skills" validate:"omitempty,dive,max=100"`
+	NiceToHaveSkills   []string `json:"nice_to_have_skills" form:"nice_to_have_skills" validate:"omitempty,dive,max=100"`
+	Location           string   `json:"location" form:"location" validate:"max=250"`
+	RelocationRequired bool     `json:"relocation_required" form:"relocation_required"`
 }
 
 type (
@@ -16,5 +36,65 @@ type (
 		Requester      string `json:"requester" validate:"required"`
 		JobDescription string `json:"job_description" validate:"required"`
 		Criteria       string `json:"criteria" validate:"required"`
+		// Requirements are optional, but candidates are only suggested for
+		// jobs that have some.
+		Requirements *JobRequirements `json:"requirements"`
+	}
+
+	JobUpdateRequest struct {
+		Position       *string          `json:"position" validate:"omitempty,min=1"`
+		Department     *string          `json:"department" validate:"omitempty,min=1"`
+		Requester      *string          `json:"requester" validate:"omitempty,min=1"`
+		JobDescription *string          `json:"job_description" validate:"omitempty,min=1"`
+		Criteria       *string          `json:"criteria" validate:"omitempty,min=1"`
+		Requirements   *JobRequirements `json:"requirements"`
+	}
+
+	JobListRequest struct {
+		Pagination
+		Query      string `query:"q" validate:"omitempty,max=200"`
+		Sort       string `query:"sort" validate:"omitempty,oneof=position -position department -department"`
+		Department string `query:"department"`
 	}
 )
+
+// UpdateRequest turns a full replacement payload into an update request that
+// sets every field. Requirements are only replaced when the payload has
+// them, so clients that do not know about them keep them.
+func (r JobCreateRequest) UpdateRequest() JobUpdateRequest {
+	return JobUpdateRequest{
+		Position:       &r.Position,
+		Department:     &r.Department,
+		Requester:      &r.Requester,
+		JobDescription: &r.JobDescription,
+		Criteria:       &r.Criteria,
+		Requirements:   r.Requirements,
+	}
+}
+
+// Normalize trims the location and normalizes the skills, dropping the
+// nice to have skills that are required anyway.
+func (r *JobRequirements) Normalize() {
+	r.Location = strings.TrimSpace(r.Location)
+	r.RequiredSkills = NormalizeSkills(r.RequiredSkills)
+
+	required := map[string]bool{}
+	for _, skill := range r.RequiredSkills {
+		required[strings.ToLower(skill)] = true
+	}
+
+	niceToHave := []string{}
+	for _, skill := range NormalizeSkills(r.NiceToHaveSkills) {
+		if !required[strings.ToLower(skill)] {
+			niceToHave = append(niceToHave, skill)
+		}
+	}
+
+	r.NiceToHaveSkills = niceToHave
+}
+
+// IsZero reports whether the requirements ask for nothing.
+func (r JobRequirements) IsZero() bool {
+	return r.MinExperience == 0 && len(r.RequiredSkills) == 0 && len(r.NiceToHaveSkills) == 0 &&
+		r.Location == "" && !r.RelocationRequired
+}
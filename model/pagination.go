@@ -0,0 +1,61 @@
+package model
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+type Pagination struct {
+	Page int `json:"page" query:"page" validate:"omitempty,gte=1"`
+	Size int `json:"size" query:"size" validate:"omitempty,gte=1,lte=100"`
+}
+
+type PageMeta struct {
+	Page       int    `json:"page"`
+	Size       int    `json:"size"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+}
+
+// Normalize fills page and size with their defaults when they are not set.
+func (p *Pagination) Normalize() {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+
+	if p.Size < 1 {
+		p.Size = DefaultPageSize
+	}
+
+	if p.Size > MaxPageSize {
+		p.Size = MaxPageSize
+	}
+}
+
+func (p Pagination) Offset() int {
+	return (p.Page - 1) * p.Size
+}
+
+func NewPageMeta(p Pagination, total int) *PageMeta {
+	totalPages := 0
+	if p.Size > 0 {
+		totalPages = (total + p.Size - 1) / p.Size
+	}
+
+	return &PageMeta{
+		Page:       p.Page,
+		Size:       p.Size,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
+
+func (m *PageMeta) HasNext() bool {
+	return m.Page < m.TotalPages
+}
+
+func (m *PageMeta) HasPrev() bool {
+	return m.Page > 1
+}
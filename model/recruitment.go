@@ -1,42 +1,73 @@
 package model
 
 import (
-	"context"
-	"talentapp/driver/db/mysql"
 	"time"
 )
 
 type Recruitment struct {
-	ID             string    `json:"id"`
-	JobID          string    `json:"job_id"`
-	Job            *Job      `json:"job,omitempty"`
-	Status         string    `json:"status"`
-	Deadline       time.Time `json:"deadline"`
-	DeadlineString string    `json:"-"`
+	ID               string     `json:"id"`
+	JobID            string     `json:"job_id"`
+	Job              *Job       `json:"job,omitempty"`
+	Status           string     `json:"status"`
+	Deadline         time.Time  `json:"deadline"`
+	DeadlineString   string     `json:"-"`
+	ScoreAggregation string     `json:"score_aggregation"`
+	ClosedAt         *time.Time `json:"closed_at,omitempty"`
+	ClosedReason     string     `json:"closed_reason,omitempty"`
 }
 
+const (
+	RecruitmentOpen  = "open"
+	RecruitmentClose = "close"
+
+	ClosedReasonManual   = "closed manually"
+	ClosedReasonDeadline = "deadline passed"
+)
+
 type (
 	RecruitmentCreateRequest struct {
-		JobID    string `json:"job_id" validate:"required"`
-		Deadline string `json:"deadline" validate:"required"`
+		JobID            string `json:"job_id" validate:"required"`
+		Deadline         string `json:"deadline" validate:"required"`
+		ScoreAggregation string `json:"score_aggregation" validate:"omitempty,oneof=mean median trimmed_mean"`
 	}
 
 	RecruitmentUpdateStatusRequest struct {
-		Status string `json:"status" validate:"required"`
+		Status string `json:"status" validate:"required,oneof=open close"`
 	}
-)
 
-func (r *Recruitment) LoadJob(ctx context.Context) error {
-	var result Job
-	SQL := "SELECT id, position, department, requester, job_description, criteria FROM job WHERE id = ?"
-	row := mysql.DB.QueryRowContext(ctx, SQL, r.JobID)
+	RecruitmentUpdateRequest struct {
+		JobID            *string `json:"job_id" validate:"omitempty,min=1"`
+		Deadline         *string `json:"deadline" validate:"omitempty,datetime=2006-01-02"`
+		Status           *string `json:"status" validate:"omitempty,oneof=open close"`
+		ScoreAggregation *string `json:"score_aggregation" validate:"omitempty,oneof=mean median trimmed_mean"`
+	}
 
-	err := row.Scan(&result.ID, &result.Position, &result.Department, &result.Requester, &result.JobDescription, &result.Criteria)
-	if err != nil {
-		return err
+	RecruitmentListRequest struct {
+		Pagination
+		Sort         string `query:"sort" validate:"omitempty,oneof=deadline -deadline status -status position -position"`
+		Status       string `query:"status" validate:"omitempty,oneof=open close"`
+		Department   string `query:"department"`
+		DeadlineFrom string `query:"deadline_from" validate:"omitempty,datetime=2006-01-02"`
+		DeadlineTo   string `query:"deadline_to" validate:"omitempty,datetime=2006-01-02"`
 	}
+)
+
+// IsClosed reports whether the recruitment stopped accepting candidate
+// scores, either because it was closed or because its deadline passed.
+func (r *Recruitment) IsClosed(now time.Time) bool {
+	return r.Status == RecruitmentClose || now.After(r.Deadline)
+}
 
-	r.Job = &result
+// Close marks the recruitment as closed at now for the given reason.
+func (r *Recruitment) Close(now time.Time, reason string) {
+	r.Status = RecruitmentClose
+	r.ClosedAt = &now
+	r.ClosedReason = reason
+}
 
-	return nil
+// Reopen marks the recruitment as open again and forgets why it was closed.
+func (r *Recruitment) Reopen() {
+	r.Status = RecruitmentOpen
+	r.ClosedAt = nil
+	r.ClosedReason = ""
 }
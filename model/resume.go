@@ -0,0 +1,40 @@
+package model
+
+// ParsedResume holds what could be read from a resume. Fields that were not
+// found are left empty, and Experience is nil when it could not be
+// estimated.
+type ParsedResume struct {
+	Name       string   `json:"name,omitempty"`
+	Address    string   `json:"address,omitempty"`
+	Email      string   `json:"email,omitempty"`
+	Phone      string   `json:"phone,omitempty"`
+	Experience *int     `json:"experience,omitempty"`
+	Skills     []string `json:"skills"`
+}
+
+// Prefill fills the fields of a candidate form with the details of the
+// resume. Fields the resume has no value for are kept, and its skills are
+// added to the ones in the form.
+func (p ParsedResume) Prefill(form *CandidateCreateRequest) {
+	if p.Name != "" {
+		form.Name = p.Name
+	}
+
+	if p.Address != "" {
+		form.Address = p.Address
+	}
+
+	if p.Experience != nil {
+		form.Experience = *p.Experience
+	}
+
+	if p.Email != "" {
+		form.Email = p.Email
+	}
+
+	if p.Phone != "" {
+		form.Phone = p.Phone
+	}
+
+	form.Skills = NormalizeSkills(append(form.Skills, p.Skills...))
+}
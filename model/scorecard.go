@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	AggregationMean        = "mean"
+	AggregationMedian      = "median"
+	AggregationTrimmedMean = "trimmed_mean"
+)
+
+// Scorecard is the assessment of one candidate by one interviewer within a
+// recruitment. The candidate score of the recruitment is aggregated from all
+// of its scorecards.
+type Scorecard struct {
+	ID            string    `json:"id"`
+	RecruitmentID string    `json:"recruitment_id"`
+	CandidateID   string    `json:"candidate_id"`
+	Interviewer   string    `json:"interviewer"`
+	AttitudeGrade string    `json:"attitude_grade"`
+	SkillGrade    string    `json:"skill_grade"`
+	AttitudeScore float64   `json:"attitude_score"`
+	SkillScore    float64   `json:"skill_score"`
+	Comment       string    `json:"comment"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type (
+	ScorecardCreateRequest struct {
+		Interviewer   string `json:"interviewer" validate:"required"`
+		AttitudeScore string `json:"attitude_score" validate:"required"`
+		SkillScore    string `json:"skill_score" validate:"required"`
+		Comment       string `json:"comment"`
+	}
+)
+
+// ScorecardSummary lists the scorecards of a candidate in a recruitment
+// together with their aggregate.
+type ScorecardSummary struct {
+	RecruitmentID    string      `json:"recruitment_id"`
+	CandidateID      string      `json:"candidate_id"`
+	ScoreAggregation string      `json:"score_aggregation"`
+	AttitudeScore    float64     `json:"attitude_score"`
+	SkillScore       float64     `json:"skill_score"`
+	AttitudeSpread   float64     `json:"attitude_spread"`
+	SkillSpread      float64     `json:"skill_spread"`
+	Scorecards       []Scorecard `json:"scorecards"`
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// ScoringProfile holds the Simple Additive Weighting configuration used to
+// score candidates of a job. Profiles are never edited in place, every change
+// creates a new version so existing scores keep pointing at the profile they
+// were computed with.
+type ScoringProfile struct {
+	ID               string             `json:"id"`
+	JobID            string             `json:"job_id"`
+	Version          int                `json:"version"`
+	AttitudeWeight   float64            `json:"attitude_weight"`
+	RelocationWeight float64            `json:"relocation_weight"`
+	SkillWeight      float64            `json:"skill_weight"`
+	ExperienceWeight float64            `json:"experience_weight"`
+	ExperienceBands  []ExperienceBand   `json:"experience_bands"`
+	GradeValues      map[string]float64 `json:"grade_values"`
+	CreatedAt        time.Time          `json:"created_at"`
+}
+
+// ExperienceBand gives Score to every candidate with at least MinYears of
+// experience, up to the next band.
+type ExperienceBand struct {
+	MinYears int     `json:"min_years" validate:"gte=0"`
+	Score    float64 `json:"score" validate:"gte=0,lte=100"`
+}
+
+type (
+	ScoringProfileRequest struct {
+		AttitudeWeight   float64            `json:"attitude_weight" validate:"gte=0,lte=1"`
+		RelocationWeight float64            `json:"relocation_weight" validate:"gte=0,lte=1"`
+		SkillWeight      float64            `json:"skill_weight" validate:"gte=0,lte=1"`
+		ExperienceWeight float64            `json:"experience_weight" validate:"gte=0,lte=1"`
+		ExperienceBands  []ExperienceBand   `json:"experience_bands" validate:"required,min=1,dive"`
+		GradeValues      map[string]float64 `json:"grade_values" validate:"required,min=1,dive,keys,alpha,len=1,endkeys,gte=0,lte=100"`
+	}
+)
+
+// DefaultScoringProfile is the first version of the profile of every job.
+// It is stored under a fixed id, so a job is given it only once.
+func DefaultScoringProfile(jobID string) *ScoringProfile {
+	return &ScoringProfile{
+		ID:               "default-" + jobID,
+		JobID:            jobID,
+		AttitudeWeight:   0.2,
+		RelocationWeight: 0.2,
+		SkillWeight:      0.3,
+		ExperienceWeight: 0.3,
+		ExperienceBands: []ExperienceBand{
+			{MinYears: 0, Score: 50},
+			{MinYears: 2, Score: 70},
+			{MinYears: 3, Score: 80},
+			{MinYears: 4, Score: 90},
+			{MinYears: 5, Score: 100},
+		},
+		GradeValues: map[string]float64{
+			"A": 100,
+			"B": 85,
+			"C": 70,
+			"D": 60,
+			"E": 50,
+		},
+	}
+}
+
+// ExperienceScore returns the score of the highest band reached by years.
+// Years below the first band get the lowest band score.
+func (p *ScoringProfile) ExperienceScore(years int) float64 {
+	bands := make([]ExperienceBand, len(p.ExperienceBands))
+	copy(bands, p.ExperienceBands)
+	sort.Slice(bands, func(i, j int) bool { return bands[i].MinYears < bands[j].MinYears })
+
+	if len(bands) == 0 {
+		return 0
+	}
+
+	score := bands[0].Score
+	for _, band := range bands {
+		if years >= band.MinYears {
+			score = band.Score
+		}
+	}
+
+	return score
+}
+
+// GradeValue returns the value of a letter grade. Unknown grades get the
+// lowest value of the scale.
+func (p *ScoringProfile) GradeValue(grade string) float64 {
+	if value, ok := p.GradeValues[grade]; ok {
+		return value
+	}
+
+	var (
+		lowest float64
+		found  bool
+	)
+
+	for _, value := range p.GradeValues {
+		if !found || value < lowest {
+			lowest, found = value, true
+		}
+	}
+
+	return lowest
+}
+
+// Grades returns the letter grades of the scale from best to worst.
+func (p *ScoringProfile) Grades() []string {
+	grades := make([]string, 0, len(p.GradeValues))
+	for grade := range p.GradeValues {
+		grades = append(grades, grade)
+	}
+
+	sort.Slice(grades, func(i, j int) bool {
+		if p.GradeValues[grades[i]] == p.GradeValues[grades[j]] {
+			return grades[i] < grades[j]
+		}
+
+		return p.GradeValues[grades[i]] > p.GradeValues[grades[j]]
+	})
+
+	return grades
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"html/template"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxSearchTerms caps the words of a query that are searched for.
+	MaxSearchTerms = 10
+	// MinPrefixLength is the length a term needs to match the words it
+	// starts. Shorter terms, such as the c of c++, would match too many.
+	MinPrefixLength = 2
+	// DefaultSearchSize is the number of candidates and of jobs returned by
+	// a search across both.
+	DefaultSearchSize = 5
+)
+
+type (
+	SearchRequest struct {
+		Query string `query:"q" validate:"required,max=200"`
+		Size  int    `query:"size" validate:"omitempty,gte=1,lte=50"`
+		// AssignedTo keeps the candidates the user is assigned to, when set
+		AssignedTo string `query:"-"`
+	}
+
+	// SearchHighlight is the passage of a field that matched a search. The
+	// snippet is HTML: its text is escaped and the matching words are
+	// wrapped in <mark>.
+	SearchHighlight struct {
+		Field   string `json:"field"`
+		Snippet string `json:"snippet"`
+	}
+
+	// SearchResult holds the best candidates and jobs for a query, with the
+	// number of matches of each so clients can link to the full lists.
+	SearchResult struct {
+		Query           string      `json:"query"`
+		Candidates      []Candidate `json:"candidates"`
+		CandidatesTotal int         `json:"candidates_total"`
+		Jobs            []Job       `json:"jobs"`
+		JobsTotal       int         `json:"jobs_total"`
+	}
+)
+
+// Normalize applies the default size.
+func (r *SearchRequest) Normalize() {
+	if r.Size < 1 {
+		r.Size = DefaultSearchSize
+	}
+}
+
+// Label names the field for people.
+func (h SearchHighlight) Label() string {
+	return strings.ReplaceAll(h.Field, "_", " ")
+}
+
+// SnippetHTML returns the snippet for templates, which would escape it
+// again otherwise.
+func (h SearchHighlight) SnippetHTML() template.HTML {
+	return template.HTML(h.Snippet)
+}
+
+// SearchTerms splits query into the lowercase words that are searched for.
+// Anything but letters and digits separates words, so the terms are safe
+// to pass to any full-text syntax. Repeated words are dropped and at most
+// MaxSearchTerms are kept.
+func SearchTerms(query string) []string {
+	var (
+		seen  = map[string]bool{}
+		terms []string
+	)
+
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == MaxSearchTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+// PrefixTerms returns the terms long enough to match the words they start.
+func PrefixTerms(terms []string) []string {
+	var result []string
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) >= MinPrefixLength {
+			result = append(result, term)
+		}
+	}
+
+	return result
+}
+
+// SearchSkills returns the lowercase skill names a query finds whole: the
+// query itself and each of its terms, so "machine learning" and "c++" find
+// those skills too. It is empty for a query without any word.
+func SearchSkills(query string) []string {
+	terms := SearchTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	phrase := strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	if phrase == terms[0] {
+		return terms
+	}
+
+	return append([]string{phrase}, terms...)
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	RoleAdmin         = "admin"
+	RoleRecruiter     = "recruiter"
+	RoleHiringManager = "hiring_manager"
+	RoleInterviewer   = "interviewer"
+)
+
+const (
+	PermCandidateRead    = "candidate:read"
+	PermCandidateWrite   = "candidate:write"
+	PermJobRead          = "job:read"
+	PermJobWrite         = "job:write"
+	PermRecruitmentRead  = "recruitment:read"
+	PermRecruitmentWrite = "recruitment:write"
+	PermScoreRead        = "score:read"
+	PermScoreWrite       = "score:write"
+	PermScorecardWrite   = "scorecard:write"
+	PermApplicationRead  = "application:read"
+	PermApplicationWrite = "application:write"
+	PermUserManage       = "user:manage"
+	PermAuditRead        = "audit:read"
+)
+
+// rolePermissions lists what each role may do. Recruiters run the
+// recruitments, hiring managers own the jobs and decide on applications and
+// interviewers only read and score the candidates assigned to them, see
+// User.AssignedOnly.
+var rolePermissions = map[string][]string{
+	RoleAdmin: {
+		PermCandidateRead, PermCandidateWrite, PermJobRead, PermJobWrite,
+		PermRecruitmentRead, PermRecruitmentWrite, PermScoreRead, PermScoreWrite,
+		PermScorecardWrite, PermApplicationRead, PermApplicationWrite, PermUserManage,
+		PermAuditRead,
+	},
+	RoleRecruiter: {
+		PermCandidateRead, PermCandidateWrite, PermJobRead, PermJobWrite,
+		PermRecruitmentRead, PermRecruitmentWrite, PermScoreRead, PermScoreWrite,
+		PermScorecardWrite, PermApplicationRead, PermApplicationWrite, PermAuditRead,
+	},
+	RoleHiringManager: {
+		PermCandidateRead, PermJobRead, PermJobWrite, PermRecruitmentRead,
+		PermScoreRead, PermScorecardWrite, PermApplicationRead, PermApplicationWrite,
+	},
+	RoleInterviewer: {
+		PermCandidateRead, PermJobRead, PermRecruitmentRead, PermScoreRead,
+		PermScorecardWrite, PermApplicationRead,
+	},
+}
+
+const (
+	TokenSession = "session"
+	TokenAPI     = "api"
+)
+
+type User struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UserToken is a login session of the web UI or an API key. Only the hash
+// of the token is stored.
+type UserToken struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Kind       string     `json:"kind"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"-"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// InterviewerAssignment allows an interviewer to score a candidate of a
+// recruitment.
+type InterviewerAssignment struct {
+	ID            string    `json:"id"`
+	RecruitmentID string    `json:"recruitment_id"`
+	CandidateID   string    `json:"candidate_id"`
+	UserID        string    `json:"user_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type (
+	UserCreateRequest struct {
+		Name     string `json:"name" validate:"required"`
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" validate:"required,min=8"`
+		Role     string `json:"role" validate:"required,oneof=admin recruiter hiring_manager interviewer"`
+	}
+
+	LoginRequest struct {
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" validate:"required"`
+	}
+
+	TokenCreateRequest struct {
+		Email    string `json:"email" validate:"required,email"`
+		Password string `json:"password" validate:"required"`
+		Name     string `json:"name" validate:"required"`
+	}
+
+	InterviewerAssignmentCreateRequest struct {
+		UserID      string `json:"user_id" validate:"required"`
+		CandidateID string `json:"candidate_id" validate:"required"`
+	}
+)
+
+// Can reports whether the role of the user grants permission.
+func (u *User) Can(permission string) bool {
+	for _, granted := range rolePermissions[u.Role] {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AssignedOnly reports whether the user only reads the candidates, scores
+// and applications of the assignments they have, instead of every one their
+// role may read.
+func (u *User) AssignedOnly() bool {
+	return u.Role == RoleInterviewer
+}
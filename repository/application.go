@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"talentapp/model"
+)
+
+type ApplicationRepository interface {
+	GetApplicationByID(ctx context.Context, id string) (*model.Application, error)
+	GetApplicationListByRecruitmentID(ctx context.Context, recruitmentID string) (*[]model.Application, error)
+	GetApplicationListByCandidateID(ctx context.Context, candidateID string) (*[]model.Application, error)
+	CountApplicationByCandidate(ctx context.Context, recruitmentID, candidateID string) (int, error)
+	CountApplicationByRecruitmentID(ctx context.Context, recruitmentID string) (int, error)
+	CountApplicationByCandidateID(ctx context.Context, candidateID string) (int, error)
+	PostApplication(ctx context.Context, model *model.Application) error
+	UpdateApplicationStage(ctx context.Context, model *model.Application, fromStage string) error
+	PostApplicationStageEvent(ctx context.Context, model *model.ApplicationStageEvent) error
+	GetApplicationStageEventListByApplicationID(ctx context.Context, applicationID string) (*[]model.ApplicationStageEvent, error)
+}
+
+type applicationRepository struct {
+	DB *DB
+}
+
+func NewApplicationRepository(db *DB) ApplicationRepository {
+	return &applicationRepository{DB: db}
+}
+
+func (r *applicationRepository) GetApplicationByID(ctx context.Context, id string) (*model.Application, error) {
+	var result model.Application
+	SQL := "SELECT id, recruitment_id, candidate_id, stage, created_at, updated_at FROM application WHERE id = ?"
+	row := conn(ctx, r.DB).QueryRowContext(ctx, SQL, id)
+
+	err := row.Scan(&result.ID, &result.RecruitmentID, &result.CandidateID, &result.Stage, &result.CreatedAt, &result.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetApplicationListByRecruitmentID returns the applications of a
+// recruitment with their candidate, oldest first.
+func (r *applicationRepository) GetApplicationListByRecruitmentID(ctx context.Context, recruitmentID string) (*[]model.Application, error) {
+	var result = []model.Application{}
+	SQL := "SELECT a.id, a.recruitment_id, a.candidate_id, a.stage, a.created_at, a.updated_at, c.id, c.name, c.address, c.experience, c.willing_to_relocate " +
+		"FROM application a JOIN candidate c ON c.id = a.candidate_id WHERE a.recruitment_id = ? ORDER BY a.created_at, a.id"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, recruitmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		application := model.Application{Candidate: &model.Candidate{}}
+		err = rows.Scan(&application.ID, &application.RecruitmentID, &application.CandidateID, &application.Stage, &application.CreatedAt, &application.UpdatedAt,
+			&application.Candidate.ID, &application.Candidate.Name, &application.Candidate.Address, &application.Candidate.Experience, &application.Candidate.WillingToRelocate)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, application)
+	}
+
+	return &result, nil
+}
+
+// GetApplicationListByCandidateID returns the applications of a candidate
+// with their recruitment and its job, latest deadline first.
+func (r *applicationRepository) GetApplicationListByCandidateID(ctx context.Context, candidateID string) (*[]model.Application, error) {
+	var result = []model.Application{}
+	SQL := "SELECT a.id, a.recruitment_id, a.candidate_id, a.stage, a.created_at, a.updated_at, " + recruitmentColumns +
+		" FROM application a JOIN recruitment r ON r.id = a.recruitment_id JOIN job j ON j.id = r.job_id WHERE a.candidate_id = ? ORDER BY r.deadline DESC, a.id"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			application model.Application
+			recruitment recruitmentRow
+		)
+
+		dest := []interface{}{&application.ID, &application.RecruitmentID, &application.CandidateID, &application.Stage, &application.CreatedAt, &application.UpdatedAt}
+		if err = rows.Scan(append(dest, recruitment.dest()...)...); err != nil {
+			return nil, err
+		}
+
+		application.Recruitment = recruitment.value()
+		result = append(result, application)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *applicationRepository) CountApplicationByCandidate(ctx context.Context, recruitmentID, candidateID string) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM application WHERE recruitment_id = ? AND candidate_id = ?", recruitmentID, candidateID)
+}
+
+func (r *applicationRepository) CountApplicationByRecruitmentID(ctx context.Context, recruitmentID string) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM application WHERE recruitment_id = ?", recruitmentID)
+}
+
+func (r *applicationRepository) CountApplicationByCandidateID(ctx context.Context, candidateID string) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM application WHERE candidate_id = ?", candidateID)
+}
+
+func (r *applicationRepository) count(ctx context.Context, SQL string, args ...interface{}) (int, error) {
+	var total int
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *applicationRepository) PostApplication(ctx context.Context, model *model.Application) error {
+	return audited(ctx, r.DB, applicationAudit.entry(actionCreate, model.ID), func(ctx context.Context) error {
+		SQL := "insert into application(id, recruitment_id, candidate_id, stage, created_at, updated_at) values (?, ?, ?, ?, ?, ?)"
+		if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.RecruitmentID, model.CandidateID, model.Stage, model.CreatedAt, model.UpdatedAt); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// UpdateApplicationStage moves the application only if it is still in
+// fromStage, so two concurrent moves cannot both succeed. sql.ErrNoRows is
+// returned when the stage was changed in the meantime.
+func (r *applicationRepository) UpdateApplicationStage(ctx context.Context, model *model.Application, fromStage string) error {
+	return audited(ctx, r.DB, applicationAudit.entry(actionStatusChange, model.ID), func(ctx context.Context) error {
+		SQL := "update application set stage = ?, updated_at = ? where id = ? and stage = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.Stage, model.UpdatedAt, model.ID, fromStage)
+		if err != nil {
+			return err
+		}
+
+		return affected(res)
+	})
+}
+
+func (r *applicationRepository) PostApplicationStageEvent(ctx context.Context, model *model.ApplicationStageEvent) error {
+	SQL := "insert into application_stage_event(id, application_id, from_stage, to_stage, changed_by, note, created_at) values (?, ?, ?, ?, ?, ?, ?)"
+	if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.ApplicationID, nullString(model.FromStage), model.ToStage, model.ChangedBy, model.Note, model.CreatedAt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *applicationRepository) GetApplicationStageEventListByApplicationID(ctx context.Context, applicationID string) (*[]model.ApplicationStageEvent, error) {
+	var result = []model.ApplicationStageEvent{}
+	SQL := "SELECT id, application_id, from_stage, to_stage, changed_by, note, created_at FROM application_stage_event WHERE application_id = ? ORDER BY created_at, id"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			event     = model.ApplicationStageEvent{}
+			fromStage sql.NullString
+			note      sql.NullString
+		)
+
+		err = rows.Scan(&event.ID, &event.ApplicationID, &fromStage, &event.ToStage, &event.ChangedBy, &note, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		event.FromStage = fromStage.String
+		event.Note = note.String
+		result = append(result, event)
+	}
+
+	return &result, nil
+}
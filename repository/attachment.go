@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"talentapp/model"
+)
+
+type AttachmentRepository interface {
+	GetAttachmentByID(ctx context.Context, id string) (*model.Attachment, error)
+	GetAttachmentListByCandidateID(ctx context.Context, candidateID string) (*[]model.Attachment, error)
+	CountAttachmentByCandidateID(ctx context.Context, candidateID string) (int, error)
+	CountAttachmentByStorageKey(ctx context.Context, storageKey string) (int, error)
+	CountAttachmentByCandidateSHA256(ctx context.Context, candidateID, sha256 string) (int, error)
+	PostAttachment(ctx context.Context, model *model.Attachment) error
+	DeleteAttachment(ctx context.Context, id string) error
+}
+
+const attachmentColumns = "id, candidate_id, application_id, kind, filename, content_type, size, sha256, storage_key, uploaded_by, created_at"
+
+type attachmentRepository struct {
+	DB *DB
+}
+
+func NewAttachmentRepository(db *DB) AttachmentRepository {
+	return &attachmentRepository{DB: db}
+}
+
+func scanAttachment(row scanner) (*model.Attachment, error) {
+	var (
+		result        model.Attachment
+		applicationID sql.NullString
+	)
+
+	err := row.Scan(&result.ID, &result.CandidateID, &applicationID, &result.Kind, &result.Filename, &result.ContentType,
+		&result.Size, &result.SHA256, &result.StorageKey, &result.UploadedBy, &result.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ApplicationID = applicationID.String
+
+	return &result, nil
+}
+
+func (r *attachmentRepository) GetAttachmentByID(ctx context.Context, id string) (*model.Attachment, error) {
+	SQL := "SELECT " + attachmentColumns + " FROM attachment WHERE id = ?"
+	return scanAttachment(conn(ctx, r.DB).QueryRowContext(ctx, SQL, id))
+}
+
+// GetAttachmentListByCandidateID returns the attachments of a candidate,
+// newest first.
+func (r *attachmentRepository) GetAttachmentListByCandidateID(ctx context.Context, candidateID string) (*[]model.Attachment, error) {
+	var result = []model.Attachment{}
+	SQL := "SELECT " + attachmentColumns + " FROM attachment WHERE candidate_id = ? ORDER BY created_at DESC, id"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *attachmentRepository) CountAttachmentByCandidateID(ctx context.Context, candidateID string) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM attachment WHERE candidate_id = ?", candidateID)
+}
+
+// CountAttachmentByStorageKey counts the attachments sharing a stored
+// object, which may only be removed from storage once none is left.
+func (r *attachmentRepository) CountAttachmentByStorageKey(ctx context.Context, storageKey string) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM attachment WHERE storage_key = ?", storageKey)
+}
+
+func (r *attachmentRepository) CountAttachmentByCandidateSHA256(ctx context.Context, candidateID, sha256 string) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM attachment WHERE candidate_id = ? AND sha256 = ?", candidateID, sha256)
+}
+
+func (r *attachmentRepository) count(ctx context.Context, SQL string, args ...interface{}) (int, error) {
+	var total int
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *attachmentRepository) PostAttachment(ctx context.Context, model *model.Attachment) error {
+	return audited(ctx, r.DB, attachmentAudit.entry(actionCreate, model.ID), func(ctx context.Context) error {
+		SQL := "insert into attachment(" + attachmentColumns + ") values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		_, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.CandidateID, nullString(model.ApplicationID), model.Kind, model.Filename,
+			model.ContentType, model.Size, model.SHA256, model.StorageKey, model.UploadedBy, model.CreatedAt)
+
+		return err
+	})
+}
+
+func (r *attachmentRepository) DeleteAttachment(ctx context.Context, id string) error {
+	return audited(ctx, r.DB, attachmentAudit.entry(actionDelete, id), func(ctx context.Context) error {
+		res, err := conn(ctx, r.DB).ExecContext(ctx, "delete from attachment where id = ?", id)
+		if err != nil {
+			return err
+		}
+
+		return affected(res)
+	})
+}
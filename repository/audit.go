@@ -0,0 +1,410 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"sort"
+	"talentapp/model"
+	"time"
+)
+
+// The action names are repeated here because most repository methods name
+// their argument model, which hides the package inside them.
+const (
+	actionCreate       = model.ActionCreate
+	actionUpdate       = model.ActionUpdate
+	actionDelete       = model.ActionDelete
+	actionStatusChange = model.ActionStatusChange
+	actionMerge        = model.ActionMerge
+	actionErase        = model.ActionErase
+)
+
+// auditTable describes how to read the row of an audited entity, so its
+// state can be stored before and after a change.
+type auditTable struct {
+	entityType string
+	snapshot   string
+}
+
+var (
+	candidateAudit             = auditTable{model.EntityCandidate, "SELECT * FROM candidate WHERE id = ?"}
+	jobAudit                   = auditTable{model.EntityJob, "SELECT * FROM job WHERE id = ?"}
+	recruitmentAudit           = auditTable{model.EntityRecruitment, "SELECT * FROM recruitment WHERE id = ?"}
+	candidateScoreAudit        = auditTable{model.EntityCandidateScore, "SELECT * FROM candidate_score WHERE recruitment_id = ? AND candidate_id = ?"}
+	scoringProfileAudit        = auditTable{model.EntityScoringProfile, "SELECT * FROM scoring_profile WHERE id = ?"}
+	scorecardAudit             = auditTable{model.EntityScorecard, "SELECT * FROM scorecard WHERE id = ?"}
+	applicationAudit           = auditTable{model.EntityApplication, "SELECT * FROM application WHERE id = ?"}
+	interviewerAssignmentAudit = auditTable{model.EntityInterviewerAssignment, "SELECT * FROM interviewer_assignment WHERE id = ?"}
+	userAudit                  = auditTable{model.EntityUser, "SELECT id, name, email, role, created_at FROM app_user WHERE id = ?"}
+	attachmentAudit            = auditTable{model.EntityAttachment, "SELECT * FROM attachment WHERE id = ?"}
+	candidateConsentAudit      = auditTable{model.EntityCandidateConsent, "SELECT * FROM candidate_consent WHERE id = ?"}
+)
+
+type auditEntry struct {
+	table  auditTable
+	action string
+	key    []interface{}
+}
+
+// entry describes an action on the row identified by key, which are the
+// arguments of the snapshot query.
+func (t auditTable) entry(action string, key ...interface{}) auditEntry {
+	return auditEntry{table: t, action: action, key: key}
+}
+
+// audited runs change and records entry in the same transaction. The row
+// is read before the change unless it is created, and after the change
+// unless it is deleted.
+func audited(ctx context.Context, db *DB, entry auditEntry, change func(ctx context.Context) error) error {
+	return withinTransaction(ctx, db, func(ctx context.Context) error {
+		var (
+			q             = conn(ctx, db)
+			before, after map[string]interface{}
+			err           error
+		)
+
+		if entry.action != actionCreate {
+			if before, err = snapshot(ctx, q, entry); err != nil {
+				return err
+			}
+		}
+
+		if err = change(ctx); err != nil {
+			return err
+		}
+
+		if entry.action != actionDelete {
+			if after, err = snapshot(ctx, q, entry); err != nil {
+				return err
+			}
+		}
+
+		return recordAudit(ctx, q, entry, before, after)
+	})
+}
+
+// snapshot reads the audited row into a column to value map. It returns nil
+// when the row does not exist.
+func snapshot(ctx context.Context, q querier, entry auditEntry) (map[string]interface{}, error) {
+	rows, err := q.QueryContext(ctx, entry.table.snapshot, entry.key...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		values = make([]sql.NullString, len(columns))
+		dest   = make([]interface{}, len(columns))
+	)
+
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	if err = rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		if values[i].Valid {
+			result[column] = values[i].String
+		} else {
+			result[column] = nil
+		}
+	}
+
+	return result, nil
+}
+
+func recordAudit(ctx context.Context, q querier, entry auditEntry, before, after map[string]interface{}) error {
+	var (
+		result = model.AuditLog{
+			ID:         uuid.NewString(),
+			Actor:      model.ActorSystem,
+			EntityType: entry.table.entityType,
+			EntityID:   fmt.Sprint(entry.key[0]),
+			Action:     entry.action,
+			CreatedAt:  time.Now(),
+		}
+		err error
+	)
+
+	if user := model.UserFromContext(ctx); user != nil {
+		result.ActorID = user.ID
+		result.Actor = user.Name
+	}
+
+	// Rows keyed by more than their id are still listed under their id, and
+	// a merge under the row merged away.
+	for _, row := range []map[string]interface{}{before, after} {
+		if id, ok := row["id"].(string); ok {
+			result.EntityID = id
+			break
+		}
+	}
+
+	result.CandidateID = auditCandidateID(result, before, after)
+
+	if result.Before, err = marshalSnapshot(before); err != nil {
+		return err
+	}
+
+	if result.After, err = marshalSnapshot(after); err != nil {
+		return err
+	}
+
+	SQL := "insert into audit_log(id, actor_id, actor, entity_type, entity_id, candidate_id, action, before_data, after_data, created_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err = q.ExecContext(ctx, SQL, result.ID, nullString(result.ActorID), result.Actor, result.EntityType, result.EntityID, nullString(result.CandidateID),
+		result.Action, nullString(string(result.Before)), nullString(string(result.After)), result.CreatedAt)
+
+	return err
+}
+
+// auditCandidateID returns the candidate an entry is about, from the
+// snapshots of the row before and after the change, or "" when it is about
+// no candidate.
+func auditCandidateID(log model.AuditLog, before, after map[string]interface{}) string {
+	switch {
+	case log.EntityType != model.EntityCandidate:
+		for _, row := range []map[string]interface{}{after, before} {
+			if id, ok := row["candidate_id"].(string); ok {
+				return id
+			}
+		}
+
+		return ""
+	case log.Action == actionMerge:
+		id, _ := after["id"].(string)
+		return id
+	}
+
+	return log.EntityID
+}
+
+func marshalSnapshot(row map[string]interface{}) (json.RawMessage, error) {
+	if row == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(row)
+}
+
+// auditLogColumns are read by scanAuditLog.
+const auditLogColumns = "id, actor_id, actor, entity_type, entity_id, candidate_id, action, before_data, after_data, created_at"
+
+func scanAuditLog(row scanner) (*model.AuditLog, error) {
+	var (
+		result                              model.AuditLog
+		actorID, candidateID, before, after sql.NullString
+	)
+
+	err := row.Scan(&result.ID, &actorID, &result.Actor, &result.EntityType, &result.EntityID, &candidateID, &result.Action, &before, &after, &result.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ActorID = actorID.String
+	result.CandidateID = candidateID.String
+	if before.Valid {
+		result.Before = json.RawMessage(before.String)
+	}
+
+	if after.Valid {
+		result.After = json.RawMessage(after.String)
+	}
+
+	return &result, nil
+}
+
+// candidateAuditLogs returns the entries about a candidate, oldest first:
+// those recorded under the candidate, such as the changes of the candidate
+// and of their scores, applications and attachments. The entries of the
+// candidates merged into this one are included, as they were the same
+// person.
+func candidateAuditLogs(ctx context.Context, q querier, candidateID string) ([]model.AuditLog, error) {
+	var (
+		result = []model.AuditLog{}
+		ids    = []string{candidateID}
+		seen   = map[string]bool{candidateID: true}
+	)
+
+	SQL := "SELECT " + auditLogColumns + " FROM audit_log WHERE candidate_id = ?"
+	for len(ids) > 0 {
+		rows, err := q.QueryContext(ctx, SQL, ids[0])
+		if err != nil {
+			return nil, err
+		}
+		ids = ids[1:]
+
+		for rows.Next() {
+			log, err := scanAuditLog(rows)
+			if err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			result = append(result, *log)
+
+			if log.EntityType == model.EntityCandidate && log.Action == actionMerge && !seen[log.EntityID] {
+				seen[log.EntityID] = true
+				ids = append(ids, log.EntityID)
+			}
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// redactAuditLogs overwrites the columns holding personal data in the
+// snapshots of the entries about a candidate. columns gives, by entity type,
+// the value each column is replaced with.
+func redactAuditLogs(ctx context.Context, q querier, candidateID string, columns map[string]map[string]interface{}) error {
+	logs, err := candidateAuditLogs(ctx, q, candidateID)
+	if err != nil {
+		return err
+	}
+
+	for _, log := range logs {
+		redacted, ok := columns[log.EntityType]
+		if !ok {
+			continue
+		}
+
+		before, err := redactSnapshot(log.Before, redacted)
+		if err != nil {
+			return err
+		}
+
+		after, err := redactSnapshot(log.After, redacted)
+		if err != nil {
+			return err
+		}
+
+		SQL := "update audit_log set before_data = ?, after_data = ? where id = ?"
+		if _, err = q.ExecContext(ctx, SQL, nullString(string(before)), nullString(string(after)), log.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func redactSnapshot(data json.RawMessage, columns map[string]interface{}) (json.RawMessage, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var row map[string]interface{}
+	if err := json.Unmarshal(data, &row); err != nil {
+		return nil, err
+	}
+
+	for column, value := range columns {
+		if _, ok := row[column]; ok {
+			row[column] = value
+		}
+	}
+
+	return marshalSnapshot(row)
+}
+
+type AuditRepository interface {
+	GetAuditLogList(ctx context.Context, filter model.AuditLogListRequest) (*[]model.AuditLog, int, error)
+	GetAuditLogListByCandidateID(ctx context.Context, candidateID string) (*[]model.AuditLog, error)
+}
+
+type auditRepository struct {
+	DB *DB
+}
+
+func NewAuditRepository(db *DB) AuditRepository {
+	return &auditRepository{
+		db,
+	}
+}
+
+func (r *auditRepository) GetAuditLogList(ctx context.Context, filter model.AuditLogListRequest) (*[]model.AuditLog, int, error) {
+	var (
+		result     = []model.AuditLog{}
+		total      int
+		conditions []string
+		args       []interface{}
+	)
+
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+
+	if filter.EntityID != "" {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, filter.EntityID)
+	}
+
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, filter.Actor)
+	}
+
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+
+	SQL := "SELECT COUNT(*) FROM audit_log" + where(conditions)
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	SQL = "SELECT " + auditLogColumns + " FROM audit_log" + where(conditions) + " ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, append(args, filter.Size, filter.Offset())...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		log, err := scanAuditLog(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		result = append(result, *log)
+	}
+
+	return &result, total, nil
+}
+
+// GetAuditLogListByCandidateID returns every entry about a candidate, oldest
+// first.
+func (r *auditRepository) GetAuditLogListByCandidateID(ctx context.Context, candidateID string) (*[]model.AuditLog, error) {
+	result, err := candidateAuditLogs(ctx, conn(ctx, r.DB), candidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -4,61 +4,332 @@ import (
 	"context"
 	"database/sql"
 	"talentapp/model"
+	"time"
 )
 
 type CandidateRepository interface {
 	GetCandidateByID(ctx context.Context, id string) (*model.Candidate, error)
 	PostCandidate(ctx context.Context, model *model.Candidate) error
-	GetCandidateList(ctx context.Context) (*[]model.Candidate, error)
+	GetCandidateList(ctx context.Context, filter model.CandidateListRequest) (*[]model.Candidate, int, error)
+	UpdateCandidate(ctx context.Context, model *model.Candidate) error
+	DeleteCandidate(ctx context.Context, id string) error
+	LoadSkills(ctx context.Context, candidates ...*model.Candidate) error
+	GetSharedRecruitmentIDs(ctx context.Context, id, otherID string) ([]string, error)
+	MergeCandidate(ctx context.Context, id, duplicateID string) error
+	EraseCandidate(ctx context.Context, id string, erasedAt time.Time) error
+}
+
+var candidateSortColumns = map[string]string{
+	"name":       "name",
+	"experience": "experience",
+}
+
+// candidateSearchColumns are searched as one document, in the order of their
+// full-text index.
+var candidateSearchColumns = []string{"name", "address"}
+
+// candidateReferences are the tables whose rows move to the candidate a
+// duplicate is merged into. Skills are merged by the caller and deleted with
+// the duplicate.
+var candidateReferences = []string{"candidate_score", "scorecard", "application", "interviewer_assignment", "attachment", "candidate_consent"}
+
+// candidateRecruitments pairs every candidate with the recruitments they
+// take part in.
+const candidateRecruitments = "SELECT recruitment_id, candidate_id FROM candidate_score" +
+	" UNION SELECT recruitment_id, candidate_id FROM scorecard" +
+	" UNION SELECT recruitment_id, candidate_id FROM application" +
+	" UNION SELECT recruitment_id, candidate_id FROM interviewer_assignment"
+
+// erasedColumns are the columns of audit snapshots holding personal data of
+// a candidate, by entity type, with the value an erasure replaces them with.
+var erasedColumns = map[string]map[string]interface{}{
+	model.EntityCandidate:  {"name": model.ErasedCandidateName, "address": "", "email": nil, "phone": nil},
+	model.EntityScorecard:  {"comment": nil},
+	model.EntityAttachment: {"filename": model.ErasedFilename},
 }
 
 type candidateRepository struct {
-	DB *sql.DB
+	DB *DB
 }
 
-func NewCandidateRepository(db *sql.DB) CandidateRepository {
+func NewCandidateRepository(db *DB) CandidateRepository {
 	return &candidateRepository{DB: db}
 }
 
 func (r *candidateRepository) GetCandidateByID(ctx context.Context, id string) (*model.Candidate, error) {
-	var result model.Candidate
-	SQL := "SELECT id, name, address, experience, willing_to_relocate FROM candidate WHERE id = ?"
-	row := r.DB.QueryRowContext(ctx, SQL, id)
+	var (
+		result       model.Candidate
+		email, phone sql.NullString
+		erasedAt     sql.NullTime
+	)
+	SQL := "SELECT id, name, address, experience, willing_to_relocate, email, phone, erased_at FROM candidate WHERE id = ?"
+	row := conn(ctx, r.DB).QueryRowContext(ctx, SQL, id)
 
-	err := row.Scan(&result.ID, &result.Name, &result.Address, &result.Experience, &result.WillingToRelocate)
+	err := row.Scan(&result.ID, &result.Name, &result.Address, &result.Experience, &result.WillingToRelocate, &email, &phone, &erasedAt)
 	if err != nil {
 		return nil, err
 	}
 
+	result.Email, result.Phone = email.String, phone.String
+	if erasedAt.Valid {
+		result.ErasedAt = &erasedAt.Time
+	}
+
 	return &result, nil
 }
 
 func (r *candidateRepository) PostCandidate(ctx context.Context, model *model.Candidate) error {
-	SQL := "insert into candidate(id, name, address, experience, willing_to_relocate) values (?, ?, ?, ?, ?)"
-	if _, err := r.DB.ExecContext(ctx, SQL, model.ID, model.Name, model.Address, model.Experience, model.WillingToRelocate); err != nil {
+	return audited(ctx, r.DB, candidateAudit.entry(actionCreate, model.ID), func(ctx context.Context) error {
+		SQL := "insert into candidate(id, name, address, experience, willing_to_relocate, email, phone) values (?, ?, ?, ?, ?, ?, ?)"
+		_, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.Name, model.Address, model.Experience, model.WillingToRelocate,
+			nullString(model.Email), nullString(model.Phone))
+		if err != nil {
+			return err
+		}
+
+		return r.replaceSkills(ctx, model)
+	})
+}
+
+// replaceSkills stores the Skills of a candidate in place of the ones
+// stored before.
+func (r *candidateRepository) replaceSkills(ctx context.Context, model *model.Candidate) error {
+	q := conn(ctx, r.DB)
+	if _, err := q.ExecContext(ctx, "delete from candidate_skills where candidate_id = ?", model.ID); err != nil {
 		return err
 	}
 
+	for _, skill := range model.Skills {
+		if _, err := q.ExecContext(ctx, "insert into candidate_skills(candidate_id, skill) values (?, ?)", model.ID, skill); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (r *candidateRepository) GetCandidateList(ctx context.Context) (*[]model.Candidate, error) {
-	var result []model.Candidate
-	SQL := "SELECT id, name, address, experience, willing_to_relocate FROM candidate LIMIT ? OFFSET ?"
-	rows, err := r.DB.QueryContext(ctx, SQL, 10, 0) // TODO: make dynamic pagination
+// LoadSkills fills the Skills of every candidate.
+func (r *candidateRepository) LoadSkills(ctx context.Context, candidates ...*model.Candidate) error {
+	return loadSkills(ctx, conn(ctx, r.DB), candidates)
+}
+
+func (r *candidateRepository) GetCandidateList(ctx context.Context, filter model.CandidateListRequest) (*[]model.Candidate, int, error) {
+	var (
+		result     = []model.Candidate{}
+		total      int
+		conditions []string
+		args       []interface{}
+	)
+
+	if filter.ExperienceMin != nil {
+		conditions = append(conditions, "experience >= ?")
+		args = append(args, *filter.ExperienceMin)
+	}
+
+	if filter.ExperienceMax != nil {
+		conditions = append(conditions, "experience <= ?")
+		args = append(args, *filter.ExperienceMax)
+	}
+
+	if filter.WillingToRelocate != "" {
+		conditions = append(conditions, "willing_to_relocate = ?")
+		args = append(args, filter.WillingToRelocate)
+	}
+
+	if filter.AssignedTo != "" {
+		conditions = append(conditions, "id IN (SELECT candidate_id FROM interviewer_assignment WHERE user_id = ?)")
+		args = append(args, filter.AssignedTo)
+	}
+
+	// a search ranks the candidates by relevance unless another sort is asked
+	search, fallback := textSearch{relevance: "0"}, "name ASC"
+	if terms, ok := searchTerms(filter.Query); ok {
+		search = r.DB.Dialect.textSearch(terms, candidateSearchColumns...).or(skillSearch(filter.Query))
+		conditions = append(conditions, search.condition)
+		args = append(args, search.args...)
+		fallback = "relevance DESC, name ASC"
+	}
+
+	SQL := "SELECT COUNT(*) FROM candidate" + where(conditions)
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	SQL = "SELECT id, name, address, experience, willing_to_relocate, email, phone, erased_at, " + search.relevance + " AS relevance FROM candidate" + where(conditions) +
+		orderBy(filter.Sort, candidateSortColumns, fallback, "id") + " LIMIT ? OFFSET ?"
+	queryArgs := append([]interface{}{}, search.relevanceArgs...)
+	queryArgs = append(queryArgs, args...)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, append(queryArgs, filter.Size, filter.Offset())...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		candidate := model.Candidate{}
-		err = rows.Scan(&candidate.ID, &candidate.Name, &candidate.Address, &candidate.Experience, &candidate.WillingToRelocate)
+		var (
+			candidate    = model.Candidate{}
+			email, phone sql.NullString
+			erasedAt     sql.NullTime
+		)
+
+		err = rows.Scan(&candidate.ID, &candidate.Name, &candidate.Address, &candidate.Experience, &candidate.WillingToRelocate,
+			&email, &phone, &erasedAt, &candidate.Relevance)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
+		}
+
+		candidate.Email, candidate.Phone = email.String, phone.String
+		if erasedAt.Valid {
+			candidate.ErasedAt = &erasedAt.Time
 		}
 		result = append(result, candidate)
 	}
 
-	return &result, nil
+	return &result, total, nil
+}
+
+func (r *candidateRepository) UpdateCandidate(ctx context.Context, model *model.Candidate) error {
+	return audited(ctx, r.DB, candidateAudit.entry(actionUpdate, model.ID), func(ctx context.Context) error {
+		SQL := "update candidate set name = ?, address = ?, experience = ?, willing_to_relocate = ?, email = ?, phone = ? where id = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.Name, model.Address, model.Experience, model.WillingToRelocate,
+			nullString(model.Email), nullString(model.Phone), model.ID)
+		if err != nil {
+			return err
+		}
+
+		if err = affected(res); err != nil {
+			return err
+		}
+
+		return r.replaceSkills(ctx, model)
+	})
+}
+
+func (r *candidateRepository) DeleteCandidate(ctx context.Context, id string) error {
+	return audited(ctx, r.DB, candidateAudit.entry(actionDelete, id), func(ctx context.Context) error {
+		SQL := "delete from candidate where id = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, id)
+		if err != nil {
+			return err
+		}
+
+		return affected(res)
+	})
+}
+
+// GetSharedRecruitmentIDs returns the recruitments both candidates take part
+// in, through a score, a scorecard, an application or an interviewer.
+func (r *candidateRepository) GetSharedRecruitmentIDs(ctx context.Context, id, otherID string) ([]string, error) {
+	var result []string
+
+	SQL := "SELECT DISTINCT a.recruitment_id FROM (" + candidateRecruitments + ") a JOIN (" + candidateRecruitments + ") b ON b.recruitment_id = a.recruitment_id" +
+		" WHERE a.candidate_id = ? AND b.candidate_id = ? ORDER BY a.recruitment_id"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, id, otherID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recruitmentID string
+		if err = rows.Scan(&recruitmentID); err != nil {
+			return nil, err
+		}
+		result = append(result, recruitmentID)
+	}
+
+	return result, rows.Err()
+}
+
+// MergeCandidate moves the rows referencing the duplicate to the candidate
+// with id and deletes the duplicate. The merge is audited under the
+// duplicate, with the candidate it was merged into as the state after.
+func (r *candidateRepository) MergeCandidate(ctx context.Context, id, duplicateID string) error {
+	return withinTransaction(ctx, r.DB, func(ctx context.Context) error {
+		var (
+			q     = conn(ctx, r.DB)
+			entry = candidateAudit.entry(actionMerge, duplicateID)
+		)
+
+		before, err := snapshot(ctx, q, entry)
+		if err != nil {
+			return err
+		}
+
+		if before == nil {
+			return sql.ErrNoRows
+		}
+
+		for _, table := range candidateReferences {
+			if _, err = q.ExecContext(ctx, "update "+table+" set candidate_id = ? where candidate_id = ?", id, duplicateID); err != nil {
+				return err
+			}
+		}
+
+		res, err := q.ExecContext(ctx, "delete from candidate where id = ?", duplicateID)
+		if err != nil {
+			return err
+		}
+
+		if err = affected(res); err != nil {
+			return err
+		}
+
+		after, err := snapshot(ctx, q, candidateAudit.entry(actionMerge, id))
+		if err != nil {
+			return err
+		}
+
+		if after == nil {
+			return sql.ErrNoRows
+		}
+
+		return recordAudit(ctx, q, entry, before, after)
+	})
+}
+
+// EraseCandidate anonymizes a candidate that has not been erased yet. The
+// row is kept for the scores and applications referencing it, but the
+// personal data of the candidate, their skills, their consents and the
+// comments and notes about them are cleared, also from the audit log. The
+// erasure is audited without the state before it.
+func (r *candidateRepository) EraseCandidate(ctx context.Context, id string, erasedAt time.Time) error {
+	return withinTransaction(ctx, r.DB, func(ctx context.Context) error {
+		var (
+			q     = conn(ctx, r.DB)
+			entry = candidateAudit.entry(actionErase, id)
+		)
+
+		SQL := "update candidate set name = ?, address = ?, email = NULL, phone = NULL, erased_at = ? where id = ? and erased_at is null"
+		res, err := q.ExecContext(ctx, SQL, model.ErasedCandidateName, "", erasedAt, id)
+		if err != nil {
+			return err
+		}
+
+		if err = affected(res); err != nil {
+			return err
+		}
+
+		for _, SQL := range []string{
+			"delete from candidate_skills where candidate_id = ?",
+			"delete from candidate_consent where candidate_id = ?",
+			"update scorecard set comment = NULL where candidate_id = ?",
+			"update application_stage_event set note = NULL where application_id in (select id from application where candidate_id = ?)",
+		} {
+			if _, err = q.ExecContext(ctx, SQL, id); err != nil {
+				return err
+			}
+		}
+
+		if err = redactAuditLogs(ctx, q, id, erasedColumns); err != nil {
+			return err
+		}
+
+		after, err := snapshot(ctx, q, entry)
+		if err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, entry, nil, after)
+	})
 }
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"talentapp/model"
+	"time"
+)
+
+type CandidateConsentRepository interface {
+	GetConsentByID(ctx context.Context, id string) (*model.CandidateConsent, error)
+	GetConsentListByCandidateID(ctx context.Context, candidateID string) (*[]model.CandidateConsent, error)
+	PostConsent(ctx context.Context, model *model.CandidateConsent) error
+	WithdrawConsent(ctx context.Context, id string, withdrawnAt time.Time) error
+	LoadConsents(ctx context.Context, candidates ...*model.Candidate) error
+}
+
+const consentColumns = "id, candidate_id, purpose, source, granted_at, expires_at, withdrawn_at, recorded_by, created_at"
+
+type candidateConsentRepository struct {
+	DB *DB
+}
+
+func NewCandidateConsentRepository(db *DB) CandidateConsentRepository {
+	return &candidateConsentRepository{DB: db}
+}
+
+func scanConsent(row scanner) (*model.CandidateConsent, error) {
+	var (
+		result      model.CandidateConsent
+		withdrawnAt sql.NullTime
+	)
+
+	err := row.Scan(&result.ID, &result.CandidateID, &result.Purpose, &result.Source, &result.GrantedAt, &result.ExpiresAt,
+		&withdrawnAt, &result.RecordedBy, &result.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if withdrawnAt.Valid {
+		result.WithdrawnAt = &withdrawnAt.Time
+	}
+
+	return &result, nil
+}
+
+func (r *candidateConsentRepository) GetConsentByID(ctx context.Context, id string) (*model.CandidateConsent, error) {
+	SQL := "SELECT " + consentColumns + " FROM candidate_consent WHERE id = ?"
+	return scanConsent(conn(ctx, r.DB).QueryRowContext(ctx, SQL, id))
+}
+
+// GetConsentListByCandidateID returns the consents of a candidate, most
+// recently granted first.
+func (r *candidateConsentRepository) GetConsentListByCandidateID(ctx context.Context, candidateID string) (*[]model.CandidateConsent, error) {
+	var result = []model.CandidateConsent{}
+	SQL := "SELECT " + consentColumns + " FROM candidate_consent WHERE candidate_id = ? ORDER BY granted_at DESC, created_at DESC, id"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		consent, err := scanConsent(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *consent)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// LoadConsents fills the Consents of every candidate.
+func (r *candidateConsentRepository) LoadConsents(ctx context.Context, candidates ...*model.Candidate) error {
+	return loadConsents(ctx, conn(ctx, r.DB), candidates)
+}
+
+func (r *candidateConsentRepository) PostConsent(ctx context.Context, model *model.CandidateConsent) error {
+	return audited(ctx, r.DB, candidateConsentAudit.entry(actionCreate, model.ID), func(ctx context.Context) error {
+		SQL := "insert into candidate_consent(" + consentColumns + ") values (?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		_, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.CandidateID, model.Purpose, model.Source, model.GrantedAt,
+			model.ExpiresAt, nullTime(model.WithdrawnAt), model.RecordedBy, model.CreatedAt)
+
+		return err
+	})
+}
+
+// WithdrawConsent ends a consent that has not been withdrawn yet.
+func (r *candidateConsentRepository) WithdrawConsent(ctx context.Context, id string, withdrawnAt time.Time) error {
+	return audited(ctx, r.DB, candidateConsentAudit.entry(actionUpdate, id), func(ctx context.Context) error {
+		SQL := "update candidate_consent set withdrawn_at = ? where id = ? and withdrawn_at is null"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, withdrawnAt, id)
+		if err != nil {
+			return err
+		}
+
+		return affected(res)
+	})
+}
@@ -9,73 +9,233 @@ import (
 type CandidateScoreRepository interface {
 	GetCandidateScoreByID(ctx context.Context, id, candidateID string) (*model.CandidateScore, error)
 	PostCandidateScore(ctx context.Context, model *model.CandidateScore) error
-	GetCandidateScoreListByRecruitmentID(ctx context.Context, recruitmentID string) (*[]model.CandidateScore, error)
+	GetCandidateScoreListByRecruitmentID(ctx context.Context, recruitmentID string, filter model.CandidateScoreListRequest) (*[]model.CandidateScore, int, error)
+	GetCandidateScoreListByCandidateID(ctx context.Context, candidateID string) (*[]model.CandidateScore, error)
+	UpdateCandidateScore(ctx context.Context, model *model.CandidateScore) error
+	DeleteCandidateScore(ctx context.Context, recruitmentID, candidateID string) error
+	CountCandidateScore(ctx context.Context, recruitmentID, candidateID string) (int, error)
+	CountCandidateScoreByCandidateID(ctx context.Context, candidateID string) (int, error)
+	CountCandidateScoreByRecruitmentID(ctx context.Context, recruitmentID string) (int, error)
+	LoadRelations(ctx context.Context, scores ...*model.CandidateScore) error
+}
+
+var candidateScoreSortColumns = map[string]string{
+	"overall_score": "cs.overall_score",
+	"name":          "c.name",
+	"experience":    "c.experience",
 }
 
 type candidateScoreRepository struct {
-	DB *sql.DB
+	DB *DB
 }
 
-func NewCandidateScoreRepository(db *sql.DB) CandidateScoreRepository {
+func NewCandidateScoreRepository(db *DB) CandidateScoreRepository {
 	return &candidateScoreRepository{DB: db}
 }
 
-func (r *candidateScoreRepository) GetCandidateScoreByID(ctx context.Context, id, candidateID string) (*model.CandidateScore, error) {
-	var result model.CandidateScore
-	SQL := "SELECT id, candidate_id, recruitment_id, willing_to_relocate_score, attitude_score, skill_score, experience_score, overall_score FROM candidate_score WHERE recruitment_id = ? AND candidate_id = ?"
-	rows, err := r.DB.QueryContext(ctx, SQL, id, candidateID)
-	if err != nil {
+// candidateScoreColumns selects a score with its rank in the recruitment, its
+// candidate and its recruitment with the job, from candidateScoreTables.
+const (
+	candidateScoreColumns = "cs.id, cs.candidate_id, cs.recruitment_id, cs.willing_to_relocate_score, cs.attitude_score, cs.skill_score, cs.experience_score, cast(cs.overall_score as decimal(5,2)), cs.scoring_profile_id, sp.version, cs.interviewer_count, cs.attitude_spread, cs.skill_spread, " +
+		"(SELECT COUNT(*) FROM candidate_score x WHERE x.recruitment_id = cs.recruitment_id AND x.overall_score > cs.overall_score) + 1, " +
+		"c.id, c.name, c.address, c.experience, c.willing_to_relocate, " + recruitmentColumns
+	candidateScoreTables = "candidate_score cs JOIN candidate c ON c.id = cs.candidate_id JOIN recruitment r ON r.id = cs.recruitment_id JOIN job j ON j.id = r.job_id LEFT JOIN scoring_profile sp ON sp.id = cs.scoring_profile_id"
+)
+
+func scanCandidateScore(row scanner) (*model.CandidateScore, error) {
+	var (
+		result         model.CandidateScore
+		candidate      model.Candidate
+		recruitment    recruitmentRow
+		profileID      sql.NullString
+		profileVersion sql.NullInt64
+	)
+
+	dest := []interface{}{
+		&result.ID, &result.CandidateID, &result.RecruitmentID, &result.WillingToRelocateScore, &result.AttitudeScore, &result.SkillScore, &result.ExperienceScore, &result.OverallScore, &profileID, &profileVersion, &result.InterviewerCount, &result.AttitudeSpread, &result.SkillSpread, &result.Rank,
+		&candidate.ID, &candidate.Name, &candidate.Address, &candidate.Experience, &candidate.WillingToRelocate,
+	}
+
+	if err := row.Scan(append(dest, recruitment.dest()...)...); err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	rows.Scan(&result.ID, &result.CandidateID, &result.RecruitmentID, &result.WillingToRelocateScore, &result.AttitudeScore, &result.SkillScore, &result.ExperienceScore, &result.OverallScore)
+	result.ScoringProfileID = profileID.String
+	result.ScoringProfileVersion = int(profileVersion.Int64)
+	result.Candidate = &candidate
+	result.Recruitment = recruitment.value()
 
 	return &result, nil
 }
 
-func (r *candidateScoreRepository) PostCandidateScore(ctx context.Context, model *model.CandidateScore) error {
-	SQL := "insert into candidate_score(id, candidate_id, recruitment_id, willing_to_relocate_score, attitude_score, skill_score, experience_score, overall_score) values (?, ?, ?, ?, ?, ?, ?, ?)"
-	if _, err := r.DB.ExecContext(ctx, SQL, model.ID, model.CandidateID, model.RecruitmentID, model.WillingToRelocateScore, model.AttitudeScore, model.SkillScore, model.ExperienceScore, model.OverallScore); err != nil {
+// GetCandidateScoreByID returns the score of a candidate in the recruitment
+// id, with its relations, or sql.ErrNoRows.
+func (r *candidateScoreRepository) GetCandidateScoreByID(ctx context.Context, id, candidateID string) (*model.CandidateScore, error) {
+	SQL := "SELECT " + candidateScoreColumns + " FROM " + candidateScoreTables + " WHERE cs.recruitment_id = ? AND cs.candidate_id = ?"
+	row := conn(ctx, r.DB).QueryRowContext(ctx, SQL, id, candidateID)
+
+	return scanCandidateScore(row)
+}
+
+// LoadRelations fills the Candidate and the Recruitment, with its job, of
+// every score.
+func (r *candidateScoreRepository) LoadRelations(ctx context.Context, scores ...*model.CandidateScore) error {
+	q := conn(ctx, r.DB)
+	if err := loadCandidates(ctx, q, scores); err != nil {
 		return err
 	}
 
-	return nil
+	return loadRecruitments(ctx, q, scores)
 }
 
-func (r *candidateScoreRepository) GetCandidateScoreListByRecruitmentID(ctx context.Context, recruitmentID string) (*[]model.CandidateScore, error) {
-	var result []model.CandidateScore
-	SQL := "SELECT id, candidate_id, recruitment_id, willing_to_relocate_score, attitude_score, skill_score, experience_score, cast(overall_score as decimal(5,2)) FROM candidate_score WHERE recruitment_id = ? ORDER BY overall_score DESC LIMIT ? OFFSET ?"
-	rows, err := r.DB.QueryContext(ctx, SQL, recruitmentID, 10, 0) // TODO: make dynamic pagination
+func (r *candidateScoreRepository) PostCandidateScore(ctx context.Context, model *model.CandidateScore) error {
+	return audited(ctx, r.DB, candidateScoreAudit.entry(actionCreate, model.RecruitmentID, model.CandidateID), func(ctx context.Context) error {
+		SQL := "insert into candidate_score(id, candidate_id, recruitment_id, willing_to_relocate_score, attitude_score, skill_score, experience_score, overall_score, scoring_profile_id, interviewer_count, attitude_spread, skill_spread) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.CandidateID, model.RecruitmentID, model.WillingToRelocateScore, model.AttitudeScore, model.SkillScore, model.ExperienceScore, model.OverallScore, nullString(model.ScoringProfileID), model.InterviewerCount, model.AttitudeSpread, model.SkillSpread); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *candidateScoreRepository) GetCandidateScoreListByRecruitmentID(ctx context.Context, recruitmentID string, filter model.CandidateScoreListRequest) (*[]model.CandidateScore, int, error) {
+	var (
+		result     = []model.CandidateScore{}
+		total      int
+		conditions = []string{"cs.recruitment_id = ?"}
+		args       = []interface{}{recruitmentID}
+	)
+
+	if filter.ExperienceMin != nil {
+		conditions = append(conditions, "c.experience >= ?")
+		args = append(args, *filter.ExperienceMin)
+	}
+
+	if filter.ExperienceMax != nil {
+		conditions = append(conditions, "c.experience <= ?")
+		args = append(args, *filter.ExperienceMax)
+	}
+
+	if filter.WillingToRelocate != "" {
+		conditions = append(conditions, "c.willing_to_relocate = ?")
+		args = append(args, filter.WillingToRelocate)
+	}
+
+	if filter.AssignedTo != "" {
+		conditions = append(conditions, "cs.candidate_id IN (SELECT ia.candidate_id FROM interviewer_assignment ia WHERE ia.recruitment_id = cs.recruitment_id AND ia.user_id = ?)")
+		args = append(args, filter.AssignedTo)
+	}
+
+	SQL := "SELECT COUNT(*) FROM candidate_score cs JOIN candidate c ON c.id = cs.candidate_id" + where(conditions)
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// the candidate, the recruitment and its job are joined in, so a page
+	// takes the same two queries however many scores it has
+	SQL = "SELECT " + candidateScoreColumns + " FROM " + candidateScoreTables +
+		where(conditions) + orderBy(filter.Sort, candidateScoreSortColumns, "cs.overall_score DESC", "cs.id") + " LIMIT ? OFFSET ?"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, append(args, filter.Size, filter.Offset())...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		candidateScore := model.CandidateScore{}
-		err = rows.Scan(&candidateScore.ID, &candidateScore.CandidateID, &candidateScore.RecruitmentID, &candidateScore.WillingToRelocateScore, &candidateScore.AttitudeScore, &candidateScore.SkillScore, &candidateScore.ExperienceScore, &candidateScore.OverallScore)
+		candidateScore, err := scanCandidateScore(rows)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
-		err = candidateScore.LoadCandidate(ctx)
+		result = append(result, *candidateScore)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return &result, total, nil
+}
+
+// GetCandidateScoreListByCandidateID returns the scores of a candidate in
+// every recruitment, each with its rank in that recruitment, latest deadline
+// first.
+func (r *candidateScoreRepository) GetCandidateScoreListByCandidateID(ctx context.Context, candidateID string) (*[]model.CandidateScore, error) {
+	var result = []model.CandidateScore{}
+	SQL := "SELECT " + candidateScoreColumns + " FROM " + candidateScoreTables + " WHERE cs.candidate_id = ? ORDER BY r.deadline DESC, cs.id"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, candidateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		candidateScore, err := scanCandidateScore(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		err = candidateScore.LoadRecruitment(ctx)
+		result = append(result, *candidateScore)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *candidateScoreRepository) UpdateCandidateScore(ctx context.Context, model *model.CandidateScore) error {
+	return audited(ctx, r.DB, candidateScoreAudit.entry(actionUpdate, model.RecruitmentID, model.CandidateID), func(ctx context.Context) error {
+		SQL := "update candidate_score set willing_to_relocate_score = ?, attitude_score = ?, skill_score = ?, experience_score = ?, overall_score = ?, scoring_profile_id = ?, interviewer_count = ?, attitude_spread = ?, skill_spread = ? where recruitment_id = ? and candidate_id = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.WillingToRelocateScore, model.AttitudeScore, model.SkillScore, model.ExperienceScore, model.OverallScore, nullString(model.ScoringProfileID), model.InterviewerCount, model.AttitudeSpread, model.SkillSpread, model.RecruitmentID, model.CandidateID)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		err = candidateScore.Recruitment.LoadJob(ctx)
+		return affected(res)
+	})
+}
+
+func (r *candidateScoreRepository) DeleteCandidateScore(ctx context.Context, recruitmentID, candidateID string) error {
+	return audited(ctx, r.DB, candidateScoreAudit.entry(actionDelete, recruitmentID, candidateID), func(ctx context.Context) error {
+		SQL := "delete from candidate_score where recruitment_id = ? and candidate_id = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, recruitmentID, candidateID)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		result = append(result, candidateScore)
+		return affected(res)
+	})
+}
+
+func (r *candidateScoreRepository) CountCandidateScore(ctx context.Context, recruitmentID, candidateID string) (int, error) {
+	var total int
+	SQL := "SELECT COUNT(*) FROM candidate_score WHERE recruitment_id = ? AND candidate_id = ?"
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, recruitmentID, candidateID).Scan(&total); err != nil {
+		return 0, err
 	}
 
-	return &result, nil
+	return total, nil
+}
+
+func (r *candidateScoreRepository) CountCandidateScoreByCandidateID(ctx context.Context, candidateID string) (int, error) {
+	var total int
+	SQL := "SELECT COUNT(*) FROM candidate_score WHERE candidate_id = ?"
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, candidateID).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *candidateScoreRepository) CountCandidateScoreByRecruitmentID(ctx context.Context, recruitmentID string) (int, error) {
+	var total int
+	SQL := "SELECT COUNT(*) FROM candidate_score WHERE recruitment_id = ?"
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, recruitmentID).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
 }
@@ -0,0 +1,453 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"talentapp/driver/db/mysql"
+	"talentapp/driver/db/postgres"
+	"talentapp/driver/db/sqlite"
+	"talentapp/model"
+	"talentapp/repository"
+	"talentapp/repository/memory"
+	"testing"
+	"time"
+)
+
+// repositories are the implementations under contract, all backed by the
+// same database.
+type repositories struct {
+	job            repository.JobRepository
+	recruitment    repository.RecruitmentRepository
+	candidate      repository.CandidateRepository
+	candidateScore repository.CandidateScoreRepository
+	scoringProfile repository.ScoringProfileRepository
+	lease          repository.LeaseRepository
+	audit          repository.AuditRepository
+	transactor     repository.Transactor
+}
+
+func sqlRepositories(db *repository.DB) repositories {
+	return repositories{
+		job:            repository.NewJobRepository(db),
+		recruitment:    repository.NewRecruitmentRepository(db),
+		candidate:      repository.NewCandidateRepository(db),
+		candidateScore: repository.NewCandidateScoreRepository(db),
+		scoringProfile: repository.NewScoringProfileRepository(db),
+		lease:          repository.NewLeaseRepository(db),
+		audit:          repository.NewAuditRepository(db),
+		transactor:     repository.NewTransactor(db),
+	}
+}
+
+func TestMemoryContract(t *testing.T) {
+	store := memory.NewStore()
+
+	testContract(t, repositories{
+		job:            memory.NewJobRepository(store),
+		recruitment:    memory.NewRecruitmentRepository(store),
+		candidate:      memory.NewCandidateRepository(store),
+		candidateScore: memory.NewCandidateScoreRepository(store),
+		scoringProfile: memory.NewScoringProfileRepository(store),
+		lease:          memory.NewLeaseRepository(store),
+		audit:          memory.NewAuditRepository(store),
+		transactor:     memory.NewTransactor(store),
+	})
+}
+
+func TestSQLiteContract(t *testing.T) {
+	db := sqlite.ConnectDB(filepath.Join(t.TempDir(), "talentapp.db"))
+	defer db.Close()
+
+	if err := sqlite.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	testContract(t, sqlRepositories(repository.NewDB(db, repository.SQLite)))
+}
+
+// TestPostgresContract runs against the database named by
+// TEST_POSTGRES_DSN, and is skipped when it is not set.
+func TestPostgresContract(t *testing.T) {
+	db := open(t, "postgres", "TEST_POSTGRES_DSN")
+
+	if err := postgres.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	testContract(t, sqlRepositories(repository.NewDB(db, repository.Postgres)))
+}
+
+// TestMySQLContract runs against the database named by TEST_MYSQL_DSN,
+// which needs parseTime, multiStatements and clientFoundRows, and is skipped
+// when it is not set.
+func TestMySQLContract(t *testing.T) {
+	db := open(t, "mysql", "TEST_MYSQL_DSN")
+
+	if err := mysql.Migrate(db, "file://../driver/db/mysql/migrations"); err != nil {
+		t.Fatal(err)
+	}
+
+	testContract(t, sqlRepositories(repository.NewDB(db, repository.MySQL)))
+}
+
+func open(t *testing.T, driver, env string) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv(env)
+	if dsn == "" {
+		t.Skip(env + " is not set")
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// testContract checks the behaviour every implementation shares. Records
+// are named after a random tag, so the suite can run again on a database
+// that keeps the records of earlier runs.
+func testContract(t *testing.T, repos repositories) {
+	tag := randomWord()
+
+	t.Run("Constraints", func(t *testing.T) { testConstraints(t, repos, tag) })
+	t.Run("Search", func(t *testing.T) { testSearch(t, repos, tag) })
+	t.Run("Ranking", func(t *testing.T) { testRanking(t, repos, tag) })
+	t.Run("ScoringProfileVersions", func(t *testing.T) { testScoringProfileVersions(t, repos, tag) })
+	t.Run("Lease", func(t *testing.T) { testLease(t, repos, tag) })
+	t.Run("Transaction", func(t *testing.T) { testTransaction(t, repos, tag) })
+	t.Run("CandidateAudit", func(t *testing.T) { testCandidateAudit(t, repos, tag) })
+}
+
+// random differs between runs, which name their records after it.
+var random = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// randomWord returns lowercase letters only, so every dialect reads it as a
+// single search term.
+func randomWord() string {
+	letters := make([]byte, 10)
+	for i := range letters {
+		letters[i] = byte('a' + random.Intn(26))
+	}
+
+	return string(letters)
+}
+
+func postJob(t *testing.T, repos repositories, id string) {
+	t.Helper()
+
+	if err := repos.job.PostJob(context.Background(), &model.Job{ID: id, Position: "Developer " + id}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func postRecruitment(t *testing.T, repos repositories, id, jobID string) {
+	t.Helper()
+
+	err := repos.recruitment.CreateRecruitment(context.Background(), &model.Recruitment{
+		ID:               id,
+		JobID:            jobID,
+		Status:           model.RecruitmentOpen,
+		Deadline:         time.Now().Add(24 * time.Hour),
+		ScoreAggregation: "mean",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func postCandidate(t *testing.T, repos repositories, id, name string) {
+	t.Helper()
+
+	err := repos.candidate.PostCandidate(context.Background(), &model.Candidate{
+		ID:                id,
+		Name:              name,
+		Address:           "Harbor Road 1",
+		Experience:        3,
+		WillingToRelocate: "yes",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func postScore(repos repositories, id, recruitmentID, candidateID string, overall float64) error {
+	return repos.candidateScore.PostCandidateScore(context.Background(), &model.CandidateScore{
+		ID:            id,
+		RecruitmentID: recruitmentID,
+		CandidateID:   candidateID,
+		OverallScore:  overall,
+	})
+}
+
+func testConstraints(t *testing.T, repos repositories, tag string) {
+	ctx := context.Background()
+
+	job, recruitment, candidate := tag+"-job", tag+"-recruitment", tag+"-candidate"
+	postJob(t, repos, job)
+	postRecruitment(t, repos, recruitment, job)
+	postCandidate(t, repos, candidate, "Constraint "+tag)
+
+	if err := postScore(repos, tag+"-score", recruitment, candidate, 80); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range map[string]struct {
+		err  error
+		want error
+	}{
+		"same job id":             {repos.job.PostJob(ctx, &model.Job{ID: job}), repository.ErrDuplicate},
+		"second score":            {postScore(repos, tag+"-again", recruitment, candidate, 70), repository.ErrDuplicate},
+		"score of no recruitment": {postScore(repos, tag+"-orphan", tag+"-missing", candidate, 70), repository.ErrReferenced},
+		"job in use":              {repos.job.DeleteJob(ctx, job), repository.ErrReferenced},
+		"candidate in use":        {repos.candidate.DeleteCandidate(ctx, candidate), repository.ErrReferenced},
+	} {
+		if !errors.Is(test.err, test.want) {
+			t.Errorf("%s: got %v, want %v", name, test.err, test.want)
+		}
+	}
+
+	if _, err := repos.job.GetJobByID(ctx, tag+"-missing"); err != sql.ErrNoRows {
+		t.Errorf("got %v for a missing job, want sql.ErrNoRows", err)
+	}
+}
+
+func testSearch(t *testing.T, repos repositories, tag string) {
+	// the other subtests name their candidates after tag too
+	word := randomWord()
+
+	annabel, brian := tag+"-annabel", tag+"-brian"
+	postCandidate(t, repos, annabel, "Annabel "+word)
+	postCandidate(t, repos, brian, "Brian "+word)
+
+	search := func(query string) []model.Candidate {
+		t.Helper()
+
+		result, _, err := repos.candidate.GetCandidateList(context.Background(), model.CandidateListRequest{
+			Pagination: model.Pagination{Page: 1, Size: 100},
+			Query:      query,
+		})
+		if err != nil {
+			t.Fatalf("%q: %v", query, err)
+		}
+
+		return *result
+	}
+
+	// the start of a word finds it
+	if found := search(word[:5]); len(found) != 2 {
+		t.Errorf("got %d candidates for the prefix of their name, want 2", len(found))
+	}
+
+	// the candidate matching more terms comes first
+	found := search(word + " annabel")
+	if len(found) < 2 || found[0].ID != annabel {
+		t.Fatalf("got %v, want %s first", found, annabel)
+	}
+
+	if found[0].Relevance <= found[1].Relevance {
+		t.Errorf("got relevance %v then %v, want the first higher", found[0].Relevance, found[1].Relevance)
+	}
+
+	// the middle of a word does not find it
+	if found := search(word[2:]); len(found) != 0 {
+		t.Errorf("got %d candidates for the middle of their name, want none", len(found))
+	}
+}
+
+func testRanking(t *testing.T, repos repositories, tag string) {
+	job, recruitment := tag+"-rank-job", tag+"-rank-recruitment"
+	postJob(t, repos, job)
+	postRecruitment(t, repos, recruitment, job)
+
+	want := map[string]int{}
+	for i, overall := range []float64{70.5, 90, 70.5} {
+		candidate := tag + "-rank-" + string(rune('a'+i))
+		postCandidate(t, repos, candidate, "Ranked "+tag)
+
+		if err := postScore(repos, candidate, recruitment, candidate, overall); err != nil {
+			t.Fatal(err)
+		}
+
+		want[candidate] = map[float64]int{90: 1, 70.5: 2}[overall]
+	}
+
+	scores, total, err := repos.candidateScore.GetCandidateScoreListByRecruitmentID(context.Background(), recruitment, model.CandidateScoreListRequest{
+		Pagination: model.Pagination{Page: 1, Size: 100},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 3 || len(*scores) != 3 {
+		t.Fatalf("got %d of %d scores, want 3", len(*scores), total)
+	}
+
+	if first := (*scores)[0]; first.OverallScore != 90 {
+		t.Errorf("got %v first, want the highest score", first.OverallScore)
+	}
+
+	for _, score := range *scores {
+		if score.Rank != want[score.CandidateID] {
+			t.Errorf("%s: got rank %d, want %d", score.CandidateID, score.Rank, want[score.CandidateID])
+		}
+	}
+}
+
+func testScoringProfileVersions(t *testing.T, repos repositories, tag string) {
+	ctx := context.Background()
+
+	job := tag + "-profile-job"
+	postJob(t, repos, job)
+
+	for i, id := range []string{tag + "-profile-1", tag + "-profile-2"} {
+		profile := model.DefaultScoringProfile(job)
+		profile.ID, profile.CreatedAt = id, time.Now()
+
+		if err := repos.scoringProfile.PostScoringProfile(ctx, profile); err != nil {
+			t.Fatal(err)
+		}
+
+		if profile.Version != i+1 {
+			t.Errorf("%s: got version %d, want %d", id, profile.Version, i+1)
+		}
+	}
+
+	latest, err := repos.scoringProfile.GetLatestScoringProfileByJobID(ctx, job)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest.ID != tag+"-profile-2" || latest.Version != 2 {
+		t.Errorf("got %s version %d as the latest, want %s-profile-2 version 2", latest.ID, latest.Version, tag)
+	}
+}
+
+func testLease(t *testing.T, repos repositories, tag string) {
+	ctx := context.Background()
+	name := tag + "-lease"
+
+	for _, step := range []struct {
+		holder string
+		ttl    time.Duration
+		want   bool
+	}{
+		{"first", time.Minute, true},
+		{"second", time.Minute, false},
+		// the holder extends its lease
+		{"first", time.Minute, true},
+		{"second", time.Minute, false},
+		// a lease ending in the past is free
+		{"first", -time.Minute, true},
+		{"second", time.Minute, true},
+		{"first", time.Minute, false},
+	} {
+		got, err := repos.lease.AcquireLease(ctx, name, step.holder, step.ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != step.want {
+			t.Fatalf("%s acquiring for %v: got %v, want %v", step.holder, step.ttl, got, step.want)
+		}
+	}
+
+	// only the holder releases the lease
+	if err := repos.lease.ReleaseLease(ctx, name, "first"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := repos.lease.AcquireLease(ctx, name, "first", time.Minute); err != nil || got {
+		t.Errorf("got %v, %v after another holder released the lease, want false", got, err)
+	}
+}
+
+func testTransaction(t *testing.T, repos repositories, tag string) {
+	ctx := context.Background()
+	failed := errors.New("failed")
+	rolledBack, committed := tag+"-rolled-back", tag+"-committed"
+
+	err := repos.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := repos.candidate.PostCandidate(ctx, &model.Candidate{ID: rolledBack, Name: "Rolled back", WillingToRelocate: "no"}); err != nil {
+			return err
+		}
+
+		// reads within the transaction see its writes
+		if _, err := repos.candidate.GetCandidateByID(ctx, rolledBack); err != nil {
+			return err
+		}
+
+		return failed
+	})
+	if err != failed {
+		t.Fatalf("got %v, want the error of the function", err)
+	}
+
+	if _, err = repos.candidate.GetCandidateByID(ctx, rolledBack); err != sql.ErrNoRows {
+		t.Errorf("got %v for the candidate of a rolled back transaction, want sql.ErrNoRows", err)
+	}
+
+	err = repos.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return repos.candidate.PostCandidate(ctx, &model.Candidate{ID: committed, Name: "Committed", WillingToRelocate: "no"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = repos.candidate.GetCandidateByID(ctx, committed); err != nil {
+		t.Errorf("got %v for the candidate of a committed transaction", err)
+	}
+}
+
+func testCandidateAudit(t *testing.T, repos repositories, tag string) {
+	ctx := context.Background()
+
+	job, recruitment := tag+"-audit-job", tag+"-audit-recruitment"
+	kept, merged, score := tag+"-audit-kept", tag+"-audit-merged", tag+"-audit-score"
+	postJob(t, repos, job)
+	postRecruitment(t, repos, recruitment, job)
+	postCandidate(t, repos, kept, "Kept")
+	postCandidate(t, repos, merged, "Merged")
+
+	if err := postScore(repos, score, recruitment, merged, 80); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repos.candidate.MergeCandidate(ctx, kept, merged); err != nil {
+		t.Fatal(err)
+	}
+
+	// the entries of a deleted record stay under its candidate
+	if err := repos.candidateScore.DeleteCandidateScore(ctx, recruitment, kept); err != nil {
+		t.Fatal(err)
+	}
+
+	logs, err := repos.audit.GetAuditLogListByCandidateID(ctx, kept)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, log := range *logs {
+		got = append(got, log.EntityType+" "+log.Action+" "+log.CandidateID)
+	}
+
+	want := []string{
+		"candidate create " + kept,
+		"candidate create " + merged,
+		"candidate_score create " + merged,
+		"candidate merge " + kept,
+		"candidate_score delete " + kept,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got entries %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// Dialect names the SQL flavour spoken by a database.
+type Dialect string
+
+const (
+	MySQL    Dialect = "mysql"
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// DB is a database handle together with its dialect. Repositories write
+// their queries with ? placeholders and conn rewrites them for the dialect.
+type DB struct {
+	*sql.DB
+	Dialect Dialect
+}
+
+func NewDB(db *sql.DB, dialect Dialect) *DB {
+	return &DB{
+		DB:      db,
+		Dialect: dialect,
+	}
+}
+
+// rebind rewrites the ? placeholders of query into $1, $2, ... for
+// Postgres. Queries must not contain a literal question mark.
+func (d Dialect) rebind(query string) string {
+	if d != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var (
+		b strings.Builder
+		n int
+	)
+
+	for _, c := range query {
+		if c != '?' {
+			b.WriteRune(c)
+			continue
+		}
+
+		n++
+		b.WriteByte('$')
+		b.WriteString(strconv.Itoa(n))
+	}
+
+	return b.String()
+}
+
+// insertIgnore returns an insert into table that skips a row whose key
+// already exists.
+func (d Dialect) insertIgnore(table string, columns ...string) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	insert := " into " + table + "(" + strings.Join(columns, ", ") + ") values (" + placeholders + ")"
+
+	switch d {
+	case SQLite:
+		return "insert or ignore" + insert
+	case Postgres:
+		return "insert" + insert + " on conflict do nothing"
+	}
+
+	return "insert ignore" + insert
+}
+
+// dialectQuerier rebinds every query before passing it on, and translates
+// the constraint violations of writes.
+type dialectQuerier struct {
+	querier
+	dialect Dialect
+}
+
+func (q dialectQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	res, err := q.querier.ExecContext(ctx, q.dialect.rebind(query), args...)
+	return res, q.dialect.constraintError(err)
+}
+
+func (q dialectQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return q.querier.QueryContext(ctx, q.dialect.rebind(query), args...)
+}
+
+func (q dialectQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return q.querier.QueryRowContext(ctx, q.dialect.rebind(query), args...)
+}
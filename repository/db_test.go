@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+func TestRebind(t *testing.T) {
+	query := "select id from candidate where name = ? and experience >= ? limit ?"
+
+	for dialect, want := range map[Dialect]string{
+		MySQL:    query,
+		SQLite:   query,
+		Postgres: "select id from candidate where name = $1 and experience >= $2 limit $3",
+	} {
+		if got := dialect.rebind(query); got != want {
+			t.Errorf("%s: got %q, want %q", dialect, got, want)
+		}
+	}
+}
+
+func TestInsertIgnore(t *testing.T) {
+	for dialect, want := range map[Dialect]string{
+		MySQL:    "insert ignore into scheduler_lease(name, holder) values (?, ?)",
+		SQLite:   "insert or ignore into scheduler_lease(name, holder) values (?, ?)",
+		Postgres: "insert into scheduler_lease(name, holder) values (?, ?) on conflict do nothing",
+	} {
+		if got := dialect.insertIgnore("scheduler_lease", "name", "holder"); got != want {
+			t.Errorf("%s: got %q, want %q", dialect, got, want)
+		}
+	}
+}
+
+func TestTextSearch(t *testing.T) {
+	terms := []string{"go", "c", "backend"}
+
+	postgres := Postgres.textSearch(terms, "name", "address")
+	document := "to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(address, ''))"
+	if want := document + " @@ to_tsquery('simple', ?)"; postgres.condition != want {
+		t.Errorf("postgres condition: got %q, want %q", postgres.condition, want)
+	}
+
+	if want := []interface{}{"go:* | backend:*"}; !reflect.DeepEqual(postgres.args, want) {
+		t.Errorf("postgres tsquery: got %v, want %v", postgres.args, want)
+	}
+
+	if want := []interface{}{"go* backend*"}; !reflect.DeepEqual(MySQL.textSearch(terms, "name").args, want) {
+		t.Errorf("mysql query: got %v, want %v", MySQL.textSearch(terms, "name").args, want)
+	}
+
+	sqlite := SQLite.textSearch(terms, "name")
+	if want := []interface{}{"% go%", "% backend%"}; !reflect.DeepEqual(sqlite.args, want) {
+		t.Errorf("sqlite patterns: got %v, want %v", sqlite.args, want)
+	}
+
+	for _, dialect := range []Dialect{MySQL, SQLite, Postgres} {
+		if got := dialect.textSearch([]string{"c"}, "name"); got.condition != noMatch.condition {
+			t.Errorf("%s: a query without a long enough term got %q, want no match", dialect, got.condition)
+		}
+	}
+}
+
+func TestConstraintError(t *testing.T) {
+	plain := errors.New("connection refused")
+
+	for _, test := range []struct {
+		dialect Dialect
+		err     error
+		want    error
+	}{
+		{MySQL, &mysql.MySQLError{Number: 1062}, ErrDuplicate},
+		{MySQL, &mysql.MySQLError{Number: 1451}, ErrReferenced},
+		{MySQL, &mysql.MySQLError{Number: 1452}, ErrReferenced},
+		{Postgres, &pq.Error{Code: "23505"}, ErrDuplicate},
+		{Postgres, fmt.Errorf("insert: %w", &pq.Error{Code: "23503"}), ErrReferenced},
+		{Postgres, &mysql.MySQLError{Number: 1062}, nil},
+		{SQLite, plain, nil},
+	} {
+		got := test.dialect.constraintError(test.err)
+		if test.want == nil {
+			if got != test.err {
+				t.Errorf("%s: %v became %v, want it unchanged", test.dialect, test.err, got)
+			}
+
+			continue
+		}
+
+		if !errors.Is(got, test.want) {
+			t.Errorf("%s: %v became %v, want %v", test.dialect, test.err, got, test.want)
+		}
+	}
+
+	if err := SQLite.constraintError(nil); err != nil {
+		t.Errorf("got %v for no error, want nil", err)
+	}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+	"strings"
+)
+
+// The constraint violations a write may run into, whatever the database.
+// The usecases check for them before writing, so these are left for writes
+// that race with another request.
+var (
+	// ErrDuplicate is returned when a write breaks a unique key.
+	ErrDuplicate = errors.New("record already exists")
+
+	// ErrReferenced is returned when a write breaks a foreign key, most
+	// often the delete of a record that others still refer to.
+	ErrReferenced = errors.New("record is still in use")
+)
+
+// constraintError wraps err with ErrDuplicate or ErrReferenced when it is a
+// constraint violation of the dialect, and returns it unchanged otherwise.
+func (d Dialect) constraintError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var kind error
+
+	switch d {
+	case MySQL:
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) {
+			switch mysqlErr.Number {
+			case 1062:
+				kind = ErrDuplicate
+			case 1451, 1452:
+				kind = ErrReferenced
+			}
+		}
+	case Postgres:
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case "23505":
+				kind = ErrDuplicate
+			case "23503":
+				kind = ErrReferenced
+			}
+		}
+	case SQLite:
+		var sqliteErr *sqlite.Error
+		if errors.As(err, &sqliteErr) {
+			switch sqliteErr.Code() {
+			case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+				kind = ErrDuplicate
+			case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+				kind = ErrReferenced
+			case sqlite3.SQLITE_CONSTRAINT_TRIGGER:
+				// ON DELETE RESTRICT is enforced by a trigger, which
+				// reports the foreign key under its own code
+				if strings.Contains(sqliteErr.Error(), "FOREIGN KEY") {
+					kind = ErrReferenced
+				}
+			}
+		}
+	}
+
+	if kind == nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", kind, err)
+}
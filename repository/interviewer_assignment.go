@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"talentapp/model"
+)
+
+type InterviewerAssignmentRepository interface {
+	GetInterviewerAssignmentListByRecruitmentID(ctx context.Context, recruitmentID string) (*[]model.InterviewerAssignment, error)
+	CountInterviewerAssignment(ctx context.Context, recruitmentID, candidateID, userID string) (int, error)
+	CountInterviewerAssignmentByCandidateID(ctx context.Context, candidateID, userID string) (int, error)
+	CountInterviewerAssignmentByApplicationID(ctx context.Context, applicationID, userID string) (int, error)
+	PostInterviewerAssignment(ctx context.Context, model *model.InterviewerAssignment) error
+	DeleteInterviewerAssignment(ctx context.Context, recruitmentID, id string) error
+}
+
+type interviewerAssignmentRepository struct {
+	DB *DB
+}
+
+func NewInterviewerAssignmentRepository(db *DB) InterviewerAssignmentRepository {
+	return &interviewerAssignmentRepository{
+		db,
+	}
+}
+
+func (r *interviewerAssignmentRepository) GetInterviewerAssignmentListByRecruitmentID(ctx context.Context, recruitmentID string) (*[]model.InterviewerAssignment, error) {
+	var result = []model.InterviewerAssignment{}
+	SQL := "SELECT id, recruitment_id, candidate_id, user_id, created_at FROM interviewer_assignment WHERE recruitment_id = ? ORDER BY created_at, id"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, recruitmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		assignment := model.InterviewerAssignment{}
+		err = rows.Scan(&assignment.ID, &assignment.RecruitmentID, &assignment.CandidateID, &assignment.UserID, &assignment.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, assignment)
+	}
+
+	return &result, nil
+}
+
+func (r *interviewerAssignmentRepository) CountInterviewerAssignment(ctx context.Context, recruitmentID, candidateID, userID string) (int, error) {
+	var total int
+	SQL := "SELECT COUNT(*) FROM interviewer_assignment WHERE recruitment_id = ? AND candidate_id = ? AND user_id = ?"
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, recruitmentID, candidateID, userID).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// CountInterviewerAssignmentByCandidateID counts the assignments of the
+// user to the candidate, in every recruitment.
+func (r *interviewerAssignmentRepository) CountInterviewerAssignmentByCandidateID(ctx context.Context, candidateID, userID string) (int, error) {
+	var total int
+	SQL := "SELECT COUNT(*) FROM interviewer_assignment WHERE candidate_id = ? AND user_id = ?"
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, candidateID, userID).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// CountInterviewerAssignmentByApplicationID counts the assignments of the
+// user to the candidate of the application, in its recruitment.
+func (r *interviewerAssignmentRepository) CountInterviewerAssignmentByApplicationID(ctx context.Context, applicationID, userID string) (int, error) {
+	var total int
+	SQL := "SELECT COUNT(*) FROM interviewer_assignment ia JOIN application a ON a.recruitment_id = ia.recruitment_id AND a.candidate_id = ia.candidate_id " +
+		"WHERE a.id = ? AND ia.user_id = ?"
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, applicationID, userID).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *interviewerAssignmentRepository) PostInterviewerAssignment(ctx context.Context, model *model.InterviewerAssignment) error {
+	return audited(ctx, r.DB, interviewerAssignmentAudit.entry(actionCreate, model.ID), func(ctx context.Context) error {
+		SQL := "insert into interviewer_assignment(id, recruitment_id, candidate_id, user_id, created_at) values (?, ?, ?, ?, ?)"
+		if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.RecruitmentID, model.CandidateID, model.UserID, model.CreatedAt); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *interviewerAssignmentRepository) DeleteInterviewerAssignment(ctx context.Context, recruitmentID, id string) error {
+	return audited(ctx, r.DB, interviewerAssignmentAudit.entry(actionDelete, id), func(ctx context.Context) error {
+		SQL := "delete from interviewer_assignment where recruitment_id = ? and id = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, recruitmentID, id)
+		if err != nil {
+			return err
+		}
+
+		return affected(res)
+	})
+}
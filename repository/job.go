@@ -2,30 +2,70 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"encoding/json"
+	"fmt"
 	"talentapp/model"
 )
 
 type JobRepository interface {
 	GetJobByID(ctx context.Context, id string) (*model.Job, error)
 	PostJob(ctx context.Context, model *model.Job) error
-	GetJobList(ctx context.Context) (*[]model.Job, error)
+	GetJobList(ctx context.Context, filter model.JobListRequest) (*[]model.Job, int, error)
+	UpdateJob(ctx context.Context, model *model.Job) error
+	DeleteJob(ctx context.Context, id string) error
 }
 
+var jobSortColumns = map[string]string{
+	"position":   "position",
+	"department": "department",
+}
+
+// jobSearchColumns are searched as one document, in the order of their
+// full-text index.
+var jobSearchColumns = []string{"position", "department", "job_description", "criteria"}
+
 type jobRepository struct {
-	DB *sql.DB
+	DB *DB
 }
 
-func NewJobRepository(db *sql.DB) JobRepository {
+func NewJobRepository(db *DB) JobRepository {
 	return &jobRepository{DB: db}
 }
 
+// requirementsColumn scans the requirements of a job, stored as JSON, into
+// dest. NULL reads as no requirements.
+type requirementsColumn struct {
+	dest *model.JobRequirements
+}
+
+func (c requirementsColumn) Scan(src interface{}) error {
+	*c.dest = model.JobRequirements{}
+
+	switch value := src.(type) {
+	case nil:
+	case []byte:
+		if err := json.Unmarshal(value, c.dest); err != nil {
+			return err
+		}
+	case string:
+		if err := json.Unmarshal([]byte(value), c.dest); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported requirements value %T", src)
+	}
+
+	c.dest.Normalize()
+
+	return nil
+}
+
 func (r *jobRepository) GetJobByID(ctx context.Context, id string) (*model.Job, error) {
 	var result model.Job
-	SQL := "SELECT id, position, department, requester, job_description, criteria FROM job WHERE id = ?"
-	row := r.DB.QueryRowContext(ctx, SQL, id)
+	SQL := "SELECT id, position, department, requester, job_description, criteria, requirements FROM job WHERE id = ?"
+	row := conn(ctx, r.DB).QueryRowContext(ctx, SQL, id)
 
-	err := row.Scan(&result.ID, &result.Position, &result.Department, &result.Requester, &result.JobDescription, &result.Criteria)
+	err := row.Scan(&result.ID, &result.Position, &result.Department, &result.Requester, &result.JobDescription, &result.Criteria, requirementsColumn{&result.Requirements})
 	if err != nil {
 		return nil, err
 	}
@@ -34,31 +74,95 @@ func (r *jobRepository) GetJobByID(ctx context.Context, id string) (*model.Job,
 }
 
 func (r *jobRepository) PostJob(ctx context.Context, model *model.Job) error {
-	SQL := "insert into job(id, position, department, requester, job_description, criteria) values (?, ?, ?, ?, ?, ?)"
-	if _, err := r.DB.ExecContext(ctx, SQL, model.ID, model.Position, model.Department, model.Requester, model.JobDescription, model.Criteria); err != nil {
-		return err
-	}
+	return audited(ctx, r.DB, jobAudit.entry(actionCreate, model.ID), func(ctx context.Context) error {
+		requirements, err := json.Marshal(model.Requirements)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		SQL := "insert into job(id, position, department, requester, job_description, criteria, requirements) values (?, ?, ?, ?, ?, ?, ?)"
+		if _, err = conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.Position, model.Department, model.Requester, model.JobDescription, model.Criteria, string(requirements)); err != nil {
+			return err
+		}
+
+		return nil
+	})
 }
 
-func (r *jobRepository) GetJobList(ctx context.Context) (*[]model.Job, error) {
-	var result []model.Job
-	SQL := "SELECT id, position, department, requester, job_description, criteria FROM job LIMIT ? OFFSET ?"
-	rows, err := r.DB.QueryContext(ctx, SQL, 10, 0) // TODO: make dynamic pagination
+func (r *jobRepository) GetJobList(ctx context.Context, filter model.JobListRequest) (*[]model.Job, int, error) {
+	var (
+		result     = []model.Job{}
+		total      int
+		conditions []string
+		args       []interface{}
+	)
+
+	if filter.Department != "" {
+		conditions = append(conditions, "department = ?")
+		args = append(args, filter.Department)
+	}
+
+	// a search ranks the jobs by relevance unless another sort is asked
+	search, fallback := textSearch{relevance: "0"}, "position ASC"
+	if terms, ok := searchTerms(filter.Query); ok {
+		search = r.DB.Dialect.textSearch(terms, jobSearchColumns...)
+		conditions = append(conditions, search.condition)
+		args = append(args, search.args...)
+		fallback = "relevance DESC, position ASC"
+	}
+
+	SQL := "SELECT COUNT(*) FROM job" + where(conditions)
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	SQL = "SELECT id, position, department, requester, job_description, criteria, requirements, " + search.relevance + " AS relevance FROM job" + where(conditions) +
+		orderBy(filter.Sort, jobSortColumns, fallback, "id") + " LIMIT ? OFFSET ?"
+	queryArgs := append([]interface{}{}, search.relevanceArgs...)
+	queryArgs = append(queryArgs, args...)
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, append(queryArgs, filter.Size, filter.Offset())...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		job := model.Job{}
-		err = rows.Scan(&job.ID, &job.Position, &job.Department, &job.Requester, &job.JobDescription, &job.Criteria)
+		err = rows.Scan(&job.ID, &job.Position, &job.Department, &job.Requester, &job.JobDescription, &job.Criteria, requirementsColumn{&job.Requirements}, &job.Relevance)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		result = append(result, job)
 	}
 
-	return &result, nil
+	return &result, total, nil
+}
+
+func (r *jobRepository) UpdateJob(ctx context.Context, model *model.Job) error {
+	return audited(ctx, r.DB, jobAudit.entry(actionUpdate, model.ID), func(ctx context.Context) error {
+		requirements, err := json.Marshal(model.Requirements)
+		if err != nil {
+			return err
+		}
+
+		SQL := "update job set position = ?, department = ?, requester = ?, job_description = ?, criteria = ?, requirements = ? where id = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.Position, model.Department, model.Requester, model.JobDescription, model.Criteria, string(requirements), model.ID)
+		if err != nil {
+			return err
+		}
+
+		return affected(res)
+	})
+}
+
+func (r *jobRepository) DeleteJob(ctx context.Context, id string) error {
+	return audited(ctx, r.DB, jobAudit.entry(actionDelete, id), func(ctx context.Context) error {
+		SQL := "delete from job where id = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, id)
+		if err != nil {
+			return err
+		}
+
+		return affected(res)
+	})
 }
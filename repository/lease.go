@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// LeaseRepository hands out named leases so that only one app instance runs
+// a given background job at a time.
+type LeaseRepository interface {
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
+type leaseRepository struct {
+	DB *DB
+}
+
+func NewLeaseRepository(db *DB) LeaseRepository {
+	return &leaseRepository{
+		db,
+	}
+}
+
+// AcquireLease takes the lease when it is free or expired, or extends it
+// when holder already owns it. It reports whether holder owns the lease
+// afterwards.
+func (r *leaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	SQL := r.DB.Dialect.insertIgnore("scheduler_lease", "name", "holder", "expires_at")
+	if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, name, holder, now); err != nil {
+		return false, err
+	}
+
+	SQL = "update scheduler_lease set holder = ?, expires_at = ? where name = ? and (holder = ? or expires_at <= ?)"
+	res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, holder, now.Add(ttl), name, holder, now)
+	if err != nil {
+		return false, err
+	}
+
+	err = affected(res)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReleaseLease gives the lease up so another instance can take it without
+// waiting for it to expire.
+func (r *leaseRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	SQL := "update scheduler_lease set expires_at = ? where name = ? and holder = ?"
+	if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, time.Now(), name, holder); err != nil {
+		return err
+	}
+
+	return nil
+}
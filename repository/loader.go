@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"talentapp/model"
+)
+
+// The loaders below fill the relations of already read models with one
+// query per relation, whatever the number of models. They run through the
+// querier they are given, so they see the rows of the caller's transaction.
+
+// in returns the placeholders and arguments of an IN clause over ids,
+// skipping duplicates.
+func in(ids []string) (string, []interface{}) {
+	var (
+		seen         = map[string]bool{}
+		placeholders []string
+		args         []interface{}
+	)
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		placeholders = append(placeholders, "?")
+		args = append(args, id)
+	}
+
+	return "(" + strings.Join(placeholders, ", ") + ")", args
+}
+
+func loadJobs(ctx context.Context, q querier, recruitments []*model.Recruitment) error {
+	if len(recruitments) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(recruitments))
+	for i, recruitment := range recruitments {
+		ids[i] = recruitment.JobID
+	}
+
+	placeholders, args := in(ids)
+	SQL := "SELECT id, position, department, requester, job_description, criteria, requirements FROM job WHERE id IN " + placeholders
+	rows, err := q.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	jobs := map[string]*model.Job{}
+	for rows.Next() {
+		var job model.Job
+		if err = rows.Scan(&job.ID, &job.Position, &job.Department, &job.Requester, &job.JobDescription, &job.Criteria, requirementsColumn{&job.Requirements}); err != nil {
+			return err
+		}
+
+		jobs[job.ID] = &job
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, recruitment := range recruitments {
+		recruitment.Job = jobs[recruitment.JobID]
+	}
+
+	return nil
+}
+
+func loadCandidates(ctx context.Context, q querier, scores []*model.CandidateScore) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(scores))
+	for i, score := range scores {
+		ids[i] = score.CandidateID
+	}
+
+	placeholders, args := in(ids)
+	SQL := "SELECT id, name, address, experience, willing_to_relocate FROM candidate WHERE id IN " + placeholders
+	rows, err := q.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	candidates := map[string]*model.Candidate{}
+	for rows.Next() {
+		var candidate model.Candidate
+		if err = rows.Scan(&candidate.ID, &candidate.Name, &candidate.Address, &candidate.Experience, &candidate.WillingToRelocate); err != nil {
+			return err
+		}
+
+		candidates[candidate.ID] = &candidate
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, score := range scores {
+		score.Candidate = candidates[score.CandidateID]
+	}
+
+	return nil
+}
+
+// loadRecruitments fills the Recruitment of every score together with its
+// job.
+func loadRecruitments(ctx context.Context, q querier, scores []*model.CandidateScore) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(scores))
+	for i, score := range scores {
+		ids[i] = score.RecruitmentID
+	}
+
+	placeholders, args := in(ids)
+	SQL := "SELECT " + recruitmentColumns + " FROM recruitment r JOIN job j ON j.id = r.job_id WHERE r.id IN " + placeholders
+	rows, err := q.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	recruitments := map[string]*model.Recruitment{}
+	for rows.Next() {
+		recruitment, err := scanRecruitment(rows)
+		if err != nil {
+			return err
+		}
+
+		recruitments[recruitment.ID] = recruitment
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, score := range scores {
+		score.Recruitment = recruitments[score.RecruitmentID]
+	}
+
+	return nil
+}
+
+// loadSkills fills the Skills of every candidate, sorted by name.
+func loadSkills(ctx context.Context, q querier, candidates []*model.Candidate) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.ID
+	}
+
+	placeholders, args := in(ids)
+	SQL := "SELECT candidate_id, skill FROM candidate_skills WHERE candidate_id IN " + placeholders + " ORDER BY skill"
+	rows, err := q.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	skills := map[string][]string{}
+	for rows.Next() {
+		var candidateID, skill string
+		if err = rows.Scan(&candidateID, &skill); err != nil {
+			return err
+		}
+
+		skills[candidateID] = append(skills[candidateID], skill)
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, candidate := range candidates {
+		candidate.Skills = model.NormalizeSkills(skills[candidate.ID])
+	}
+
+	return nil
+}
+
+// loadConsents fills the Consents of every candidate, most recently granted
+// first.
+func loadConsents(ctx context.Context, q querier, candidates []*model.Candidate) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.ID
+	}
+
+	placeholders, args := in(ids)
+	SQL := "SELECT " + consentColumns + " FROM candidate_consent WHERE candidate_id IN " + placeholders + " ORDER BY granted_at DESC, created_at DESC, id"
+	rows, err := q.QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	consents := map[string][]model.CandidateConsent{}
+	for rows.Next() {
+		consent, err := scanConsent(rows)
+		if err != nil {
+			return err
+		}
+
+		consents[consent.CandidateID] = append(consents[consent.CandidateID], *consent)
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, candidate := range candidates {
+		candidate.Consents = consents[candidate.ID]
+	}
+
+	return nil
+}
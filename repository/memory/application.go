@@ -0,0 +1,214 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"talentapp/model"
+	"talentapp/repository"
+)
+
+func applicationKey(a model.Application) string {
+	return a.RecruitmentID + " " + a.CandidateID
+}
+
+type applicationRepository struct {
+	store *Store
+}
+
+func NewApplicationRepository(store *Store) repository.ApplicationRepository {
+	return &applicationRepository{store}
+}
+
+// application returns the stored columns of an application, without
+// relations.
+func application(a model.Application) model.Application {
+	return model.Application{
+		ID:            a.ID,
+		RecruitmentID: a.RecruitmentID,
+		CandidateID:   a.CandidateID,
+		Stage:         a.Stage,
+		CreatedAt:     timestamp(a.CreatedAt),
+		UpdatedAt:     timestamp(a.UpdatedAt),
+	}
+}
+
+func (r *applicationRepository) GetApplicationByID(ctx context.Context, id string) (*model.Application, error) {
+	var result model.Application
+
+	err := r.store.read(ctx, func(t *tables) (err error) {
+		result, err = find(t.applications, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetApplicationListByRecruitmentID returns the applications of a
+// recruitment with their candidate, oldest first.
+func (r *applicationRepository) GetApplicationListByRecruitmentID(ctx context.Context, recruitmentID string) (*[]model.Application, error) {
+	var result = []model.Application{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, application := range t.applications {
+			if application.RecruitmentID != recruitmentID {
+				continue
+			}
+
+			candidate, err := find(t.candidates, application.CandidateID)
+			if err != nil {
+				return err
+			}
+
+			application.Candidate = basicCandidate(candidate)
+			result = append(result, application)
+		}
+
+		orderBy(result, "", nil, []compare[model.Application]{func(a, b model.Application) int { return compareTimes(a.CreatedAt, b.CreatedAt) }},
+			func(a model.Application) string { return a.ID })
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetApplicationListByCandidateID returns the applications of a candidate
+// with their recruitment and its job, latest deadline first.
+func (r *applicationRepository) GetApplicationListByCandidateID(ctx context.Context, candidateID string) (*[]model.Application, error) {
+	var result = []model.Application{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, application := range t.applications {
+			if application.CandidateID != candidateID {
+				continue
+			}
+
+			recruitment, err := t.recruitment(application.RecruitmentID)
+			if err != nil {
+				return err
+			}
+
+			application.Recruitment = recruitment
+			result = append(result, application)
+		}
+
+		orderBy(result, "", nil, []compare[model.Application]{func(a, b model.Application) int { return compareDeadlines(*b.Recruitment, *a.Recruitment) }},
+			func(a model.Application) string { return a.ID })
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *applicationRepository) CountApplicationByCandidate(ctx context.Context, recruitmentID, candidateID string) (int, error) {
+	return r.count(ctx, func(a model.Application) bool {
+		return a.RecruitmentID == recruitmentID && a.CandidateID == candidateID
+	})
+}
+
+func (r *applicationRepository) CountApplicationByRecruitmentID(ctx context.Context, recruitmentID string) (int, error) {
+	return r.count(ctx, func(a model.Application) bool { return a.RecruitmentID == recruitmentID })
+}
+
+func (r *applicationRepository) CountApplicationByCandidateID(ctx context.Context, candidateID string) (int, error) {
+	return r.count(ctx, func(a model.Application) bool { return a.CandidateID == candidateID })
+}
+
+func (r *applicationRepository) count(ctx context.Context, match func(model.Application) bool) (int, error) {
+	var total int
+
+	err := r.store.read(ctx, func(t *tables) error {
+		total = count(t.applications, match)
+		return nil
+	})
+
+	return total, err
+}
+
+func (r *applicationRepository) PostApplication(ctx context.Context, model *model.Application) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row := application(*model)
+
+		for _, err := range []error{
+			exists(t.recruitments, "recruitment", row.RecruitmentID),
+			exists(t.candidates, "candidate", row.CandidateID),
+			unique(t.applications, applicationKey, "application", row.ID, row),
+		} {
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := insert(t.applications, "application", row.ID, row); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityApplication, actionCreate, row.ID, nil, applicationRow(row))
+	})
+}
+
+// UpdateApplicationStage moves the application only if it is still in
+// fromStage, so two concurrent moves cannot both succeed. sql.ErrNoRows is
+// returned when the stage was changed in the meantime.
+func (r *applicationRepository) UpdateApplicationStage(ctx context.Context, model *model.Application, fromStage string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.applications, model.ID)
+		if err != nil {
+			return err
+		}
+
+		if before.Stage != fromStage {
+			return sql.ErrNoRows
+		}
+
+		after := before
+		after.Stage, after.UpdatedAt = model.Stage, timestamp(model.UpdatedAt)
+		t.applications[after.ID] = after
+
+		return t.recordAudit(ctx, entityApplication, actionStatusChange, after.ID, applicationRow(before), applicationRow(after))
+	})
+}
+
+func (r *applicationRepository) PostApplicationStageEvent(ctx context.Context, model *model.ApplicationStageEvent) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row := *model
+		row.CreatedAt = timestamp(row.CreatedAt)
+		if err := exists(t.applications, "application", row.ApplicationID); err != nil {
+			return err
+		}
+
+		return insert(t.applicationStageEvents, "application_stage_event", row.ID, row)
+	})
+}
+
+func (r *applicationRepository) GetApplicationStageEventListByApplicationID(ctx context.Context, applicationID string) (*[]model.ApplicationStageEvent, error) {
+	var result = []model.ApplicationStageEvent{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, event := range t.applicationStageEvents {
+			if event.ApplicationID == applicationID {
+				result = append(result, event)
+			}
+		}
+
+		orderBy(result, "", nil, []compare[model.ApplicationStageEvent]{func(a, b model.ApplicationStageEvent) int { return compareTimes(a.CreatedAt, b.CreatedAt) }},
+			func(e model.ApplicationStageEvent) string { return e.ID })
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"talentapp/model"
+	"talentapp/repository"
+)
+
+type attachmentRepository struct {
+	store *Store
+}
+
+func NewAttachmentRepository(store *Store) repository.AttachmentRepository {
+	return &attachmentRepository{store}
+}
+
+func (r *attachmentRepository) GetAttachmentByID(ctx context.Context, id string) (*model.Attachment, error) {
+	var result model.Attachment
+
+	err := r.store.read(ctx, func(t *tables) (err error) {
+		result, err = find(t.attachments, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetAttachmentListByCandidateID returns the attachments of a candidate,
+// newest first.
+func (r *attachmentRepository) GetAttachmentListByCandidateID(ctx context.Context, candidateID string) (*[]model.Attachment, error) {
+	var result = []model.Attachment{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, attachment := range t.attachments {
+			if attachment.CandidateID == candidateID {
+				result = append(result, attachment)
+			}
+		}
+
+		orderBy(result, "", nil, []compare[model.Attachment]{func(a, b model.Attachment) int { return compareTimes(b.CreatedAt, a.CreatedAt) }},
+			func(a model.Attachment) string { return a.ID })
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *attachmentRepository) CountAttachmentByCandidateID(ctx context.Context, candidateID string) (int, error) {
+	return r.count(ctx, func(a model.Attachment) bool { return a.CandidateID == candidateID })
+}
+
+// CountAttachmentByStorageKey counts the attachments sharing a stored
+// object, which may only be removed from storage once none is left.
+func (r *attachmentRepository) CountAttachmentByStorageKey(ctx context.Context, storageKey string) (int, error) {
+	return r.count(ctx, func(a model.Attachment) bool { return a.StorageKey == storageKey })
+}
+
+func (r *attachmentRepository) CountAttachmentByCandidateSHA256(ctx context.Context, candidateID, sha256 string) (int, error) {
+	return r.count(ctx, func(a model.Attachment) bool { return a.CandidateID == candidateID && a.SHA256 == sha256 })
+}
+
+func (r *attachmentRepository) count(ctx context.Context, match func(model.Attachment) bool) (int, error) {
+	var total int
+
+	err := r.store.read(ctx, func(t *tables) error {
+		total = count(t.attachments, match)
+		return nil
+	})
+
+	return total, err
+}
+
+func (r *attachmentRepository) PostAttachment(ctx context.Context, model *model.Attachment) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row := *model
+		row.CreatedAt = timestamp(row.CreatedAt)
+		if err := exists(t.candidates, "candidate", row.CandidateID); err != nil {
+			return err
+		}
+
+		if row.ApplicationID != "" {
+			if err := exists(t.applications, "application", row.ApplicationID); err != nil {
+				return err
+			}
+		}
+
+		if err := insert(t.attachments, "attachment", row.ID, row); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityAttachment, actionCreate, row.ID, nil, attachmentRow(row))
+	})
+}
+
+func (r *attachmentRepository) DeleteAttachment(ctx context.Context, id string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.attachments, id)
+		if err != nil {
+			return err
+		}
+
+		delete(t.attachments, id)
+
+		return t.recordAudit(ctx, entityAttachment, actionDelete, id, attachmentRow(before), nil)
+	})
+}
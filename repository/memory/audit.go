@@ -0,0 +1,371 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"sort"
+	"strconv"
+	"talentapp/model"
+	"talentapp/repository"
+	"time"
+)
+
+// The names are repeated here because most repository methods name their
+// argument model, which hides the package inside them.
+const (
+	entityCandidate             = model.EntityCandidate
+	entityJob                   = model.EntityJob
+	entityRecruitment           = model.EntityRecruitment
+	entityCandidateScore        = model.EntityCandidateScore
+	entityScoringProfile        = model.EntityScoringProfile
+	entityScorecard             = model.EntityScorecard
+	entityApplication           = model.EntityApplication
+	entityInterviewerAssignment = model.EntityInterviewerAssignment
+	entityUser                  = model.EntityUser
+	entityAttachment            = model.EntityAttachment
+	entityCandidateConsent      = model.EntityCandidateConsent
+
+	actionCreate       = model.ActionCreate
+	actionUpdate       = model.ActionUpdate
+	actionDelete       = model.ActionDelete
+	actionStatusChange = model.ActionStatusChange
+	actionMerge        = model.ActionMerge
+	actionErase        = model.ActionErase
+)
+
+// row is the state of a record as the audit log stores it: its columns,
+// with the values the SQL repositories read, as text or nil for NULL.
+type row map[string]interface{}
+
+func text(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+
+		return v.Format(time.RFC3339Nano)
+	}
+
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// nullString reads an empty string as NULL, as the SQL repositories store
+// it.
+func nullString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+
+	return value
+}
+
+func candidateRow(c model.Candidate) row {
+	return row{
+		"id": c.ID, "name": c.Name, "address": c.Address, "experience": text(c.Experience), "willing_to_relocate": c.WillingToRelocate,
+		"email": nullString(c.Email), "phone": nullString(c.Phone), "erased_at": text(c.ErasedAt),
+	}
+}
+
+func jobRow(j model.Job) row {
+	return row{
+		"id": j.ID, "position": j.Position, "department": j.Department, "requester": j.Requester,
+		"job_description": j.JobDescription, "criteria": j.Criteria, "requirements": text(j.Requirements),
+	}
+}
+
+func recruitmentRow(r model.Recruitment) row {
+	return row{
+		"id": r.ID, "job_id": r.JobID, "status": r.Status, "deadline": text(r.Deadline), "score_aggregation": r.ScoreAggregation,
+		"closed_at": text(r.ClosedAt), "closed_reason": nullString(r.ClosedReason),
+	}
+}
+
+func candidateScoreRow(s model.CandidateScore) row {
+	return row{
+		"id": s.ID, "candidate_id": s.CandidateID, "recruitment_id": s.RecruitmentID,
+		"willing_to_relocate_score": text(s.WillingToRelocateScore), "attitude_score": text(s.AttitudeScore),
+		"skill_score": text(s.SkillScore), "experience_score": text(s.ExperienceScore), "overall_score": text(s.OverallScore),
+		"scoring_profile_id": nullString(s.ScoringProfileID), "interviewer_count": text(s.InterviewerCount),
+		"attitude_spread": text(s.AttitudeSpread), "skill_spread": text(s.SkillSpread),
+	}
+}
+
+func scoringProfileRow(p model.ScoringProfile) row {
+	return row{
+		"id": p.ID, "job_id": p.JobID, "version": text(p.Version), "attitude_weight": text(p.AttitudeWeight),
+		"relocation_weight": text(p.RelocationWeight), "skill_weight": text(p.SkillWeight), "experience_weight": text(p.ExperienceWeight),
+		"experience_bands": text(p.ExperienceBands), "grade_values": text(p.GradeValues), "created_at": text(p.CreatedAt),
+	}
+}
+
+func scorecardRow(s model.Scorecard) row {
+	return row{
+		"id": s.ID, "recruitment_id": s.RecruitmentID, "candidate_id": s.CandidateID, "interviewer": s.Interviewer,
+		"attitude_grade": s.AttitudeGrade, "skill_grade": s.SkillGrade, "attitude_score": text(s.AttitudeScore),
+		"skill_score": text(s.SkillScore), "comment": s.Comment, "created_at": text(s.CreatedAt),
+	}
+}
+
+func applicationRow(a model.Application) row {
+	return row{
+		"id": a.ID, "recruitment_id": a.RecruitmentID, "candidate_id": a.CandidateID, "stage": a.Stage,
+		"created_at": text(a.CreatedAt), "updated_at": text(a.UpdatedAt),
+	}
+}
+
+func interviewerAssignmentRow(a model.InterviewerAssignment) row {
+	return row{
+		"id": a.ID, "recruitment_id": a.RecruitmentID, "candidate_id": a.CandidateID, "user_id": a.UserID, "created_at": text(a.CreatedAt),
+	}
+}
+
+// userRow leaves the password hash out, like the SQL snapshot of a user.
+func userRow(u model.User) row {
+	return row{"id": u.ID, "name": u.Name, "email": u.Email, "role": u.Role, "created_at": text(u.CreatedAt)}
+}
+
+func attachmentRow(a model.Attachment) row {
+	return row{
+		"id": a.ID, "candidate_id": a.CandidateID, "application_id": nullString(a.ApplicationID), "kind": a.Kind,
+		"filename": a.Filename, "content_type": a.ContentType, "size": text(a.Size), "sha256": a.SHA256,
+		"storage_key": a.StorageKey, "uploaded_by": a.UploadedBy, "created_at": text(a.CreatedAt),
+	}
+}
+
+func consentRow(c model.CandidateConsent) row {
+	return row{
+		"id": c.ID, "candidate_id": c.CandidateID, "purpose": c.Purpose, "source": c.Source, "granted_at": text(c.GrantedAt),
+		"expires_at": text(c.ExpiresAt), "withdrawn_at": text(c.WithdrawnAt), "recorded_by": c.RecordedBy, "created_at": text(c.CreatedAt),
+	}
+}
+
+// recordAudit appends an entry about an action on the record entityID,
+// whose state was before and is after the action. Either may be nil.
+func (t *tables) recordAudit(ctx context.Context, entityType, action, entityID string, before, after row) error {
+	result := model.AuditLog{
+		ID:         uuid.NewString(),
+		Actor:      model.ActorSystem,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		CreatedAt:  time.Now(),
+	}
+
+	if user := model.UserFromContext(ctx); user != nil {
+		result.ActorID = user.ID
+		result.Actor = user.Name
+	}
+
+	for _, r := range []row{before, after} {
+		if id, ok := r["id"].(string); ok {
+			result.EntityID = id
+			break
+		}
+	}
+
+	result.CandidateID = auditCandidateID(result, before, after)
+
+	var err error
+	if result.Before, err = marshalRow(before); err != nil {
+		return err
+	}
+
+	if result.After, err = marshalRow(after); err != nil {
+		return err
+	}
+
+	t.auditLogs = append(t.auditLogs, result)
+
+	return nil
+}
+
+func marshalRow(r row) (json.RawMessage, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(r)
+}
+
+// auditCandidateID returns the candidate an entry is about, from the states
+// of the record before and after the change, or "" when it is about no
+// candidate.
+func auditCandidateID(log model.AuditLog, before, after row) string {
+	switch {
+	case log.EntityType != entityCandidate:
+		for _, r := range []row{after, before} {
+			if id, ok := r["candidate_id"].(string); ok {
+				return id
+			}
+		}
+
+		return ""
+	case log.Action == actionMerge:
+		id, _ := after["id"].(string)
+		return id
+	}
+
+	return log.EntityID
+}
+
+// candidateAuditLogs returns the indexes of the entries about a candidate,
+// oldest first: those recorded under the candidate, and those of the
+// candidates merged into this one.
+func (t *tables) candidateAuditLogs(candidateID string) []int {
+	var (
+		result []int
+		ids    = []string{candidateID}
+		seen   = map[string]bool{candidateID: true}
+	)
+
+	for len(ids) > 0 {
+		id := ids[0]
+		ids = ids[1:]
+
+		for i, log := range t.auditLogs {
+			if log.CandidateID != id {
+				continue
+			}
+
+			result = append(result, i)
+
+			if log.EntityType == model.EntityCandidate && log.Action == model.ActionMerge && !seen[log.EntityID] {
+				seen[log.EntityID] = true
+				ids = append(ids, log.EntityID)
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return t.auditLogs[result[i]].CreatedAt.Before(t.auditLogs[result[j]].CreatedAt)
+	})
+
+	return result
+}
+
+// redactAuditLogs overwrites the columns holding personal data in the
+// states of the entries about a candidate, see erasedColumns.
+func (t *tables) redactAuditLogs(candidateID string) error {
+	for _, i := range t.candidateAuditLogs(candidateID) {
+		log := t.auditLogs[i]
+		redacted, ok := erasedColumns[log.EntityType]
+		if !ok {
+			continue
+		}
+
+		var err error
+		if log.Before, err = redactState(log.Before, redacted); err != nil {
+			return err
+		}
+
+		if log.After, err = redactState(log.After, redacted); err != nil {
+			return err
+		}
+
+		t.auditLogs[i] = log
+	}
+
+	return nil
+}
+
+func redactState(data json.RawMessage, columns map[string]interface{}) (json.RawMessage, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var r row
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+
+	for column, value := range columns {
+		if _, ok := r[column]; ok {
+			r[column] = value
+		}
+	}
+
+	return marshalRow(r)
+}
+
+type auditRepository struct {
+	store *Store
+}
+
+func NewAuditRepository(store *Store) repository.AuditRepository {
+	return &auditRepository{store}
+}
+
+func (r *auditRepository) GetAuditLogList(ctx context.Context, filter model.AuditLogListRequest) (*[]model.AuditLog, int, error) {
+	var (
+		result = []model.AuditLog{}
+		total  int
+	)
+
+	err := r.store.read(ctx, func(t *tables) error {
+		var logs []model.AuditLog
+		for _, log := range t.auditLogs {
+			if filter.EntityType != "" && log.EntityType != filter.EntityType ||
+				filter.EntityID != "" && log.EntityID != filter.EntityID ||
+				filter.Actor != "" && log.Actor != filter.Actor ||
+				filter.Action != "" && log.Action != filter.Action {
+				continue
+			}
+
+			logs = append(logs, log)
+		}
+
+		sort.Slice(logs, func(i, j int) bool {
+			if !logs[i].CreatedAt.Equal(logs[j].CreatedAt) {
+				return logs[i].CreatedAt.After(logs[j].CreatedAt)
+			}
+
+			return logs[i].ID > logs[j].ID
+		})
+
+		total = len(logs)
+		result = append(result, page(logs, filter.Pagination)...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &result, total, nil
+}
+
+// GetAuditLogListByCandidateID returns every entry about a candidate, oldest
+// first.
+func (r *auditRepository) GetAuditLogListByCandidateID(ctx context.Context, candidateID string) (*[]model.AuditLog, error) {
+	var result = []model.AuditLog{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, i := range t.candidateAuditLogs(candidateID) {
+			result = append(result, t.auditLogs[i])
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
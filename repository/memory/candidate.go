@@ -0,0 +1,429 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"talentapp/model"
+	"talentapp/repository"
+	"time"
+)
+
+var candidateSortColumns = map[string]compare[model.Candidate]{
+	"name":       func(a, b model.Candidate) int { return compareStrings(a.Name, b.Name) },
+	"experience": func(a, b model.Candidate) int { return compareInts(a.Experience, b.Experience) },
+}
+
+// erasedColumns are the columns of audit states holding personal data of a
+// candidate, by entity type, with the value an erasure replaces them with.
+var erasedColumns = map[string]map[string]interface{}{
+	model.EntityCandidate:  {"name": model.ErasedCandidateName, "address": "", "email": nil, "phone": nil},
+	model.EntityScorecard:  {"comment": nil},
+	model.EntityAttachment: {"filename": model.ErasedFilename},
+}
+
+type candidateRepository struct {
+	store *Store
+}
+
+func NewCandidateRepository(store *Store) repository.CandidateRepository {
+	return &candidateRepository{store}
+}
+
+// candidate returns the stored columns of a candidate, without relations.
+func candidate(c model.Candidate) model.Candidate {
+	return model.Candidate{
+		ID:                c.ID,
+		Name:              c.Name,
+		Address:           c.Address,
+		Experience:        c.Experience,
+		WillingToRelocate: c.WillingToRelocate,
+		Email:             c.Email,
+		Phone:             c.Phone,
+		ErasedAt:          timestampPtr(c.ErasedAt),
+	}
+}
+
+// basicCandidate returns the columns of a candidate read with its scores and
+// applications.
+func basicCandidate(c model.Candidate) *model.Candidate {
+	return &model.Candidate{
+		ID:                c.ID,
+		Name:              c.Name,
+		Address:           c.Address,
+		Experience:        c.Experience,
+		WillingToRelocate: c.WillingToRelocate,
+	}
+}
+
+func (r *candidateRepository) GetCandidateByID(ctx context.Context, id string) (*model.Candidate, error) {
+	var result model.Candidate
+
+	err := r.store.read(ctx, func(t *tables) (err error) {
+		result, err = find(t.candidates, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *candidateRepository) PostCandidate(ctx context.Context, model *model.Candidate) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row := candidate(*model)
+		if err := insert(t.candidates, "candidate", row.ID, row); err != nil {
+			return err
+		}
+
+		if err := t.replaceSkills(row.ID, model.Skills); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityCandidate, actionCreate, row.ID, nil, candidateRow(row))
+	})
+}
+
+// replaceSkills stores the skills of a candidate in place of the ones stored
+// before.
+func (t *tables) replaceSkills(candidateID string, skills []string) error {
+	seen := map[string]bool{}
+	for _, skill := range skills {
+		if seen[skill] {
+			return errDuplicate("candidate_skills", candidateID+" "+skill)
+		}
+
+		seen[skill] = true
+	}
+
+	delete(t.skills, candidateID)
+	if len(skills) > 0 {
+		t.skills[candidateID] = append([]string{}, skills...)
+	}
+
+	return nil
+}
+
+// LoadSkills fills the Skills of every candidate.
+func (r *candidateRepository) LoadSkills(ctx context.Context, candidates ...*model.Candidate) error {
+	return r.store.read(ctx, func(t *tables) error {
+		for _, candidate := range candidates {
+			skills := append([]string{}, t.skills[candidate.ID]...)
+			sort.Strings(skills)
+			candidate.Skills = model.NormalizeSkills(skills)
+		}
+
+		return nil
+	})
+}
+
+func (r *candidateRepository) GetCandidateList(ctx context.Context, filter model.CandidateListRequest) (*[]model.Candidate, int, error) {
+	var (
+		result = []model.Candidate{}
+		total  int
+	)
+
+	err := r.store.read(ctx, func(t *tables) error {
+		var (
+			candidates []model.Candidate
+			search     = newTextSearch(filter.Query, true)
+			assigned   = map[string]bool{}
+		)
+
+		for _, assignment := range t.interviewerAssignments {
+			if assignment.UserID == filter.AssignedTo {
+				assigned[assignment.CandidateID] = true
+			}
+		}
+
+		for _, candidate := range t.candidates {
+			if filter.ExperienceMin != nil && candidate.Experience < *filter.ExperienceMin ||
+				filter.ExperienceMax != nil && candidate.Experience > *filter.ExperienceMax ||
+				filter.WillingToRelocate != "" && candidate.WillingToRelocate != filter.WillingToRelocate ||
+				filter.AssignedTo != "" && !assigned[candidate.ID] {
+				continue
+			}
+
+			if search != nil {
+				candidate.Relevance = search.relevance([]string{candidate.Name, candidate.Address}, t.skills[candidate.ID])
+				if candidate.Relevance == 0 {
+					continue
+				}
+			}
+
+			candidates = append(candidates, candidate)
+		}
+
+		// a search ranks the candidates by relevance unless another sort is
+		// asked
+		fallback := []compare[model.Candidate]{candidateSortColumns["name"]}
+		if search != nil {
+			fallback = append([]compare[model.Candidate]{func(a, b model.Candidate) int { return compareFloats(b.Relevance, a.Relevance) }}, fallback...)
+		}
+
+		orderBy(candidates, filter.Sort, candidateSortColumns, fallback, func(c model.Candidate) string { return c.ID })
+		total = len(candidates)
+		result = append(result, page(candidates, filter.Pagination)...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &result, total, nil
+}
+
+func (r *candidateRepository) UpdateCandidate(ctx context.Context, model *model.Candidate) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.candidates, model.ID)
+		if err != nil {
+			return err
+		}
+
+		after := candidate(*model)
+		after.ErasedAt = before.ErasedAt
+		t.candidates[after.ID] = after
+
+		if err = t.replaceSkills(after.ID, model.Skills); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityCandidate, actionUpdate, after.ID, candidateRow(before), candidateRow(after))
+	})
+}
+
+func (r *candidateRepository) DeleteCandidate(ctx context.Context, id string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.candidates, id)
+		if err != nil {
+			return err
+		}
+
+		if err = t.deleteCandidate(id); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityCandidate, actionDelete, id, candidateRow(before), nil)
+	})
+}
+
+// deleteCandidate deletes a candidate with their skills, consents and
+// interviewer assignments, unless other rows still refer to them.
+func (t *tables) deleteCandidate(id string) error {
+	for _, err := range []error{
+		restrict(t.candidateScores, func(s model.CandidateScore) string { return s.CandidateID }, "candidate", id, "candidate_score"),
+		restrict(t.scorecards, func(s model.Scorecard) string { return s.CandidateID }, "candidate", id, "scorecard"),
+		restrict(t.applications, func(a model.Application) string { return a.CandidateID }, "candidate", id, "application"),
+		restrict(t.attachments, func(a model.Attachment) string { return a.CandidateID }, "candidate", id, "attachment"),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	delete(t.candidates, id)
+	delete(t.skills, id)
+	cascade(t.consents, func(c model.CandidateConsent) string { return c.CandidateID }, id)
+	cascade(t.interviewerAssignments, func(a model.InterviewerAssignment) string { return a.CandidateID }, id)
+
+	return nil
+}
+
+// recruitmentIDs returns the recruitments a candidate takes part in, through
+// a score, a scorecard, an application or an interviewer.
+func (t *tables) recruitmentIDs(candidateID string) map[string]bool {
+	result := map[string]bool{}
+
+	for _, score := range t.candidateScores {
+		if score.CandidateID == candidateID {
+			result[score.RecruitmentID] = true
+		}
+	}
+
+	for _, scorecard := range t.scorecards {
+		if scorecard.CandidateID == candidateID {
+			result[scorecard.RecruitmentID] = true
+		}
+	}
+
+	for _, application := range t.applications {
+		if application.CandidateID == candidateID {
+			result[application.RecruitmentID] = true
+		}
+	}
+
+	for _, assignment := range t.interviewerAssignments {
+		if assignment.CandidateID == candidateID {
+			result[assignment.RecruitmentID] = true
+		}
+	}
+
+	return result
+}
+
+// GetSharedRecruitmentIDs returns the recruitments both candidates take part
+// in, through a score, a scorecard, an application or an interviewer.
+func (r *candidateRepository) GetSharedRecruitmentIDs(ctx context.Context, id, otherID string) ([]string, error) {
+	var result []string
+
+	err := r.store.read(ctx, func(t *tables) error {
+		other := t.recruitmentIDs(otherID)
+		for recruitmentID := range t.recruitmentIDs(id) {
+			if other[recruitmentID] {
+				result = append(result, recruitmentID)
+			}
+		}
+
+		sort.Strings(result)
+
+		return nil
+	})
+
+	return result, err
+}
+
+// MergeCandidate moves the rows referencing the duplicate to the candidate
+// with id and deletes the duplicate. The merge is audited under the
+// duplicate, with the candidate it was merged into as the state after.
+func (r *candidateRepository) MergeCandidate(ctx context.Context, id, duplicateID string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.candidates, duplicateID)
+		if err != nil {
+			return err
+		}
+
+		if err = t.moveCandidate(id, duplicateID); err != nil {
+			return err
+		}
+
+		if err = t.deleteCandidate(duplicateID); err != nil {
+			return err
+		}
+
+		after, err := find(t.candidates, id)
+		if err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityCandidate, actionMerge, duplicateID, candidateRow(before), candidateRow(after))
+	})
+}
+
+// moveCandidate points the rows referencing the candidate from to the
+// candidate to, keeping the unique keys they are part of.
+func (t *tables) moveCandidate(to, from string) error {
+	if from == to {
+		return nil
+	}
+
+	if err := exists(t.candidates, "candidate", to); err != nil {
+		return err
+	}
+
+	for key, score := range t.candidateScores {
+		if score.CandidateID == from {
+			score.CandidateID = to
+			if err := unique(t.candidateScores, candidateScoreKey, "candidate_score", key, score); err != nil {
+				return err
+			}
+
+			t.candidateScores[key] = score
+		}
+	}
+
+	for key, scorecard := range t.scorecards {
+		if scorecard.CandidateID == from {
+			scorecard.CandidateID = to
+			if err := unique(t.scorecards, scorecardKey, "scorecard", key, scorecard); err != nil {
+				return err
+			}
+
+			t.scorecards[key] = scorecard
+		}
+	}
+
+	for key, application := range t.applications {
+		if application.CandidateID == from {
+			application.CandidateID = to
+			if err := unique(t.applications, applicationKey, "application", key, application); err != nil {
+				return err
+			}
+
+			t.applications[key] = application
+		}
+	}
+
+	for key, assignment := range t.interviewerAssignments {
+		if assignment.CandidateID == from {
+			assignment.CandidateID = to
+			if err := unique(t.interviewerAssignments, interviewerAssignmentKey, "interviewer_assignment", key, assignment); err != nil {
+				return err
+			}
+
+			t.interviewerAssignments[key] = assignment
+		}
+	}
+
+	for key, attachment := range t.attachments {
+		if attachment.CandidateID == from {
+			attachment.CandidateID = to
+			t.attachments[key] = attachment
+		}
+	}
+
+	for key, consent := range t.consents {
+		if consent.CandidateID == from {
+			consent.CandidateID = to
+			t.consents[key] = consent
+		}
+	}
+
+	return nil
+}
+
+// EraseCandidate anonymizes a candidate that has not been erased yet. The
+// row is kept for the scores and applications referencing it, but the
+// personal data of the candidate, their skills, their consents and the
+// comments and notes about them are cleared, also from the audit log. The
+// erasure is audited without the state before it.
+func (r *candidateRepository) EraseCandidate(ctx context.Context, id string, erasedAt time.Time) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		after, err := find(t.candidates, id)
+		if err != nil {
+			return err
+		}
+
+		if after.ErasedAt != nil {
+			return sql.ErrNoRows
+		}
+
+		after.Name, after.Address, after.Email, after.Phone = model.ErasedCandidateName, "", "", ""
+		after.ErasedAt = timestampPtr(&erasedAt)
+		t.candidates[id] = after
+
+		delete(t.skills, id)
+		cascade(t.consents, func(c model.CandidateConsent) string { return c.CandidateID }, id)
+
+		for key, scorecard := range t.scorecards {
+			if scorecard.CandidateID == id {
+				scorecard.Comment = ""
+				t.scorecards[key] = scorecard
+			}
+		}
+
+		for key, event := range t.applicationStageEvents {
+			if application, ok := t.applications[event.ApplicationID]; ok && application.CandidateID == id {
+				event.Note = ""
+				t.applicationStageEvents[key] = event
+			}
+		}
+
+		if err = t.redactAuditLogs(id); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityCandidate, actionErase, id, nil, candidateRow(after))
+	})
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"talentapp/model"
+	"talentapp/repository"
+	"time"
+)
+
+type candidateConsentRepository struct {
+	store *Store
+}
+
+func NewCandidateConsentRepository(store *Store) repository.CandidateConsentRepository {
+	return &candidateConsentRepository{store}
+}
+
+// consent returns the stored columns of a consent.
+func consent(c model.CandidateConsent) model.CandidateConsent {
+	return model.CandidateConsent{
+		ID:          c.ID,
+		CandidateID: c.CandidateID,
+		Purpose:     c.Purpose,
+		Source:      c.Source,
+		GrantedAt:   timestamp(c.GrantedAt),
+		ExpiresAt:   timestamp(c.ExpiresAt),
+		WithdrawnAt: timestampPtr(c.WithdrawnAt),
+		RecordedBy:  c.RecordedBy,
+		CreatedAt:   timestamp(c.CreatedAt),
+	}
+}
+
+func (r *candidateConsentRepository) GetConsentByID(ctx context.Context, id string) (*model.CandidateConsent, error) {
+	var result model.CandidateConsent
+
+	err := r.store.read(ctx, func(t *tables) error {
+		row, err := find(t.consents, id)
+		result = consent(row)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// candidateConsents returns the consents of a candidate, most recently
+// granted first.
+func (t *tables) candidateConsents(candidateID string) []model.CandidateConsent {
+	var result []model.CandidateConsent
+	for _, row := range t.consents {
+		if row.CandidateID == candidateID {
+			result = append(result, consent(row))
+		}
+	}
+
+	orderBy(result, "", nil, []compare[model.CandidateConsent]{
+		func(a, b model.CandidateConsent) int { return compareTimes(b.GrantedAt, a.GrantedAt) },
+		func(a, b model.CandidateConsent) int { return compareTimes(b.CreatedAt, a.CreatedAt) },
+	}, func(c model.CandidateConsent) string { return c.ID })
+
+	return result
+}
+
+// GetConsentListByCandidateID returns the consents of a candidate, most
+// recently granted first.
+func (r *candidateConsentRepository) GetConsentListByCandidateID(ctx context.Context, candidateID string) (*[]model.CandidateConsent, error) {
+	var result = []model.CandidateConsent{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		result = append(result, t.candidateConsents(candidateID)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// LoadConsents fills the Consents of every candidate.
+func (r *candidateConsentRepository) LoadConsents(ctx context.Context, candidates ...*model.Candidate) error {
+	return r.store.read(ctx, func(t *tables) error {
+		for _, candidate := range candidates {
+			candidate.Consents = t.candidateConsents(candidate.ID)
+		}
+
+		return nil
+	})
+}
+
+func (r *candidateConsentRepository) PostConsent(ctx context.Context, model *model.CandidateConsent) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row := consent(*model)
+		if err := exists(t.candidates, "candidate", row.CandidateID); err != nil {
+			return err
+		}
+
+		if err := insert(t.consents, "candidate_consent", row.ID, row); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityCandidateConsent, actionCreate, row.ID, nil, consentRow(row))
+	})
+}
+
+// WithdrawConsent ends a consent that has not been withdrawn yet.
+func (r *candidateConsentRepository) WithdrawConsent(ctx context.Context, id string, withdrawnAt time.Time) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.consents, id)
+		if err != nil {
+			return err
+		}
+
+		if before.WithdrawnAt != nil {
+			return sql.ErrNoRows
+		}
+
+		after := before
+		after.WithdrawnAt = timestampPtr(&withdrawnAt)
+		t.consents[id] = after
+
+		return t.recordAudit(ctx, entityCandidateConsent, actionUpdate, id, consentRow(before), consentRow(after))
+	})
+}
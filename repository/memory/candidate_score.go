@@ -0,0 +1,305 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"talentapp/model"
+	"talentapp/repository"
+)
+
+var candidateScoreSortColumns = map[string]compare[model.CandidateScore]{
+	"overall_score": compareOverallScores,
+	"name":          func(a, b model.CandidateScore) int { return compareStrings(a.Candidate.Name, b.Candidate.Name) },
+	"experience": func(a, b model.CandidateScore) int {
+		return compareInts(a.Candidate.Experience, b.Candidate.Experience)
+	},
+}
+
+func compareOverallScores(a, b model.CandidateScore) int {
+	return compareFloats(a.OverallScore, b.OverallScore)
+}
+
+func candidateScoreKey(s model.CandidateScore) string {
+	return s.RecruitmentID + " " + s.CandidateID
+}
+
+type candidateScoreRepository struct {
+	store *Store
+}
+
+func NewCandidateScoreRepository(store *Store) repository.CandidateScoreRepository {
+	return &candidateScoreRepository{store}
+}
+
+// candidateScore returns the stored columns of a score, without relations.
+func candidateScore(s model.CandidateScore) model.CandidateScore {
+	return model.CandidateScore{
+		ID:                     s.ID,
+		CandidateID:            s.CandidateID,
+		RecruitmentID:          s.RecruitmentID,
+		WillingToRelocateScore: s.WillingToRelocateScore,
+		AttitudeScore:          s.AttitudeScore,
+		SkillScore:             s.SkillScore,
+		ExperienceScore:        s.ExperienceScore,
+		OverallScore:           s.OverallScore,
+		ScoringProfileID:       s.ScoringProfileID,
+		InterviewerCount:       s.InterviewerCount,
+		AttitudeSpread:         s.AttitudeSpread,
+		SkillSpread:            s.SkillSpread,
+	}
+}
+
+// findCandidateScore returns the stored score of a candidate in a
+// recruitment, or sql.ErrNoRows.
+func (t *tables) findCandidateScore(recruitmentID, candidateID string) (model.CandidateScore, error) {
+	for _, score := range t.candidateScores {
+		if score.RecruitmentID == recruitmentID && score.CandidateID == candidateID {
+			return score, nil
+		}
+	}
+
+	return model.CandidateScore{}, sql.ErrNoRows
+}
+
+// candidateScore returns a score as the SQL repositories read it: with its
+// rank in the recruitment, its candidate, its recruitment with the job, and
+// the overall score rounded to two decimals.
+func (t *tables) candidateScore(score model.CandidateScore) (model.CandidateScore, error) {
+	result := score
+	result.Rank = 1
+	for _, other := range t.candidateScores {
+		if other.RecruitmentID == score.RecruitmentID && other.OverallScore > score.OverallScore {
+			result.Rank++
+		}
+	}
+
+	result.OverallScore = math.Round(score.OverallScore*100) / 100
+	if profile, ok := t.scoringProfiles[score.ScoringProfileID]; ok {
+		result.ScoringProfileVersion = profile.Version
+	}
+
+	candidate, err := find(t.candidates, score.CandidateID)
+	if err != nil {
+		return result, err
+	}
+
+	result.Candidate = basicCandidate(candidate)
+	result.Recruitment, err = t.recruitment(score.RecruitmentID)
+
+	return result, err
+}
+
+// GetCandidateScoreByID returns the score of a candidate in the recruitment
+// id, with its relations, or sql.ErrNoRows.
+func (r *candidateScoreRepository) GetCandidateScoreByID(ctx context.Context, id, candidateID string) (*model.CandidateScore, error) {
+	var result model.CandidateScore
+
+	err := r.store.read(ctx, func(t *tables) error {
+		score, err := t.findCandidateScore(id, candidateID)
+		if err != nil {
+			return err
+		}
+
+		result, err = t.candidateScore(score)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// LoadRelations fills the Candidate and the Recruitment, with its job, of
+// every score.
+func (r *candidateScoreRepository) LoadRelations(ctx context.Context, scores ...*model.CandidateScore) error {
+	return r.store.read(ctx, func(t *tables) error {
+		for _, score := range scores {
+			score.Candidate, score.Recruitment = nil, nil
+			if candidate, ok := t.candidates[score.CandidateID]; ok {
+				score.Candidate = basicCandidate(candidate)
+			}
+
+			if recruitment, err := t.recruitment(score.RecruitmentID); err == nil {
+				score.Recruitment = recruitment
+			}
+		}
+
+		return nil
+	})
+}
+
+// checkCandidateScore checks the rows a score refers to exist and that the
+// candidate has no other score in the recruitment.
+func (t *tables) checkCandidateScore(score model.CandidateScore) error {
+	for _, err := range []error{
+		exists(t.candidates, "candidate", score.CandidateID),
+		exists(t.recruitments, "recruitment", score.RecruitmentID),
+		unique(t.candidateScores, candidateScoreKey, "candidate_score", score.ID, score),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	if score.ScoringProfileID != "" {
+		return exists(t.scoringProfiles, "scoring_profile", score.ScoringProfileID)
+	}
+
+	return nil
+}
+
+func (r *candidateScoreRepository) PostCandidateScore(ctx context.Context, model *model.CandidateScore) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row := candidateScore(*model)
+		if err := t.checkCandidateScore(row); err != nil {
+			return err
+		}
+
+		if err := insert(t.candidateScores, "candidate_score", row.ID, row); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityCandidateScore, actionCreate, row.RecruitmentID, nil, candidateScoreRow(row))
+	})
+}
+
+func (r *candidateScoreRepository) GetCandidateScoreListByRecruitmentID(ctx context.Context, recruitmentID string, filter model.CandidateScoreListRequest) (*[]model.CandidateScore, int, error) {
+	var (
+		result = []model.CandidateScore{}
+		total  int
+	)
+
+	err := r.store.read(ctx, func(t *tables) error {
+		var scores []model.CandidateScore
+
+		for _, row := range t.candidateScores {
+			if row.RecruitmentID != recruitmentID {
+				continue
+			}
+
+			score, err := t.candidateScore(row)
+			if err != nil {
+				return err
+			}
+
+			candidate := score.Candidate
+			if filter.ExperienceMin != nil && candidate.Experience < *filter.ExperienceMin ||
+				filter.ExperienceMax != nil && candidate.Experience > *filter.ExperienceMax ||
+				filter.WillingToRelocate != "" && candidate.WillingToRelocate != filter.WillingToRelocate ||
+				filter.AssignedTo != "" && !t.assigned(recruitmentID, candidate.ID, filter.AssignedTo) {
+				continue
+			}
+
+			// scores are ordered by their stored value, not the rounded one
+			score.OverallScore = row.OverallScore
+			scores = append(scores, score)
+		}
+
+		fallback := []compare[model.CandidateScore]{descending(compareOverallScores)}
+		orderBy(scores, filter.Sort, candidateScoreSortColumns, fallback, func(s model.CandidateScore) string { return s.ID })
+		for i := range scores {
+			scores[i].OverallScore = math.Round(scores[i].OverallScore*100) / 100
+		}
+
+		total = len(scores)
+		result = append(result, page(scores, filter.Pagination)...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &result, total, nil
+}
+
+// GetCandidateScoreListByCandidateID returns the scores of a candidate in
+// every recruitment, each with its rank in that recruitment, latest deadline
+// first.
+func (r *candidateScoreRepository) GetCandidateScoreListByCandidateID(ctx context.Context, candidateID string) (*[]model.CandidateScore, error) {
+	var result = []model.CandidateScore{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, row := range t.candidateScores {
+			if row.CandidateID != candidateID {
+				continue
+			}
+
+			score, err := t.candidateScore(row)
+			if err != nil {
+				return err
+			}
+
+			result = append(result, score)
+		}
+
+		fallback := []compare[model.CandidateScore]{func(a, b model.CandidateScore) int { return compareDeadlines(*b.Recruitment, *a.Recruitment) }}
+		orderBy(result, "", nil, fallback, func(s model.CandidateScore) string { return s.ID })
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *candidateScoreRepository) UpdateCandidateScore(ctx context.Context, model *model.CandidateScore) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := t.findCandidateScore(model.RecruitmentID, model.CandidateID)
+		if err != nil {
+			return err
+		}
+
+		after := candidateScore(*model)
+		after.ID = before.ID
+		if err = t.checkCandidateScore(after); err != nil {
+			return err
+		}
+
+		t.candidateScores[after.ID] = after
+
+		return t.recordAudit(ctx, entityCandidateScore, actionUpdate, after.RecruitmentID, candidateScoreRow(before), candidateScoreRow(after))
+	})
+}
+
+func (r *candidateScoreRepository) DeleteCandidateScore(ctx context.Context, recruitmentID, candidateID string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := t.findCandidateScore(recruitmentID, candidateID)
+		if err != nil {
+			return err
+		}
+
+		delete(t.candidateScores, before.ID)
+
+		return t.recordAudit(ctx, entityCandidateScore, actionDelete, recruitmentID, candidateScoreRow(before), nil)
+	})
+}
+
+func (r *candidateScoreRepository) CountCandidateScore(ctx context.Context, recruitmentID, candidateID string) (int, error) {
+	return r.count(ctx, func(s model.CandidateScore) bool {
+		return s.RecruitmentID == recruitmentID && s.CandidateID == candidateID
+	})
+}
+
+func (r *candidateScoreRepository) CountCandidateScoreByCandidateID(ctx context.Context, candidateID string) (int, error) {
+	return r.count(ctx, func(s model.CandidateScore) bool { return s.CandidateID == candidateID })
+}
+
+func (r *candidateScoreRepository) CountCandidateScoreByRecruitmentID(ctx context.Context, recruitmentID string) (int, error) {
+	return r.count(ctx, func(s model.CandidateScore) bool { return s.RecruitmentID == recruitmentID })
+}
+
+func (r *candidateScoreRepository) count(ctx context.Context, match func(model.CandidateScore) bool) (int, error) {
+	var total int
+
+	err := r.store.read(ctx, func(t *tables) error {
+		total = count(t.candidateScores, match)
+		return nil
+	})
+
+	return total, err
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"database/sql"
+	"fmt"
+	"talentapp/repository"
+	"time"
+)
+
+// The checks below stand in for the keys of the SQL schema. They return the
+// errors the SQL repositories translate constraint violations into.
+
+func errDuplicate(table, key string) error {
+	return fmt.Errorf("%w: %s %s", repository.ErrDuplicate, table, key)
+}
+
+// errMissing is returned by a write referring to a row that does not exist.
+func errMissing(table, id string) error {
+	return fmt.Errorf("%w: %s %s does not exist", repository.ErrReferenced, table, id)
+}
+
+// errReferenced is returned by the delete of a row others still refer to.
+func errReferenced(table, id, by string) error {
+	return fmt.Errorf("%w: %s %s is referenced by %s", repository.ErrReferenced, table, id, by)
+}
+
+// exists returns errMissing unless the row id is in rows.
+func exists[V any](rows map[string]V, table, id string) error {
+	if _, ok := rows[id]; !ok {
+		return errMissing(table, id)
+	}
+
+	return nil
+}
+
+// insert adds row under id, unless a row has that id already.
+func insert[V any](rows map[string]V, table, id string, row V) error {
+	if _, ok := rows[id]; ok {
+		return errDuplicate(table, id)
+	}
+
+	rows[id] = row
+
+	return nil
+}
+
+// restrict returns errReferenced when one of rows refers to the row id of
+// table through the column read by ref.
+func restrict[V any](rows map[string]V, ref func(V) string, table, id, by string) error {
+	for _, row := range rows {
+		if ref(row) == id {
+			return errReferenced(table, id, by)
+		}
+	}
+
+	return nil
+}
+
+// cascade deletes the rows referring to id through the column read by ref.
+func cascade[V any](rows map[string]V, ref func(V) string, id string) {
+	for key, row := range rows {
+		if ref(row) == id {
+			delete(rows, key)
+		}
+	}
+}
+
+// unique returns errDuplicate when another row than id has the same key.
+func unique[V any](rows map[string]V, key func(V) string, table, id string, row V) error {
+	for otherID, other := range rows {
+		if otherID != id && key(other) == key(row) {
+			return errDuplicate(table, key(row))
+		}
+	}
+
+	return nil
+}
+
+// find returns the row id of rows, or sql.ErrNoRows.
+func find[V any](rows map[string]V, id string) (V, error) {
+	row, ok := rows[id]
+	if !ok {
+		return row, sql.ErrNoRows
+	}
+
+	return row, nil
+}
+
+// timestamp drops the monotonic clock reading of t, which a database does
+// not store either.
+func timestamp(t time.Time) time.Time {
+	return t.Round(0)
+}
+
+func timestampPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	result := timestamp(*t)
+	return &result
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"talentapp/model"
+	"talentapp/repository"
+)
+
+func interviewerAssignmentKey(a model.InterviewerAssignment) string {
+	return a.RecruitmentID + " " + a.CandidateID + " " + a.UserID
+}
+
+type interviewerAssignmentRepository struct {
+	store *Store
+}
+
+func NewInterviewerAssignmentRepository(store *Store) repository.InterviewerAssignmentRepository {
+	return &interviewerAssignmentRepository{store}
+}
+
+// assigned tells whether the user is assigned to the candidate in the
+// recruitment.
+func (t *tables) assigned(recruitmentID, candidateID, userID string) bool {
+	return count(t.interviewerAssignments, func(a model.InterviewerAssignment) bool {
+		return a.RecruitmentID == recruitmentID && a.CandidateID == candidateID && a.UserID == userID
+	}) > 0
+}
+
+func (r *interviewerAssignmentRepository) GetInterviewerAssignmentListByRecruitmentID(ctx context.Context, recruitmentID string) (*[]model.InterviewerAssignment, error) {
+	var result = []model.InterviewerAssignment{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, assignment := range t.interviewerAssignments {
+			if assignment.RecruitmentID == recruitmentID {
+				result = append(result, assignment)
+			}
+		}
+
+		orderBy(result, "", nil, []compare[model.InterviewerAssignment]{func(a, b model.InterviewerAssignment) int { return compareTimes(a.CreatedAt, b.CreatedAt) }},
+			func(a model.InterviewerAssignment) string { return a.ID })
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *interviewerAssignmentRepository) CountInterviewerAssignment(ctx context.Context, recruitmentID, candidateID, userID string) (int, error) {
+	return r.count(ctx, func(a model.InterviewerAssignment) bool {
+		return a.RecruitmentID == recruitmentID && a.CandidateID == candidateID && a.UserID == userID
+	})
+}
+
+// CountInterviewerAssignmentByCandidateID counts the assignments of the
+// user to the candidate, in every recruitment.
+func (r *interviewerAssignmentRepository) CountInterviewerAssignmentByCandidateID(ctx context.Context, candidateID, userID string) (int, error) {
+	return r.count(ctx, func(a model.InterviewerAssignment) bool { return a.CandidateID == candidateID && a.UserID == userID })
+}
+
+// CountInterviewerAssignmentByApplicationID counts the assignments of the
+// user to the candidate of the application, in its recruitment.
+func (r *interviewerAssignmentRepository) CountInterviewerAssignmentByApplicationID(ctx context.Context, applicationID, userID string) (int, error) {
+	var total int
+
+	err := r.store.read(ctx, func(t *tables) error {
+		if application, ok := t.applications[applicationID]; ok && t.assigned(application.RecruitmentID, application.CandidateID, userID) {
+			total = 1
+		}
+
+		return nil
+	})
+
+	return total, err
+}
+
+func (r *interviewerAssignmentRepository) count(ctx context.Context, match func(model.InterviewerAssignment) bool) (int, error) {
+	var total int
+
+	err := r.store.read(ctx, func(t *tables) error {
+		total = count(t.interviewerAssignments, match)
+		return nil
+	})
+
+	return total, err
+}
+
+func (r *interviewerAssignmentRepository) PostInterviewerAssignment(ctx context.Context, model *model.InterviewerAssignment) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row := *model
+		row.CreatedAt = timestamp(row.CreatedAt)
+
+		for _, err := range []error{
+			exists(t.recruitments, "recruitment", row.RecruitmentID),
+			exists(t.candidates, "candidate", row.CandidateID),
+			exists(t.users, "app_user", row.UserID),
+			unique(t.interviewerAssignments, interviewerAssignmentKey, "interviewer_assignment", row.ID, row),
+		} {
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := insert(t.interviewerAssignments, "interviewer_assignment", row.ID, row); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityInterviewerAssignment, actionCreate, row.ID, nil, interviewerAssignmentRow(row))
+	})
+}
+
+func (r *interviewerAssignmentRepository) DeleteInterviewerAssignment(ctx context.Context, recruitmentID, id string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, ok := t.interviewerAssignments[id]
+		if !ok || before.RecruitmentID != recruitmentID {
+			return sql.ErrNoRows
+		}
+
+		delete(t.interviewerAssignments, id)
+
+		return t.recordAudit(ctx, entityInterviewerAssignment, actionDelete, id, interviewerAssignmentRow(before), nil)
+	})
+}
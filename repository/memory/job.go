@@ -0,0 +1,188 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"talentapp/model"
+	"talentapp/repository"
+)
+
+var jobSortColumns = map[string]compare[model.Job]{
+	"position":   func(a, b model.Job) int { return compareStrings(a.Position, b.Position) },
+	"department": func(a, b model.Job) int { return compareStrings(a.Department, b.Department) },
+}
+
+type jobRepository struct {
+	store *Store
+}
+
+func NewJobRepository(store *Store) repository.JobRepository {
+	return &jobRepository{store}
+}
+
+// job returns the stored columns of a job, with its requirements read back
+// from JSON like the SQL repositories do, so no slice is shared with the
+// caller.
+func job(j model.Job) (model.Job, error) {
+	result := model.Job{
+		ID:             j.ID,
+		Position:       j.Position,
+		Department:     j.Department,
+		Requester:      j.Requester,
+		JobDescription: j.JobDescription,
+		Criteria:       j.Criteria,
+	}
+
+	data, err := json.Marshal(j.Requirements)
+	if err != nil {
+		return result, err
+	}
+
+	if err = json.Unmarshal(data, &result.Requirements); err != nil {
+		return result, err
+	}
+
+	result.Requirements.Normalize()
+
+	return result, nil
+}
+
+func (t *tables) job(id string) (model.Job, error) {
+	result, err := find(t.jobs, id)
+	if err != nil {
+		return result, err
+	}
+
+	return job(result)
+}
+
+func (r *jobRepository) GetJobByID(ctx context.Context, id string) (*model.Job, error) {
+	var result model.Job
+
+	err := r.store.read(ctx, func(t *tables) (err error) {
+		result, err = t.job(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *jobRepository) PostJob(ctx context.Context, model *model.Job) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row, err := job(*model)
+		if err != nil {
+			return err
+		}
+
+		if err = insert(t.jobs, "job", row.ID, row); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityJob, actionCreate, row.ID, nil, jobRow(row))
+	})
+}
+
+func (r *jobRepository) GetJobList(ctx context.Context, filter model.JobListRequest) (*[]model.Job, int, error) {
+	var (
+		result = []model.Job{}
+		total  int
+	)
+
+	err := r.store.read(ctx, func(t *tables) error {
+		var (
+			jobs   []model.Job
+			search = newTextSearch(filter.Query, false)
+		)
+
+		for id, row := range t.jobs {
+			if filter.Department != "" && row.Department != filter.Department {
+				continue
+			}
+
+			var relevance float64
+			if search != nil {
+				relevance = search.relevance([]string{row.Position, row.Department, row.JobDescription, row.Criteria}, nil)
+				if relevance == 0 {
+					continue
+				}
+			}
+
+			job, err := t.job(id)
+			if err != nil {
+				return err
+			}
+
+			job.Relevance = relevance
+			jobs = append(jobs, job)
+		}
+
+		// a search ranks the jobs by relevance unless another sort is asked
+		fallback := []compare[model.Job]{jobSortColumns["position"]}
+		if search != nil {
+			fallback = append([]compare[model.Job]{func(a, b model.Job) int { return compareFloats(b.Relevance, a.Relevance) }}, fallback...)
+		}
+
+		orderBy(jobs, filter.Sort, jobSortColumns, fallback, func(j model.Job) string { return j.ID })
+		total = len(jobs)
+		result = append(result, page(jobs, filter.Pagination)...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &result, total, nil
+}
+
+func (r *jobRepository) UpdateJob(ctx context.Context, model *model.Job) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.jobs, model.ID)
+		if err != nil {
+			return err
+		}
+
+		after, err := job(*model)
+		if err != nil {
+			return err
+		}
+
+		t.jobs[after.ID] = after
+
+		return t.recordAudit(ctx, entityJob, actionUpdate, after.ID, jobRow(before), jobRow(after))
+	})
+}
+
+func (r *jobRepository) DeleteJob(ctx context.Context, id string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.jobs, id)
+		if err != nil {
+			return err
+		}
+
+		err = restrict(t.recruitments, func(r model.Recruitment) string { return r.JobID }, "job", id, "recruitment")
+		if err != nil {
+			return err
+		}
+
+		for profileID, profile := range t.scoringProfiles {
+			if profile.JobID != id {
+				continue
+			}
+
+			err = restrict(t.candidateScores, func(s model.CandidateScore) string { return s.ScoringProfileID }, "scoring_profile", profileID, "candidate_score")
+			if err != nil {
+				return err
+			}
+
+			delete(t.scoringProfiles, profileID)
+		}
+
+		delete(t.jobs, id)
+
+		return t.recordAudit(ctx, entityJob, actionDelete, id, jobRow(before), nil)
+	})
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"talentapp/repository"
+	"time"
+)
+
+type leaseRepository struct {
+	store *Store
+}
+
+func NewLeaseRepository(store *Store) repository.LeaseRepository {
+	return &leaseRepository{store}
+}
+
+// AcquireLease takes the lease when it is free or expired, or extends it
+// when holder already owns it. It reports whether holder owns the lease
+// afterwards.
+func (r *leaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	var acquired bool
+
+	err := r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		now := timestamp(time.Now())
+		current, ok := t.leases[name]
+		if ok && current.holder != holder && current.expiresAt.After(now) {
+			return nil
+		}
+
+		t.leases[name] = lease{holder: holder, expiresAt: now.Add(ttl)}
+		acquired = true
+
+		return nil
+	})
+
+	return acquired, err
+}
+
+// ReleaseLease gives the lease up so another instance can take it without
+// waiting for it to expire.
+func (r *leaseRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		if current, ok := t.leases[name]; ok && current.holder == holder {
+			current.expiresAt = timestamp(time.Now())
+			t.leases[name] = current
+		}
+
+		return nil
+	})
+}
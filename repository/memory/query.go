@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"talentapp/model"
+	"time"
+)
+
+// page returns the rows of a page of rows, like LIMIT and OFFSET.
+func page[T any](rows []T, p model.Pagination) []T {
+	offset := p.Offset()
+	if offset < 0 {
+		offset = 0
+	}
+
+	if offset >= len(rows) || p.Size <= 0 {
+		return nil
+	}
+
+	end := offset + p.Size
+	if end > len(rows) {
+		end = len(rows)
+	}
+
+	return rows[offset:end]
+}
+
+// compare orders two rows by one column, returning a negative number when a
+// comes first.
+type compare[T any] func(a, b T) int
+
+func compareStrings(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+func compareInts(a, b int) int {
+	return compareFloats(float64(a), float64(b))
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+
+	return 0
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+
+	return 0
+}
+
+// orderBy sorts rows by a sort key such as "name" or "-name", as the SQL
+// orderBy does: keys missing from columns fall back to the given ordering,
+// and rows that tie are ordered by id.
+func orderBy[T any](rows []T, sortKey string, columns map[string]compare[T], fallback []compare[T], id func(T) string) {
+	order := fallback
+	if column, ok := columns[strings.TrimPrefix(sortKey, "-")]; ok {
+		order = []compare[T]{column}
+		if strings.HasPrefix(sortKey, "-") {
+			order = []compare[T]{descending(column)}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, c := range order {
+			if result := c(rows[i], rows[j]); result != 0 {
+				return result < 0
+			}
+		}
+
+		return id(rows[i]) < id(rows[j])
+	})
+}
+
+func descending[T any](c compare[T]) compare[T] {
+	return func(a, b T) int {
+		return c(b, a)
+	}
+}
+
+// textSearch holds the terms of a query and the skills it names, and counts
+// how well a row matches them like the SQLite fallback of the SQL
+// repositories: one per column matching a term, and one per skill found.
+type textSearch struct {
+	terms  []string
+	skills map[string]bool
+}
+
+// newTextSearch returns the search of a list query, or nil when the list is
+// not searched.
+func newTextSearch(query string, skills bool) *textSearch {
+	if strings.TrimSpace(query) == "" {
+		return nil
+	}
+
+	result := &textSearch{terms: model.PrefixTerms(model.SearchTerms(query)), skills: map[string]bool{}}
+	if skills {
+		for _, name := range model.SearchSkills(query) {
+			result.skills[name] = true
+		}
+	}
+
+	return result
+}
+
+// relevance returns how well the columns and skills of a row match, zero
+// meaning they do not.
+func (s *textSearch) relevance(columns []string, skills []string) float64 {
+	var result float64
+
+	for _, column := range columns {
+		column = " " + strings.ToLower(column)
+		for _, term := range s.terms {
+			if strings.Contains(column, " "+term) {
+				result++
+			}
+		}
+	}
+
+	for _, skill := range skills {
+		if s.skills[strings.ToLower(skill)] {
+			result++
+		}
+	}
+
+	return result
+}
+
+// count counts the rows matching match.
+func count[V any](rows map[string]V, match func(V) bool) int {
+	var total int
+	for _, row := range rows {
+		if match(row) {
+			total++
+		}
+	}
+
+	return total
+}
@@ -0,0 +1,263 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"talentapp/model"
+	"talentapp/repository"
+	"time"
+)
+
+var recruitmentSortColumns = map[string]compare[model.Recruitment]{
+	"deadline": compareDeadlines,
+	"status":   func(a, b model.Recruitment) int { return compareStrings(a.Status, b.Status) },
+	"position": func(a, b model.Recruitment) int { return compareStrings(a.Job.Position, b.Job.Position) },
+}
+
+func compareDeadlines(a, b model.Recruitment) int {
+	return compareTimes(a.Deadline, b.Deadline)
+}
+
+type recruitmentRepository struct {
+	store *Store
+}
+
+func NewRecruitmentRepository(store *Store) repository.RecruitmentRepository {
+	return &recruitmentRepository{store}
+}
+
+// recruitment returns the stored columns of a recruitment, without its job.
+func recruitment(r model.Recruitment) model.Recruitment {
+	return model.Recruitment{
+		ID:               r.ID,
+		JobID:            r.JobID,
+		Status:           r.Status,
+		Deadline:         timestamp(r.Deadline),
+		ScoreAggregation: r.ScoreAggregation,
+		ClosedAt:         timestampPtr(r.ClosedAt),
+		ClosedReason:     r.ClosedReason,
+	}
+}
+
+// recruitment returns a recruitment together with its job, as the SQL
+// repositories read it.
+func (t *tables) recruitment(id string) (*model.Recruitment, error) {
+	result, err := find(t.recruitments, id)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := t.job(result.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ClosedAt = timestampPtr(result.ClosedAt)
+	result.Job = &job
+	result.DeadlineString = strings.Split(result.Deadline.String(), " ")[0]
+
+	return &result, nil
+}
+
+func (r *recruitmentRepository) GetRecruitmentByID(ctx context.Context, id string) (*model.Recruitment, error) {
+	var result *model.Recruitment
+
+	err := r.store.read(ctx, func(t *tables) (err error) {
+		result, err = t.recruitment(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// LoadJobs fills the Job of every recruitment.
+func (r *recruitmentRepository) LoadJobs(ctx context.Context, recruitments ...*model.Recruitment) error {
+	return r.store.read(ctx, func(t *tables) error {
+		for _, recruitment := range recruitments {
+			recruitment.Job = nil
+			if job, err := t.job(recruitment.JobID); err == nil {
+				recruitment.Job = &job
+			}
+		}
+
+		return nil
+	})
+}
+
+// checkRecruitment checks the job of a recruitment exists.
+func (t *tables) checkRecruitment(r model.Recruitment) error {
+	return exists(t.jobs, "job", r.JobID)
+}
+
+func (r *recruitmentRepository) CreateRecruitment(ctx context.Context, model *model.Recruitment) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row := recruitment(*model)
+		row.ClosedAt, row.ClosedReason = nil, ""
+		if err := t.checkRecruitment(row); err != nil {
+			return err
+		}
+
+		if err := insert(t.recruitments, "recruitment", row.ID, row); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityRecruitment, actionCreate, row.ID, nil, recruitmentRow(row))
+	})
+}
+
+func (r *recruitmentRepository) GetRecruitments(ctx context.Context, filter model.RecruitmentListRequest) (*[]model.Recruitment, int, error) {
+	var (
+		result = []model.Recruitment{}
+		total  int
+	)
+
+	err := r.store.read(ctx, func(t *tables) error {
+		var recruitments []model.Recruitment
+
+		for id := range t.recruitments {
+			recruitment, err := t.recruitment(id)
+			if err != nil {
+				return err
+			}
+
+			// the deadline is compared as text, like the SQL repositories
+			// compare it with the bounds of the day
+			deadline := recruitment.Deadline.Format("2006-01-02 15:04:05")
+			if filter.Status != "" && recruitment.Status != filter.Status ||
+				filter.Department != "" && recruitment.Job.Department != filter.Department ||
+				filter.DeadlineFrom != "" && deadline < filter.DeadlineFrom+" 00:00:00" ||
+				filter.DeadlineTo != "" && deadline > filter.DeadlineTo+" 23:59:59" {
+				continue
+			}
+
+			recruitments = append(recruitments, *recruitment)
+		}
+
+		fallback := []compare[model.Recruitment]{descending(compareDeadlines)}
+		orderBy(recruitments, filter.Sort, recruitmentSortColumns, fallback, func(r model.Recruitment) string { return r.ID })
+		total = len(recruitments)
+		result = append(result, page(recruitments, filter.Pagination)...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &result, total, nil
+}
+
+// UpdateRecruitmentStatus sets the status of a recruitment. Like the SQL
+// repositories, it does not report a recruitment that does not exist.
+func (r *recruitmentRepository) UpdateRecruitmentStatus(ctx context.Context, model *model.Recruitment) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, ok := t.recruitments[model.ID]
+		if !ok {
+			return t.recordAudit(ctx, entityRecruitment, actionStatusChange, model.ID, nil, nil)
+		}
+
+		after := before
+		after.Status, after.ClosedAt, after.ClosedReason = model.Status, timestampPtr(model.ClosedAt), model.ClosedReason
+		t.recruitments[after.ID] = after
+
+		return t.recordAudit(ctx, entityRecruitment, actionStatusChange, after.ID, recruitmentRow(before), recruitmentRow(after))
+	})
+}
+
+func (r *recruitmentRepository) UpdateRecruitment(ctx context.Context, model *model.Recruitment) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.recruitments, model.ID)
+		if err != nil {
+			return err
+		}
+
+		after := recruitment(*model)
+		if err = t.checkRecruitment(after); err != nil {
+			return err
+		}
+
+		t.recruitments[after.ID] = after
+
+		return t.recordAudit(ctx, entityRecruitment, actionUpdate, after.ID, recruitmentRow(before), recruitmentRow(after))
+	})
+}
+
+func (r *recruitmentRepository) DeleteRecruitment(ctx context.Context, id string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.recruitments, id)
+		if err != nil {
+			return err
+		}
+
+		for _, err := range []error{
+			restrict(t.candidateScores, func(s model.CandidateScore) string { return s.RecruitmentID }, "recruitment", id, "candidate_score"),
+			restrict(t.scorecards, func(s model.Scorecard) string { return s.RecruitmentID }, "recruitment", id, "scorecard"),
+			restrict(t.applications, func(a model.Application) string { return a.RecruitmentID }, "recruitment", id, "application"),
+		} {
+			if err != nil {
+				return err
+			}
+		}
+
+		delete(t.recruitments, id)
+		cascade(t.interviewerAssignments, func(a model.InterviewerAssignment) string { return a.RecruitmentID }, id)
+
+		return t.recordAudit(ctx, entityRecruitment, actionDelete, id, recruitmentRow(before), nil)
+	})
+}
+
+func (r *recruitmentRepository) CountRecruitmentByJobID(ctx context.Context, jobID string) (int, error) {
+	var total int
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, recruitment := range t.recruitments {
+			if recruitment.JobID == jobID {
+				total++
+			}
+		}
+
+		return nil
+	})
+
+	return total, err
+}
+
+// CloseExpiredRecruitments closes every open recruitment whose deadline is
+// before now and returns how many were closed. Each recruitment gets its own
+// audit entry.
+func (r *recruitmentRepository) CloseExpiredRecruitments(ctx context.Context, now time.Time, reason string) (int64, error) {
+	var closed int64
+
+	err := r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		var expired []model.Recruitment
+		for _, recruitment := range t.recruitments {
+			if recruitment.Status == model.RecruitmentOpen && recruitment.Deadline.Before(now) {
+				expired = append(expired, recruitment)
+			}
+		}
+
+		orderBy(expired, "", nil, nil, func(r model.Recruitment) string { return r.ID })
+
+		for _, before := range expired {
+			after := before
+			after.Status, after.ClosedAt, after.ClosedReason = model.RecruitmentClose, timestampPtr(&now), reason
+			t.recruitments[after.ID] = after
+
+			if err := t.recordAudit(ctx, entityRecruitment, actionStatusChange, after.ID, recruitmentRow(before), recruitmentRow(after)); err != nil {
+				return err
+			}
+
+			closed++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return closed, nil
+}
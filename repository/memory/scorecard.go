@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"talentapp/model"
+	"talentapp/repository"
+)
+
+func scorecardKey(s model.Scorecard) string {
+	return s.RecruitmentID + " " + s.CandidateID + " " + s.Interviewer
+}
+
+type scorecardRepository struct {
+	store *Store
+}
+
+func NewScorecardRepository(store *Store) repository.ScorecardRepository {
+	return &scorecardRepository{store}
+}
+
+func (r *scorecardRepository) GetScorecardByID(ctx context.Context, id string) (*model.Scorecard, error) {
+	var result model.Scorecard
+
+	err := r.store.read(ctx, func(t *tables) (err error) {
+		result, err = find(t.scorecards, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *scorecardRepository) GetScorecardListByCandidate(ctx context.Context, recruitmentID, candidateID string) (*[]model.Scorecard, error) {
+	return r.list(ctx, func(s model.Scorecard) bool { return s.RecruitmentID == recruitmentID && s.CandidateID == candidateID })
+}
+
+// GetScorecardListByCandidateID returns the scorecards of a candidate in
+// every recruitment.
+func (r *scorecardRepository) GetScorecardListByCandidateID(ctx context.Context, candidateID string) (*[]model.Scorecard, error) {
+	return r.list(ctx, func(s model.Scorecard) bool { return s.CandidateID == candidateID })
+}
+
+func (r *scorecardRepository) list(ctx context.Context, match func(model.Scorecard) bool) (*[]model.Scorecard, error) {
+	var result = []model.Scorecard{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, scorecard := range t.scorecards {
+			if match(scorecard) {
+				result = append(result, scorecard)
+			}
+		}
+
+		orderBy(result, "", nil, []compare[model.Scorecard]{func(a, b model.Scorecard) int { return compareTimes(a.CreatedAt, b.CreatedAt) }},
+			func(s model.Scorecard) string { return s.ID })
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetCandidateIDListByRecruitmentID returns every candidate that has at least
+// one scorecard in the recruitment.
+func (r *scorecardRepository) GetCandidateIDListByRecruitmentID(ctx context.Context, recruitmentID string) ([]string, error) {
+	var result []string
+
+	err := r.store.read(ctx, func(t *tables) error {
+		seen := map[string]bool{}
+		for _, scorecard := range t.scorecards {
+			if scorecard.RecruitmentID == recruitmentID && !seen[scorecard.CandidateID] {
+				seen[scorecard.CandidateID] = true
+				result = append(result, scorecard.CandidateID)
+			}
+		}
+
+		sort.Strings(result)
+
+		return nil
+	})
+
+	return result, err
+}
+
+func (r *scorecardRepository) CountScorecardByRecruitmentID(ctx context.Context, recruitmentID string) (int, error) {
+	return r.count(ctx, func(s model.Scorecard) bool { return s.RecruitmentID == recruitmentID })
+}
+
+func (r *scorecardRepository) CountScorecardByCandidateID(ctx context.Context, candidateID string) (int, error) {
+	return r.count(ctx, func(s model.Scorecard) bool { return s.CandidateID == candidateID })
+}
+
+func (r *scorecardRepository) count(ctx context.Context, match func(model.Scorecard) bool) (int, error) {
+	var total int
+
+	err := r.store.read(ctx, func(t *tables) error {
+		total = count(t.scorecards, match)
+		return nil
+	})
+
+	return total, err
+}
+
+func (r *scorecardRepository) PostScorecard(ctx context.Context, model *model.Scorecard) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row := *model
+		row.CreatedAt = timestamp(row.CreatedAt)
+
+		for _, err := range []error{
+			exists(t.recruitments, "recruitment", row.RecruitmentID),
+			exists(t.candidates, "candidate", row.CandidateID),
+			unique(t.scorecards, scorecardKey, "scorecard", row.ID, row),
+		} {
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := insert(t.scorecards, "scorecard", row.ID, row); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityScorecard, actionCreate, row.ID, nil, scorecardRow(row))
+	})
+}
+
+func (r *scorecardRepository) DeleteScorecard(ctx context.Context, id string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.scorecards, id)
+		if err != nil {
+			return err
+		}
+
+		delete(t.scorecards, id)
+
+		return t.recordAudit(ctx, entityScorecard, actionDelete, id, scorecardRow(before), nil)
+	})
+}
@@ -0,0 +1,118 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"talentapp/model"
+	"talentapp/repository"
+)
+
+type scoringProfileRepository struct {
+	store *Store
+}
+
+func NewScoringProfileRepository(store *Store) repository.ScoringProfileRepository {
+	return &scoringProfileRepository{store}
+}
+
+// scoringProfile returns a copy of p sharing no slice or map with it.
+func scoringProfile(p model.ScoringProfile) model.ScoringProfile {
+	result := p
+	result.CreatedAt = timestamp(p.CreatedAt)
+	result.ExperienceBands = append([]model.ExperienceBand{}, p.ExperienceBands...)
+	result.GradeValues = make(map[string]float64, len(p.GradeValues))
+	for grade, value := range p.GradeValues {
+		result.GradeValues[grade] = value
+	}
+
+	return result
+}
+
+func (r *scoringProfileRepository) GetScoringProfileByID(ctx context.Context, id string) (*model.ScoringProfile, error) {
+	var result model.ScoringProfile
+
+	err := r.store.read(ctx, func(t *tables) error {
+		profile, err := find(t.scoringProfiles, id)
+		result = scoringProfile(profile)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// profiles returns the profiles of a job, latest version first.
+func (t *tables) profiles(jobID string) []model.ScoringProfile {
+	var result []model.ScoringProfile
+	for _, profile := range t.scoringProfiles {
+		if profile.JobID == jobID {
+			result = append(result, scoringProfile(profile))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version > result[j].Version
+	})
+
+	return result
+}
+
+func (r *scoringProfileRepository) GetLatestScoringProfileByJobID(ctx context.Context, jobID string) (*model.ScoringProfile, error) {
+	var result model.ScoringProfile
+
+	err := r.store.read(ctx, func(t *tables) error {
+		profiles := t.profiles(jobID)
+		if len(profiles) == 0 {
+			return sql.ErrNoRows
+		}
+
+		result = profiles[0]
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *scoringProfileRepository) GetScoringProfileListByJobID(ctx context.Context, jobID string) (*[]model.ScoringProfile, error) {
+	var result = []model.ScoringProfile{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		result = append(result, t.profiles(jobID)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// PostScoringProfile stores model as the next version of its job profile.
+// Writes to a store run one after the other, so no other profile can take
+// the version in the meantime.
+func (r *scoringProfileRepository) PostScoringProfile(ctx context.Context, model *model.ScoringProfile) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		if err := exists(t.jobs, "job", model.JobID); err != nil {
+			return err
+		}
+
+		model.Version = 1
+		if profiles := t.profiles(model.JobID); len(profiles) > 0 {
+			model.Version = profiles[0].Version + 1
+		}
+
+		row := scoringProfile(*model)
+		if err := insert(t.scoringProfiles, "scoring_profile", row.ID, row); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityScoringProfile, actionCreate, row.ID, nil, scoringProfileRow(row))
+	})
+}
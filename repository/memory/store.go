@@ -0,0 +1,180 @@
+// Package memory keeps the records of the app in process memory. Its
+// repositories implement the interfaces of package repository with the same
+// behaviour as the SQL ones: the same ordering, the same unique and foreign
+// keys, reported as repository.ErrDuplicate and repository.ErrReferenced,
+// and the same audit log. Nothing outlives the process, so it serves demos
+// and tests that should not need a database server.
+package memory
+
+import (
+	"context"
+	"sync"
+	"talentapp/model"
+	"talentapp/repository"
+	"time"
+)
+
+// Store holds the tables shared by the repositories of one in-memory
+// database.
+type Store struct {
+	mu     sync.Mutex
+	tables *tables
+}
+
+func NewStore() *Store {
+	return &Store{tables: newTables()}
+}
+
+type lease struct {
+	holder    string
+	expiresAt time.Time
+}
+
+// tables are the rows of the store, keyed by id. Rows are stored without
+// their relations, and their slices and maps are never changed in place, so
+// a shallow copy of the maps is enough to roll a transaction back.
+type tables struct {
+	candidates             map[string]model.Candidate
+	skills                 map[string][]string
+	jobs                   map[string]model.Job
+	recruitments           map[string]model.Recruitment
+	scoringProfiles        map[string]model.ScoringProfile
+	candidateScores        map[string]model.CandidateScore
+	scorecards             map[string]model.Scorecard
+	applications           map[string]model.Application
+	applicationStageEvents map[string]model.ApplicationStageEvent
+	interviewerAssignments map[string]model.InterviewerAssignment
+	attachments            map[string]model.Attachment
+	consents               map[string]model.CandidateConsent
+	users                  map[string]model.User
+	userTokens             map[string]model.UserToken
+	auditLogs              []model.AuditLog
+	leases                 map[string]lease
+}
+
+func newTables() *tables {
+	return &tables{
+		candidates:             map[string]model.Candidate{},
+		skills:                 map[string][]string{},
+		jobs:                   map[string]model.Job{},
+		recruitments:           map[string]model.Recruitment{},
+		scoringProfiles:        map[string]model.ScoringProfile{},
+		candidateScores:        map[string]model.CandidateScore{},
+		scorecards:             map[string]model.Scorecard{},
+		applications:           map[string]model.Application{},
+		applicationStageEvents: map[string]model.ApplicationStageEvent{},
+		interviewerAssignments: map[string]model.InterviewerAssignment{},
+		attachments:            map[string]model.Attachment{},
+		consents:               map[string]model.CandidateConsent{},
+		users:                  map[string]model.User{},
+		userTokens:             map[string]model.UserToken{},
+		leases:                 map[string]lease{},
+	}
+}
+
+func (t *tables) clone() *tables {
+	return &tables{
+		candidates:             cloneMap(t.candidates),
+		skills:                 cloneMap(t.skills),
+		jobs:                   cloneMap(t.jobs),
+		recruitments:           cloneMap(t.recruitments),
+		scoringProfiles:        cloneMap(t.scoringProfiles),
+		candidateScores:        cloneMap(t.candidateScores),
+		scorecards:             cloneMap(t.scorecards),
+		applications:           cloneMap(t.applications),
+		applicationStageEvents: cloneMap(t.applicationStageEvents),
+		interviewerAssignments: cloneMap(t.interviewerAssignments),
+		attachments:            cloneMap(t.attachments),
+		consents:               cloneMap(t.consents),
+		users:                  cloneMap(t.users),
+		userTokens:             cloneMap(t.userTokens),
+		auditLogs:              append([]model.AuditLog{}, t.auditLogs...),
+		leases:                 cloneMap(t.leases),
+	}
+}
+
+func cloneMap[V any](m map[string]V) map[string]V {
+	result := make(map[string]V, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+
+	return result
+}
+
+type txKey struct{}
+
+// inTransaction tells whether ctx carries a transaction of s, whose caller
+// already holds the lock.
+func (s *Store) inTransaction(ctx context.Context) bool {
+	store, ok := ctx.Value(txKey{}).(*Store)
+	return ok && store == s
+}
+
+// read calls fn with the tables, under the lock unless ctx carries a
+// transaction of s.
+func (s *Store) read(ctx context.Context, fn func(t *tables) error) error {
+	if s.inTransaction(ctx) {
+		return fn(s.tables)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fn(s.tables)
+}
+
+// write calls fn with the tables in a transaction, so a write failing half
+// way leaves them as they were.
+func (s *Store) write(ctx context.Context, fn func(ctx context.Context, t *tables) error) error {
+	return s.withinTransaction(ctx, func(ctx context.Context) error {
+		return fn(ctx, s.tables)
+	})
+}
+
+// withinTransaction runs fn holding the lock, and puts the tables back as
+// they were when fn returns an error or panics, or when ctx is done. When
+// ctx already carries a transaction fn joins it.
+func (s *Store) withinTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if s.inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := s.tables.clone()
+	defer func() {
+		if p := recover(); p != nil {
+			s.tables = saved
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, s)); err != nil {
+		s.tables = saved
+		return err
+	}
+
+	if err = ctx.Err(); err != nil {
+		s.tables = saved
+		return err
+	}
+
+	return nil
+}
+
+type transactor struct {
+	store *Store
+}
+
+func NewTransactor(store *Store) repository.Transactor {
+	return &transactor{store}
+}
+
+// WithinTransaction runs fn as one unit: every change made with the ctx
+// passed to fn is undone when fn fails. Transactions of a store run one
+// after the other.
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.store.withinTransaction(ctx, fn)
+}
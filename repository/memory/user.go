@@ -0,0 +1,228 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"talentapp/model"
+	"talentapp/repository"
+	"time"
+)
+
+func userEmail(u model.User) string {
+	return u.Email
+}
+
+func tokenHash(t model.UserToken) string {
+	return t.TokenHash
+}
+
+type userRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) repository.UserRepository {
+	return &userRepository{store}
+}
+
+// userToken returns the stored columns of a token.
+func userToken(t model.UserToken) model.UserToken {
+	result := t
+	result.ExpiresAt = timestampPtr(t.ExpiresAt)
+	result.CreatedAt = timestamp(t.CreatedAt)
+	result.LastUsedAt = timestampPtr(t.LastUsedAt)
+
+	return result
+}
+
+func (r *userRepository) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	var result model.User
+
+	err := r.store.read(ctx, func(t *tables) (err error) {
+		result, err = find(t.users, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	var result model.User
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, user := range t.users {
+			if user.Email == email {
+				result = user
+				return nil
+			}
+		}
+
+		return sql.ErrNoRows
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *userRepository) GetUserList(ctx context.Context) (*[]model.User, error) {
+	var result = []model.User{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, user := range t.users {
+			result = append(result, user)
+		}
+
+		orderBy(result, "", nil, []compare[model.User]{func(a, b model.User) int { return compareStrings(a.Name, b.Name) }},
+			func(u model.User) string { return u.ID })
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *userRepository) CountUser(ctx context.Context) (int, error) {
+	var total int
+
+	err := r.store.read(ctx, func(t *tables) error {
+		total = len(t.users)
+		return nil
+	})
+
+	return total, err
+}
+
+func (r *userRepository) PostUser(ctx context.Context, model *model.User) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row := *model
+		row.CreatedAt = timestamp(row.CreatedAt)
+		if err := unique(t.users, userEmail, "app_user", row.ID, row); err != nil {
+			return err
+		}
+
+		if err := insert(t.users, "app_user", row.ID, row); err != nil {
+			return err
+		}
+
+		return t.recordAudit(ctx, entityUser, actionCreate, row.ID, nil, userRow(row))
+	})
+}
+
+func (r *userRepository) DeleteUser(ctx context.Context, id string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		before, err := find(t.users, id)
+		if err != nil {
+			return err
+		}
+
+		delete(t.users, id)
+		cascade(t.userTokens, func(token model.UserToken) string { return token.UserID }, id)
+		cascade(t.interviewerAssignments, func(a model.InterviewerAssignment) string { return a.UserID }, id)
+
+		return t.recordAudit(ctx, entityUser, actionDelete, id, userRow(before), nil)
+	})
+}
+
+// GetUserByTokenHash returns the owner of an unexpired token of the given
+// kind together with the token itself.
+func (r *userRepository) GetUserByTokenHash(ctx context.Context, kind, tokenHash string, now time.Time) (*model.User, *model.UserToken, error) {
+	var (
+		user  model.User
+		token model.UserToken
+	)
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, row := range t.userTokens {
+			if row.Kind != kind || row.TokenHash != tokenHash || row.ExpiresAt != nil && !row.ExpiresAt.After(now) {
+				continue
+			}
+
+			owner, ok := t.users[row.UserID]
+			if !ok {
+				continue
+			}
+
+			user, token = owner, userToken(row)
+
+			return nil
+		}
+
+		return sql.ErrNoRows
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &user, &token, nil
+}
+
+func (r *userRepository) GetUserTokenListByUserID(ctx context.Context, userID string) (*[]model.UserToken, error) {
+	var result = []model.UserToken{}
+
+	err := r.store.read(ctx, func(t *tables) error {
+		for _, token := range t.userTokens {
+			if token.UserID == userID && token.Kind == model.TokenAPI {
+				result = append(result, userToken(token))
+			}
+		}
+
+		orderBy(result, "", nil, []compare[model.UserToken]{func(a, b model.UserToken) int { return compareTimes(a.CreatedAt, b.CreatedAt) }},
+			func(token model.UserToken) string { return token.ID })
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *userRepository) PostUserToken(ctx context.Context, model *model.UserToken) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		row := userToken(*model)
+		row.LastUsedAt = nil
+
+		for _, err := range []error{
+			exists(t.users, "app_user", row.UserID),
+			unique(t.userTokens, tokenHash, "user_token", row.ID, row),
+		} {
+			if err != nil {
+				return err
+			}
+		}
+
+		return insert(t.userTokens, "user_token", row.ID, row)
+	})
+}
+
+func (r *userRepository) TouchUserToken(ctx context.Context, id string, now time.Time) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		if token, ok := t.userTokens[id]; ok {
+			token.LastUsedAt = timestampPtr(&now)
+			t.userTokens[id] = token
+		}
+
+		return nil
+	})
+}
+
+func (r *userRepository) DeleteUserToken(ctx context.Context, userID, id string) error {
+	return r.store.write(ctx, func(ctx context.Context, t *tables) error {
+		token, ok := t.userTokens[id]
+		if !ok || token.UserID != userID {
+			return sql.ErrNoRows
+		}
+
+		delete(t.userTokens, id)
+
+		return nil
+	})
+}
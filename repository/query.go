@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// scanner is implemented by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// orderBy translates a sort key such as "name" or "-name" into an ORDER BY
+// clause. Only keys present in columns are accepted, anything else falls
+// back to the given default clause. Rows that tie are ordered by the unique
+// id column, so pages neither repeat nor skip a row.
+func orderBy(sort string, columns map[string]string, fallback, id string) string {
+	direction := "ASC"
+	if strings.HasPrefix(sort, "-") {
+		direction = "DESC"
+		sort = strings.TrimPrefix(sort, "-")
+	}
+
+	column, ok := columns[sort]
+	if !ok {
+		return " ORDER BY " + fallback + ", " + id
+	}
+
+	return " ORDER BY " + column + " " + direction + ", " + id
+}
+
+// where joins the collected conditions into a WHERE clause.
+func where(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// affected turns an update or delete that touched no row into sql.ErrNoRows,
+// so callers can handle a missing record the same way as on reads.
+func affected(res sql.Result) error {
+	count, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// nullString stores an empty string as NULL, which optional foreign keys
+// require.
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// nullTime stores a nil time as NULL.
+func nullTime(value *time.Time) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: *value, Valid: true}
+}
@@ -5,83 +5,254 @@ import (
 	"database/sql"
 	"strings"
 	"talentapp/model"
+	"time"
 )
 
 type RecruitmentRepository interface {
-	GetRecruitments(ctx context.Context) (*[]model.Recruitment, error)
+	GetRecruitments(ctx context.Context, filter model.RecruitmentListRequest) (*[]model.Recruitment, int, error)
 	GetRecruitmentByID(ctx context.Context, id string) (*model.Recruitment, error)
 	CreateRecruitment(ctx context.Context, model *model.Recruitment) error
 	UpdateRecruitmentStatus(ctx context.Context, model *model.Recruitment) error
+	UpdateRecruitment(ctx context.Context, model *model.Recruitment) error
+	DeleteRecruitment(ctx context.Context, id string) error
+	CountRecruitmentByJobID(ctx context.Context, jobID string) (int, error)
+	CloseExpiredRecruitments(ctx context.Context, now time.Time, reason string) (int64, error)
+	LoadJobs(ctx context.Context, recruitments ...*model.Recruitment) error
+}
+
+var recruitmentSortColumns = map[string]string{
+	"deadline": "r.deadline",
+	"status":   "r.status",
+	"position": "j.position",
 }
 
 type recruitmentRepository struct {
-	DB *sql.DB
+	DB *DB
 }
 
-func NewRecruitmentRepository(db *sql.DB) RecruitmentRepository {
+func NewRecruitmentRepository(db *DB) RecruitmentRepository {
 	return &recruitmentRepository{
 		db,
 	}
 }
 
+// recruitmentColumns selects a recruitment r together with its job j, so
+// both are read in one query. The columns match recruitmentRow.dest.
+const recruitmentColumns = "r.id, r.job_id, r.status, r.deadline, r.score_aggregation, r.closed_at, r.closed_reason, " +
+	"j.id, j.position, j.department, j.requester, j.job_description, j.criteria, j.requirements"
+
+// recruitmentRow holds the scan targets of recruitmentColumns.
+type recruitmentRow struct {
+	recruitment  model.Recruitment
+	job          model.Job
+	closedAt     sql.NullTime
+	closedReason sql.NullString
+}
+
+func (row *recruitmentRow) dest() []interface{} {
+	return []interface{}{
+		&row.recruitment.ID, &row.recruitment.JobID, &row.recruitment.Status, &row.recruitment.Deadline, &row.recruitment.ScoreAggregation, &row.closedAt, &row.closedReason,
+		&row.job.ID, &row.job.Position, &row.job.Department, &row.job.Requester, &row.job.JobDescription, &row.job.Criteria, requirementsColumn{&row.job.Requirements},
+	}
+}
+
+func (row *recruitmentRow) value() *model.Recruitment {
+	result := row.recruitment
+	if row.closedAt.Valid {
+		closedAt := row.closedAt.Time
+		result.ClosedAt = &closedAt
+	}
+
+	job := row.job
+	result.Job = &job
+	result.ClosedReason = row.closedReason.String
+	result.DeadlineString = strings.Split(result.Deadline.String(), " ")[0]
+
+	return &result
+}
+
 func (r *recruitmentRepository) GetRecruitmentByID(ctx context.Context, id string) (*model.Recruitment, error) {
-	var result model.Recruitment
-	SQL := "SELECT id, job_id, status, deadline FROM recruitment WHERE id = ?"
-	row := r.DB.QueryRowContext(ctx, SQL, id)
+	SQL := "SELECT " + recruitmentColumns + " FROM recruitment r JOIN job j ON j.id = r.job_id WHERE r.id = ?"
+	row := conn(ctx, r.DB).QueryRowContext(ctx, SQL, id)
 
-	err := row.Scan(&result.ID, &result.JobID, &result.Status, &result.Deadline)
-	if err != nil {
+	return scanRecruitment(row)
+}
+
+func scanRecruitment(row scanner) (*model.Recruitment, error) {
+	var result recruitmentRow
+
+	if err := row.Scan(result.dest()...); err != nil {
 		return nil, err
 	}
 
-	result.DeadlineString = strings.Split(result.Deadline.String(), " ")[0]
+	return result.value(), nil
+}
 
-	return &result, nil
+// LoadJobs fills the Job of every recruitment.
+func (r *recruitmentRepository) LoadJobs(ctx context.Context, recruitments ...*model.Recruitment) error {
+	return loadJobs(ctx, conn(ctx, r.DB), recruitments)
 }
 
 func (r *recruitmentRepository) CreateRecruitment(ctx context.Context, model *model.Recruitment) error {
-	SQL := "insert into recruitment(id, job_id, status, deadline) values (?, ?, ?, ?)"
-	if _, err := r.DB.ExecContext(ctx, SQL, model.ID, model.JobID, model.Status, model.Deadline); err != nil {
-		return err
-	}
+	return audited(ctx, r.DB, recruitmentAudit.entry(actionCreate, model.ID), func(ctx context.Context) error {
+		SQL := "insert into recruitment(id, job_id, status, deadline, score_aggregation) values (?, ?, ?, ?, ?)"
+		if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.JobID, model.Status, model.Deadline, model.ScoreAggregation); err != nil {
+			return err
+		}
 
-	return nil
+		return nil
+	})
 }
 
-func (r *recruitmentRepository) GetRecruitments(ctx context.Context) (*[]model.Recruitment, error) {
-	var result []model.Recruitment
-	SQL := "SELECT id, job_id, status, deadline FROM recruitment LIMIT ? OFFSET ?"
-	rows, err := r.DB.QueryContext(ctx, SQL, 10, 0) // TODO: make dynamic pagination
+func (r *recruitmentRepository) GetRecruitments(ctx context.Context, filter model.RecruitmentListRequest) (*[]model.Recruitment, int, error) {
+	var (
+		result     = []model.Recruitment{}
+		total      int
+		conditions []string
+		args       []interface{}
+	)
+
+	if filter.Status != "" {
+		conditions = append(conditions, "r.status = ?")
+		args = append(args, filter.Status)
+	}
+
+	if filter.Department != "" {
+		conditions = append(conditions, "j.department = ?")
+		args = append(args, filter.Department)
+	}
+
+	if filter.DeadlineFrom != "" {
+		conditions = append(conditions, "r.deadline >= ?")
+		args = append(args, filter.DeadlineFrom+" 00:00:00")
+	}
+
+	if filter.DeadlineTo != "" {
+		conditions = append(conditions, "r.deadline <= ?")
+		args = append(args, filter.DeadlineTo+" 23:59:59")
+	}
+
+	SQL := "SELECT COUNT(*) FROM recruitment r JOIN job j ON j.id = r.job_id" + where(conditions)
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	SQL = "SELECT " + recruitmentColumns + " FROM recruitment r JOIN job j ON j.id = r.job_id" + where(conditions) +
+		orderBy(filter.Sort, recruitmentSortColumns, "r.deadline DESC", "r.id") + " LIMIT ? OFFSET ?"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, append(args, filter.Size, filter.Offset())...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		recruitment := model.Recruitment{}
-		err = rows.Scan(&recruitment.ID, &recruitment.JobID, &recruitment.Status, &recruitment.Deadline)
+		recruitment, err := scanRecruitment(rows)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
-		err = recruitment.LoadJob(ctx)
+		result = append(result, *recruitment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return &result, total, nil
+}
+
+func (r *recruitmentRepository) UpdateRecruitmentStatus(ctx context.Context, model *model.Recruitment) error {
+	return audited(ctx, r.DB, recruitmentAudit.entry(actionStatusChange, model.ID), func(ctx context.Context) error {
+		SQL := "update recruitment set status = ?, closed_at = ?, closed_reason = ? where id = ?"
+		if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.Status, nullTime(model.ClosedAt), nullString(model.ClosedReason), model.ID); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *recruitmentRepository) UpdateRecruitment(ctx context.Context, model *model.Recruitment) error {
+	return audited(ctx, r.DB, recruitmentAudit.entry(actionUpdate, model.ID), func(ctx context.Context) error {
+		SQL := "update recruitment set job_id = ?, status = ?, deadline = ?, score_aggregation = ?, closed_at = ?, closed_reason = ? where id = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.JobID, model.Status, model.Deadline, model.ScoreAggregation, nullTime(model.ClosedAt), nullString(model.ClosedReason), model.ID)
+		if err != nil {
+			return err
+		}
+
+		return affected(res)
+	})
+}
+
+func (r *recruitmentRepository) DeleteRecruitment(ctx context.Context, id string) error {
+	return audited(ctx, r.DB, recruitmentAudit.entry(actionDelete, id), func(ctx context.Context) error {
+		SQL := "delete from recruitment where id = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, id)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
-		recruitment.DeadlineString = strings.Split(recruitment.Deadline.String(), " ")[0]
+		return affected(res)
+	})
+}
 
-		result = append(result, recruitment)
+func (r *recruitmentRepository) CountRecruitmentByJobID(ctx context.Context, jobID string) (int, error) {
+	var total int
+	SQL := "SELECT COUNT(*) FROM recruitment WHERE job_id = ?"
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, jobID).Scan(&total); err != nil {
+		return 0, err
 	}
 
-	return &result, nil
+	return total, nil
 }
 
-func (r *recruitmentRepository) UpdateRecruitmentStatus(ctx context.Context, model *model.Recruitment) error {
-	SQL := "update recruitment set status = ? where id = ?"
-	if _, err := r.DB.ExecContext(ctx, SQL, model.Status, model.ID); err != nil {
-		return err
+// CloseExpiredRecruitments closes every open recruitment whose deadline is
+// before now and returns how many were closed. Each recruitment is closed
+// on its own so the audit log gets one entry per recruitment.
+func (r *recruitmentRepository) CloseExpiredRecruitments(ctx context.Context, now time.Time, reason string) (int64, error) {
+	var ids []string
+	SQL := "SELECT id FROM recruitment WHERE status = ? AND deadline < ?"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, model.RecruitmentOpen, now)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return 0, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	var closed int64
+	for _, id := range ids {
+		err = audited(ctx, r.DB, recruitmentAudit.entry(actionStatusChange, id), func(ctx context.Context) error {
+			SQL := "update recruitment set status = ?, closed_at = ?, closed_reason = ? where id = ? and status = ?"
+			res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.RecruitmentClose, now, reason, id, model.RecruitmentOpen)
+			if err != nil {
+				return err
+			}
+
+			return affected(res)
+		})
+		if err == sql.ErrNoRows {
+			// closed by someone else in the meantime
+			continue
+		}
+
+		if err != nil {
+			return closed, err
+		}
+
+		closed++
 	}
 
-	return nil
+	return closed, nil
 }
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"talentapp/model"
+)
+
+type ScorecardRepository interface {
+	GetScorecardByID(ctx context.Context, id string) (*model.Scorecard, error)
+	GetScorecardListByCandidate(ctx context.Context, recruitmentID, candidateID string) (*[]model.Scorecard, error)
+	GetScorecardListByCandidateID(ctx context.Context, candidateID string) (*[]model.Scorecard, error)
+	GetCandidateIDListByRecruitmentID(ctx context.Context, recruitmentID string) ([]string, error)
+	CountScorecardByRecruitmentID(ctx context.Context, recruitmentID string) (int, error)
+	CountScorecardByCandidateID(ctx context.Context, candidateID string) (int, error)
+	PostScorecard(ctx context.Context, model *model.Scorecard) error
+	DeleteScorecard(ctx context.Context, id string) error
+}
+
+type scorecardRepository struct {
+	DB *DB
+}
+
+func NewScorecardRepository(db *DB) ScorecardRepository {
+	return &scorecardRepository{DB: db}
+}
+
+func (r *scorecardRepository) GetScorecardByID(ctx context.Context, id string) (*model.Scorecard, error) {
+	var (
+		result  model.Scorecard
+		comment sql.NullString
+	)
+	SQL := "SELECT id, recruitment_id, candidate_id, interviewer, attitude_grade, skill_grade, attitude_score, skill_score, comment, created_at FROM scorecard WHERE id = ?"
+	row := conn(ctx, r.DB).QueryRowContext(ctx, SQL, id)
+
+	err := row.Scan(&result.ID, &result.RecruitmentID, &result.CandidateID, &result.Interviewer, &result.AttitudeGrade, &result.SkillGrade, &result.AttitudeScore, &result.SkillScore, &comment, &result.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Comment = comment.String
+
+	return &result, nil
+}
+
+func (r *scorecardRepository) GetScorecardListByCandidate(ctx context.Context, recruitmentID, candidateID string) (*[]model.Scorecard, error) {
+	return r.list(ctx, "recruitment_id = ? AND candidate_id = ?", recruitmentID, candidateID)
+}
+
+// GetScorecardListByCandidateID returns the scorecards of a candidate in
+// every recruitment.
+func (r *scorecardRepository) GetScorecardListByCandidateID(ctx context.Context, candidateID string) (*[]model.Scorecard, error) {
+	return r.list(ctx, "candidate_id = ?", candidateID)
+}
+
+func (r *scorecardRepository) list(ctx context.Context, condition string, args ...interface{}) (*[]model.Scorecard, error) {
+	var result = []model.Scorecard{}
+	SQL := "SELECT id, recruitment_id, candidate_id, interviewer, attitude_grade, skill_grade, attitude_score, skill_score, comment, created_at FROM scorecard WHERE " + condition + " ORDER BY created_at, id"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			scorecard = model.Scorecard{}
+			comment   sql.NullString
+		)
+
+		err = rows.Scan(&scorecard.ID, &scorecard.RecruitmentID, &scorecard.CandidateID, &scorecard.Interviewer, &scorecard.AttitudeGrade, &scorecard.SkillGrade, &scorecard.AttitudeScore, &scorecard.SkillScore, &comment, &scorecard.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		scorecard.Comment = comment.String
+		result = append(result, scorecard)
+	}
+
+	return &result, nil
+}
+
+// GetCandidateIDListByRecruitmentID returns every candidate that has at least
+// one scorecard in the recruitment.
+func (r *scorecardRepository) GetCandidateIDListByRecruitmentID(ctx context.Context, recruitmentID string) ([]string, error) {
+	var result []string
+	SQL := "SELECT DISTINCT candidate_id FROM scorecard WHERE recruitment_id = ?"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, recruitmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var candidateID string
+		if err = rows.Scan(&candidateID); err != nil {
+			return nil, err
+		}
+		result = append(result, candidateID)
+	}
+
+	return result, nil
+}
+
+func (r *scorecardRepository) CountScorecardByRecruitmentID(ctx context.Context, recruitmentID string) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM scorecard WHERE recruitment_id = ?", recruitmentID)
+}
+
+func (r *scorecardRepository) CountScorecardByCandidateID(ctx context.Context, candidateID string) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM scorecard WHERE candidate_id = ?", candidateID)
+}
+
+func (r *scorecardRepository) count(ctx context.Context, SQL string, args ...interface{}) (int, error) {
+	var total int
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *scorecardRepository) PostScorecard(ctx context.Context, model *model.Scorecard) error {
+	return audited(ctx, r.DB, scorecardAudit.entry(actionCreate, model.ID), func(ctx context.Context) error {
+		SQL := "insert into scorecard(id, recruitment_id, candidate_id, interviewer, attitude_grade, skill_grade, attitude_score, skill_score, comment, created_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.RecruitmentID, model.CandidateID, model.Interviewer, model.AttitudeGrade, model.SkillGrade, model.AttitudeScore, model.SkillScore, model.Comment, model.CreatedAt); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *scorecardRepository) DeleteScorecard(ctx context.Context, id string) error {
+	return audited(ctx, r.DB, scorecardAudit.entry(actionDelete, id), func(ctx context.Context) error {
+		SQL := "delete from scorecard where id = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, id)
+		if err != nil {
+			return err
+		}
+
+		return affected(res)
+	})
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"talentapp/model"
+)
+
+// postScoringProfileAttempts bounds the retries of a profile whose version
+// is taken by a profile of the same job posted at the same time.
+const postScoringProfileAttempts = 3
+
+type ScoringProfileRepository interface {
+	GetScoringProfileByID(ctx context.Context, id string) (*model.ScoringProfile, error)
+	GetLatestScoringProfileByJobID(ctx context.Context, jobID string) (*model.ScoringProfile, error)
+	GetScoringProfileListByJobID(ctx context.Context, jobID string) (*[]model.ScoringProfile, error)
+	PostScoringProfile(ctx context.Context, model *model.ScoringProfile) error
+}
+
+type scoringProfileRepository struct {
+	DB *DB
+}
+
+func NewScoringProfileRepository(db *DB) ScoringProfileRepository {
+	return &scoringProfileRepository{DB: db}
+}
+
+const scoringProfileColumns = "id, job_id, version, attitude_weight, relocation_weight, skill_weight, experience_weight, experience_bands, grade_values, created_at"
+
+func scanScoringProfile(row scanner) (*model.ScoringProfile, error) {
+	var (
+		result          model.ScoringProfile
+		experienceBands string
+		gradeValues     string
+	)
+
+	err := row.Scan(&result.ID, &result.JobID, &result.Version, &result.AttitudeWeight, &result.RelocationWeight, &result.SkillWeight, &result.ExperienceWeight, &experienceBands, &gradeValues, &result.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal([]byte(experienceBands), &result.ExperienceBands); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal([]byte(gradeValues), &result.GradeValues); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *scoringProfileRepository) GetScoringProfileByID(ctx context.Context, id string) (*model.ScoringProfile, error) {
+	SQL := "SELECT " + scoringProfileColumns + " FROM scoring_profile WHERE id = ?"
+	return scanScoringProfile(conn(ctx, r.DB).QueryRowContext(ctx, SQL, id))
+}
+
+func (r *scoringProfileRepository) GetLatestScoringProfileByJobID(ctx context.Context, jobID string) (*model.ScoringProfile, error) {
+	SQL := "SELECT " + scoringProfileColumns + " FROM scoring_profile WHERE job_id = ? ORDER BY version DESC LIMIT 1"
+	return scanScoringProfile(conn(ctx, r.DB).QueryRowContext(ctx, SQL, jobID))
+}
+
+func (r *scoringProfileRepository) GetScoringProfileListByJobID(ctx context.Context, jobID string) (*[]model.ScoringProfile, error) {
+	var result = []model.ScoringProfile{}
+	SQL := "SELECT " + scoringProfileColumns + " FROM scoring_profile WHERE job_id = ? ORDER BY version DESC"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		profile, err := scanScoringProfile(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *profile)
+	}
+
+	return &result, nil
+}
+
+// PostScoringProfile stores model as the next version of its job profile.
+// The version is read and taken in one transaction. When a profile posted at
+// the same time takes it first, the post is retried with the next version,
+// unless it runs in the transaction of a caller, which the failed insert
+// may have aborted.
+func (r *scoringProfileRepository) PostScoringProfile(ctx context.Context, model *model.ScoringProfile) error {
+	for attempt := 1; ; attempt++ {
+		err := r.postScoringProfile(ctx, model)
+		if !errors.Is(err, ErrDuplicate) || attempt == postScoringProfileAttempts || inTransaction(ctx) {
+			return err
+		}
+	}
+}
+
+func (r *scoringProfileRepository) postScoringProfile(ctx context.Context, model *model.ScoringProfile) error {
+	return audited(ctx, r.DB, scoringProfileAudit.entry(actionCreate, model.ID), func(ctx context.Context) error {
+		experienceBands, err := json.Marshal(model.ExperienceBands)
+		if err != nil {
+			return err
+		}
+
+		gradeValues, err := json.Marshal(model.GradeValues)
+		if err != nil {
+			return err
+		}
+
+		SQL := "SELECT COALESCE(MAX(version), 0) + 1 FROM scoring_profile WHERE job_id = ?"
+		if err = conn(ctx, r.DB).QueryRowContext(ctx, SQL, model.JobID).Scan(&model.Version); err != nil {
+			return err
+		}
+
+		SQL = "insert into scoring_profile(id, job_id, version, attitude_weight, relocation_weight, skill_weight, experience_weight, experience_bands, grade_values, created_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		if _, err = conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.JobID, model.Version, model.AttitudeWeight, model.RelocationWeight, model.SkillWeight, model.ExperienceWeight, string(experienceBands), string(gradeValues), model.CreatedAt); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"strings"
+	"talentapp/model"
+)
+
+// textSearch finds the rows of a table matching any word of a query: it
+// holds a condition selecting them and an expression ranking them, higher
+// being more relevant, each with its own arguments.
+type textSearch struct {
+	condition     string
+	args          []interface{}
+	relevance     string
+	relevanceArgs []interface{}
+}
+
+// noMatch is the search for a query without any word.
+var noMatch = textSearch{condition: "1 = 0", relevance: "0"}
+
+// textSearch searches terms in columns, read as a single document. Terms
+// shorter than model.MinPrefixLength are left out.
+//
+// MySQL and Postgres match words starting with a term through the full-text
+// indexes created by the migrations, whose columns must be given in the same
+// order. SQLite has no such index and falls back to LIKE, counting the
+// columns and terms matched as relevance.
+func (d Dialect) textSearch(terms []string, columns ...string) textSearch {
+	terms = model.PrefixTerms(terms)
+	if len(terms) == 0 {
+		return noMatch
+	}
+
+	switch d {
+	case MySQL:
+		match := "MATCH(" + strings.Join(columns, ", ") + ") AGAINST (? IN BOOLEAN MODE)"
+		query := strings.Join(terms, "* ") + "*"
+
+		return textSearch{
+			condition:     match,
+			args:          []interface{}{query},
+			relevance:     match,
+			relevanceArgs: []interface{}{query},
+		}
+	case Postgres:
+		parts := make([]string, len(columns))
+		for i, column := range columns {
+			parts[i] = "coalesce(" + column + ", '')"
+		}
+
+		document := "to_tsvector('simple', " + strings.Join(parts, " || ' ' || ") + ")"
+		query := strings.Join(terms, ":* | ") + ":*"
+
+		return textSearch{
+			condition:     document + " @@ to_tsquery('simple', ?)",
+			args:          []interface{}{query},
+			relevance:     "ts_rank(" + document + ", to_tsquery('simple', ?))",
+			relevanceArgs: []interface{}{query},
+		}
+	}
+
+	var (
+		matches []string
+		args    []interface{}
+	)
+
+	for _, column := range columns {
+		for _, term := range terms {
+			// the leading space lets the pattern match the first word too
+			matches = append(matches, "(' ' || LOWER(coalesce("+column+", '')) LIKE ?)")
+			args = append(args, "% "+term+"%")
+		}
+	}
+
+	return textSearch{
+		condition:     "(" + strings.Join(matches, " OR ") + ")",
+		args:          args,
+		relevance:     "(" + strings.Join(matches, " + ") + ")",
+		relevanceArgs: args,
+	}
+}
+
+// or widens s to the rows matching other as well, adding up both
+// relevances.
+func (s textSearch) or(other textSearch) textSearch {
+	return textSearch{
+		condition:     "(" + s.condition + " OR " + other.condition + ")",
+		args:          append(append([]interface{}{}, s.args...), other.args...),
+		relevance:     "(" + s.relevance + " + " + other.relevance + ")",
+		relevanceArgs: append(append([]interface{}{}, s.relevanceArgs...), other.relevanceArgs...),
+	}
+}
+
+// skillSearch finds the candidates having one of the skills named by
+// model.SearchSkills. Each skill found counts one towards relevance.
+func skillSearch(query string) textSearch {
+	names := model.SearchSkills(query)
+	if len(names) == 0 {
+		return noMatch
+	}
+
+	placeholders, args := in(names)
+
+	return textSearch{
+		condition:     "id IN (SELECT candidate_id FROM candidate_skills WHERE LOWER(skill) IN " + placeholders + ")",
+		args:          args,
+		relevance:     "(SELECT COUNT(*) FROM candidate_skills s WHERE s.candidate_id = candidate.id AND LOWER(s.skill) IN " + placeholders + ")",
+		relevanceArgs: args,
+	}
+}
+
+// searchTerms returns the words of a list query, or nil when the list is
+// not searched.
+func searchTerms(query string) ([]string, bool) {
+	if strings.TrimSpace(query) == "" {
+		return nil, false
+	}
+
+	return model.SearchTerms(query), true
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// querier is implemented by both *sql.DB and *sql.Tx.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// conn returns the transaction carried by ctx, or db when there is none, so
+// repositories take part in a transaction started by a caller. Queries are
+// rebound to the dialect of db, and constraint violations of writes are
+// reported as ErrDuplicate or ErrReferenced.
+func conn(ctx context.Context, db *DB) querier {
+	var q querier = db.DB
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		q = tx
+	}
+
+	return dialectQuerier{q, db.Dialect}
+}
+
+// Transactor runs a function inside a database transaction. It is the unit
+// of work of the usecases: the repository calls made with the ctx passed to
+// fn all commit together or not at all.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	DB *DB
+}
+
+func NewTransactor(db *DB) Transactor {
+	return &transactor{
+		db,
+	}
+}
+
+// WithinTransaction commits when fn succeeds and rolls back when fn returns
+// an error or panics, or when ctx is done before the commit. When ctx
+// already carries a transaction fn joins it, and the outermost call decides
+// about the commit.
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withinTransaction(ctx, t.DB, fn)
+}
+
+func withinTransaction(ctx context.Context, db *DB, fn func(ctx context.Context) error) (err error) {
+	if inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	// the driver rolls back on its own once ctx is done, report why
+	if err = ctx.Err(); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// inTransaction tells whether ctx carries a transaction started by a caller.
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return ok
+}
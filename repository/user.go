@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"talentapp/model"
+	"time"
+)
+
+type UserRepository interface {
+	GetUserByID(ctx context.Context, id string) (*model.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	GetUserList(ctx context.Context) (*[]model.User, error)
+	CountUser(ctx context.Context) (int, error)
+	PostUser(ctx context.Context, model *model.User) error
+	DeleteUser(ctx context.Context, id string) error
+	GetUserByTokenHash(ctx context.Context, kind, tokenHash string, now time.Time) (*model.User, *model.UserToken, error)
+	GetUserTokenListByUserID(ctx context.Context, userID string) (*[]model.UserToken, error)
+	PostUserToken(ctx context.Context, model *model.UserToken) error
+	TouchUserToken(ctx context.Context, id string, now time.Time) error
+	DeleteUserToken(ctx context.Context, userID, id string) error
+}
+
+type userRepository struct {
+	DB *DB
+}
+
+func NewUserRepository(db *DB) UserRepository {
+	return &userRepository{
+		db,
+	}
+}
+
+func (r *userRepository) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	var result model.User
+	SQL := "SELECT id, name, email, password_hash, role, created_at FROM app_user WHERE id = ?"
+	row := conn(ctx, r.DB).QueryRowContext(ctx, SQL, id)
+
+	err := row.Scan(&result.ID, &result.Name, &result.Email, &result.PasswordHash, &result.Role, &result.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	var result model.User
+	SQL := "SELECT id, name, email, password_hash, role, created_at FROM app_user WHERE email = ?"
+	row := conn(ctx, r.DB).QueryRowContext(ctx, SQL, email)
+
+	err := row.Scan(&result.ID, &result.Name, &result.Email, &result.PasswordHash, &result.Role, &result.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (r *userRepository) GetUserList(ctx context.Context) (*[]model.User, error) {
+	var result = []model.User{}
+	SQL := "SELECT id, name, email, password_hash, role, created_at FROM app_user ORDER BY name, id"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user := model.User{}
+		err = rows.Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, user)
+	}
+
+	return &result, nil
+}
+
+func (r *userRepository) CountUser(ctx context.Context) (int, error) {
+	var total int
+	SQL := "SELECT COUNT(*) FROM app_user"
+	if err := conn(ctx, r.DB).QueryRowContext(ctx, SQL).Scan(&total); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *userRepository) PostUser(ctx context.Context, model *model.User) error {
+	return audited(ctx, r.DB, userAudit.entry(actionCreate, model.ID), func(ctx context.Context) error {
+		SQL := "insert into app_user(id, name, email, password_hash, role, created_at) values (?, ?, ?, ?, ?, ?)"
+		if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.Name, model.Email, model.PasswordHash, model.Role, model.CreatedAt); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (r *userRepository) DeleteUser(ctx context.Context, id string) error {
+	return audited(ctx, r.DB, userAudit.entry(actionDelete, id), func(ctx context.Context) error {
+		SQL := "delete from app_user where id = ?"
+		res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, id)
+		if err != nil {
+			return err
+		}
+
+		return affected(res)
+	})
+}
+
+// GetUserByTokenHash returns the owner of an unexpired token of the given
+// kind together with the token itself.
+func (r *userRepository) GetUserByTokenHash(ctx context.Context, kind, tokenHash string, now time.Time) (*model.User, *model.UserToken, error) {
+	var (
+		user       model.User
+		token      model.UserToken
+		expiresAt  sql.NullTime
+		lastUsedAt sql.NullTime
+	)
+	SQL := "SELECT u.id, u.name, u.email, u.password_hash, u.role, u.created_at, t.id, t.user_id, t.kind, t.name, t.token_hash, t.expires_at, t.created_at, t.last_used_at " +
+		"FROM user_token t JOIN app_user u ON u.id = t.user_id WHERE t.kind = ? AND t.token_hash = ? AND (t.expires_at IS NULL OR t.expires_at > ?)"
+	row := conn(ctx, r.DB).QueryRowContext(ctx, SQL, kind, tokenHash, now)
+
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.Role, &user.CreatedAt,
+		&token.ID, &token.UserID, &token.Kind, &token.Name, &token.TokenHash, &expiresAt, &token.CreatedAt, &lastUsedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+
+	return &user, &token, nil
+}
+
+func (r *userRepository) GetUserTokenListByUserID(ctx context.Context, userID string) (*[]model.UserToken, error) {
+	var result = []model.UserToken{}
+	SQL := "SELECT id, user_id, kind, name, token_hash, expires_at, created_at, last_used_at FROM user_token WHERE user_id = ? AND kind = ? ORDER BY created_at, id"
+	rows, err := conn(ctx, r.DB).QueryContext(ctx, SQL, userID, model.TokenAPI)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			token      = model.UserToken{}
+			expiresAt  sql.NullTime
+			lastUsedAt sql.NullTime
+		)
+
+		err = rows.Scan(&token.ID, &token.UserID, &token.Kind, &token.Name, &token.TokenHash, &expiresAt, &token.CreatedAt, &lastUsedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if expiresAt.Valid {
+			token.ExpiresAt = &expiresAt.Time
+		}
+
+		if lastUsedAt.Valid {
+			token.LastUsedAt = &lastUsedAt.Time
+		}
+
+		result = append(result, token)
+	}
+
+	return &result, nil
+}
+
+func (r *userRepository) PostUserToken(ctx context.Context, model *model.UserToken) error {
+	SQL := "insert into user_token(id, user_id, kind, name, token_hash, expires_at, created_at) values (?, ?, ?, ?, ?, ?, ?)"
+	if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, model.ID, model.UserID, model.Kind, model.Name, model.TokenHash, nullTime(model.ExpiresAt), model.CreatedAt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) TouchUserToken(ctx context.Context, id string, now time.Time) error {
+	SQL := "update user_token set last_used_at = ? where id = ?"
+	if _, err := conn(ctx, r.DB).ExecContext(ctx, SQL, now, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) DeleteUserToken(ctx context.Context, userID, id string) error {
+	SQL := "delete from user_token where user_id = ? and id = ?"
+	res, err := conn(ctx, r.DB).ExecContext(ctx, SQL, userID, id)
+	if err != nil {
+		return err
+	}
+
+	return affected(res)
+}
@@ -0,0 +1,66 @@
+package resume
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// docxText reads the body of a Word document, ending a line after every
+// paragraph and line break.
+func docxText(data []byte) (string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", ErrUnreadable
+	}
+
+	file, err := archive.Open("word/document.xml")
+	if err != nil {
+		return "", ErrUnreadable
+	}
+	defer file.Close()
+
+	var (
+		decoder = xml.NewDecoder(file)
+		builder strings.Builder
+		inText  bool
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return "", ErrUnreadable
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			switch token.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				builder.WriteByte('\t')
+			case "br", "cr":
+				builder.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch token.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				builder.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				builder.Write(token)
+			}
+		}
+	}
+
+	return builder.String(), nil
+}
@@ -0,0 +1,161 @@
+package resume
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"talentapp/model"
+	"time"
+	"unicode"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`\+?\(?\d[\d ().-]{6,}\d`)
+
+	addressPattern = regexp.MustCompile(`(?im)^\s*(?:address|location)\s*[:\-]\s*(.+?)\s*$`)
+
+	// statedExperiencePattern matches "5 years of experience", "7+ years
+	// professional experience" and "experience: 3 years".
+	statedExperiencePattern = regexp.MustCompile(`(?i)(\d{1,2})\+?\s*(?:years?|yrs?)(?:\s+of)?(?:\s+[a-z/+#.-]+){0,3}?\s+experience` +
+		`|experience\s*(?:of|:)?\s*(\d{1,2})\+?\s*(?:years?|yrs?)`)
+
+	// periodPattern matches employment periods such as "2016 - 2019" or
+	// "Mar 2020 – present".
+	periodPattern = regexp.MustCompile(`(?i)\b((?:19|20)\d{2})\s*(?:-|–|—|to|until)\s*(?:[a-z]{3,9}\.?\s+)?((?:19|20)\d{2}|present|current|now|today)\b`)
+
+	// nameHeadings are titles that open a resume instead of the name.
+	nameHeadings = map[string]bool{"resume": true, "résumé": true, "curriculum vitae": true, "cv": true}
+)
+
+// Parse picks contact details, skills and years of experience out of the
+// text of a resume. Experience is taken from a stated number of years when
+// there is one, and otherwise summed up from the periods listed, counting
+// overlapping periods once. now is the date "present" refers to.
+func Parse(text string, now time.Time) *model.ParsedResume {
+	result := &model.ParsedResume{
+		Name:   parseName(text),
+		Email:  strings.ToLower(emailPattern.FindString(text)),
+		Phone:  parsePhone(text),
+		Skills: Skills(text),
+	}
+
+	if match := addressPattern.FindStringSubmatch(text); match != nil {
+		result.Address = match[1]
+	}
+
+	if experience, ok := parseExperience(text, now); ok {
+		result.Experience = &experience
+	}
+
+	return result
+}
+
+// parseName takes the first line near the top that reads like a name: two
+// to four words of letters only.
+func parseName(text string) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) > 5 {
+		lines = lines[:5]
+	}
+
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" || nameHeadings[strings.ToLower(line)] {
+			continue
+		}
+
+		words := strings.Fields(line)
+		if len(words) < 2 || len(words) > 4 || !isName(line) {
+			continue
+		}
+
+		if strings.ToUpper(line) == line {
+			for i, word := range words {
+				lower := []rune(strings.ToLower(word))
+				lower[0] = unicode.ToUpper(lower[0])
+				words[i] = string(lower)
+			}
+			line = strings.Join(words, " ")
+		}
+
+		return line
+	}
+
+	return ""
+}
+
+func isName(line string) bool {
+	for _, r := range line {
+		if !unicode.IsLetter(r) && r != ' ' && r != '.' && r != '-' && r != '\'' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parsePhone returns the first number with 9 to 15 digits, the length of
+// phone numbers with or without their country code.
+func parsePhone(text string) string {
+	for _, match := range phonePattern.FindAllString(text, -1) {
+		digits := 0
+		for _, r := range match {
+			if unicode.IsDigit(r) {
+				digits++
+			}
+		}
+
+		if digits >= 9 && digits <= 15 {
+			return strings.TrimSpace(match)
+		}
+	}
+
+	return ""
+}
+
+func parseExperience(text string, now time.Time) (int, bool) {
+	stated := -1
+	for _, match := range statedExperiencePattern.FindAllStringSubmatch(text, -1) {
+		years, _ := strconv.Atoi(match[1] + match[2])
+		if years > stated {
+			stated = years
+		}
+	}
+
+	if stated >= 0 {
+		return stated, true
+	}
+
+	var periods [][2]int
+	for _, match := range periodPattern.FindAllStringSubmatch(text, -1) {
+		start, _ := strconv.Atoi(match[1])
+		end, err := strconv.Atoi(match[2])
+		if err != nil {
+			end = now.Year()
+		}
+
+		if start <= end && end <= now.Year() {
+			periods = append(periods, [2]int{start, end})
+		}
+	}
+
+	if len(periods) == 0 {
+		return 0, false
+	}
+
+	return mergedYears(periods), true
+}
+
+// mergedYears sums the length of periods, counting the years covered by more
+// than one period once.
+func mergedYears(periods [][2]int) int {
+	covered := map[int]bool{}
+	for _, period := range periods {
+		for year := period[0]; year < period[1]; year++ {
+			covered[year] = true
+		}
+	}
+
+	return len(covered)
+}
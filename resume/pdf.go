@@ -0,0 +1,79 @@
+package resume
+
+import (
+	"bytes"
+	"github.com/ledongthuc/pdf"
+	"math"
+	"sort"
+	"strings"
+)
+
+// pdfText lays the glyphs of every page out again as lines, top to bottom
+// and left to right, because PDF files only store where each glyph is drawn.
+func pdfText(data []byte) (text string, err error) {
+	// the reader panics on some malformed files
+	defer func() {
+		if recover() != nil {
+			text, err = "", ErrUnreadable
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", ErrUnreadable
+	}
+
+	var builder strings.Builder
+	for i := 1; i <= reader.NumPage(); i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+
+		for _, line := range pdfLines(page.Content().Text) {
+			builder.WriteString(line)
+			builder.WriteByte('\n')
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// pdfLines groups glyphs drawn at the same height into lines. A space is put
+// between glyphs that are further apart than a third of the font size,
+// since many files position words instead of drawing spaces.
+func pdfLines(glyphs []pdf.Text) []string {
+	sort.SliceStable(glyphs, func(i, j int) bool {
+		yi, yj := math.Round(glyphs[i].Y), math.Round(glyphs[j].Y)
+		if yi != yj {
+			return yi > yj
+		}
+
+		return glyphs[i].X < glyphs[j].X
+	})
+
+	var (
+		lines   []string
+		builder strings.Builder
+	)
+
+	for i, glyph := range glyphs {
+		if i > 0 {
+			previous := glyphs[i-1]
+			if math.Round(previous.Y) != math.Round(glyph.Y) {
+				lines = append(lines, builder.String())
+				builder.Reset()
+			} else if previous.W > 0 && glyph.X-(previous.X+previous.W) > glyph.FontSize/3 {
+				builder.WriteByte(' ')
+			}
+		}
+
+		builder.WriteString(glyph.S)
+	}
+
+	if builder.Len() > 0 {
+		lines = append(lines, builder.String())
+	}
+
+	return lines
+}
@@ -0,0 +1,55 @@
+// Package resume reads the text of resumes and picks out the details a
+// recruiter would otherwise type into the candidate form. Everything runs
+// in process; no document is sent anywhere.
+package resume
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrUnsupported is returned for files that are not PDF, DOCX or plain
+	// text.
+	ErrUnsupported = errors.New("unsupported resume format, expected .pdf, .docx or .txt")
+
+	// ErrUnreadable is returned when a file has the right extension but its
+	// text cannot be read, such as a scanned PDF without a text layer.
+	ErrUnreadable = errors.New("resume text cannot be read")
+)
+
+// Text returns the plain text of a resume, picking the format from the file
+// name. Lines of the document are separated by newlines.
+func Text(filename string, data []byte) (string, error) {
+	var (
+		text string
+		err  error
+	)
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".pdf":
+		text, err = pdfText(data)
+	case ".docx":
+		text, err = docxText(data)
+	case ".txt":
+		if !utf8.Valid(data) {
+			return "", ErrUnreadable
+		}
+		text = strings.TrimPrefix(string(data), "\ufeff")
+	default:
+		return "", ErrUnsupported
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if strings.TrimSpace(text) == "" {
+		return "", ErrUnreadable
+	}
+
+	return text, nil
+}
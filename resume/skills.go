@@ -0,0 +1,125 @@
+package resume
+
+import (
+	"regexp"
+	"strings"
+)
+
+// skill is a skill recognised in resumes, under its name and the other
+// ways it is written. Names that are also common words, such as Go or
+// Excel, are only recognised as written when exact is set; aliases are
+// recognised in any case.
+type skill struct {
+	name    string
+	aliases []string
+	exact   bool
+}
+
+// knownSkills is the vocabulary skills are recognised from. Names are what
+// is stored for the candidate.
+var knownSkills = []skill{
+	{name: "Go", aliases: []string{"golang"}, exact: true},
+	{name: "Python"},
+	{name: "Java"},
+	{name: "JavaScript", aliases: []string{"ecmascript"}},
+	{name: "TypeScript"},
+	{name: "C++"},
+	{name: "C#"},
+	{name: "PHP"},
+	{name: "Ruby"},
+	{name: "Rust", exact: true},
+	{name: "Kotlin"},
+	{name: "Swift", exact: true},
+	{name: "Scala"},
+	{name: "SQL"},
+	{name: "MySQL"},
+	{name: "PostgreSQL", aliases: []string{"postgres"}},
+	{name: "SQLite"},
+	{name: "MongoDB"},
+	{name: "Redis"},
+	{name: "Elasticsearch"},
+	{name: "Kafka"},
+	{name: "RabbitMQ"},
+	{name: "Docker"},
+	{name: "Kubernetes", aliases: []string{"k8s"}},
+	{name: "Terraform"},
+	{name: "Ansible"},
+	{name: "AWS", aliases: []string{"amazon web services"}},
+	{name: "Azure"},
+	{name: "Google Cloud", aliases: []string{"gcp", "google cloud platform"}},
+	{name: "Linux"},
+	{name: "Git"},
+	{name: "CI/CD", aliases: []string{"continuous integration"}},
+	{name: "REST", aliases: []string{"restful"}},
+	{name: "GraphQL"},
+	{name: "gRPC"},
+	{name: "Microservices", aliases: []string{"microservice"}},
+	{name: "HTML"},
+	{name: "CSS"},
+	{name: "React", aliases: []string{"react.js", "reactjs"}},
+	{name: "Angular"},
+	{name: "Vue.js", aliases: []string{"vue", "vuejs"}},
+	{name: "Node.js", aliases: []string{"nodejs"}},
+	{name: "Django"},
+	{name: "Flask"},
+	{name: "Spring Boot", aliases: []string{"spring framework"}},
+	{name: ".NET", aliases: []string{"dotnet", "asp.net"}},
+	{name: "Laravel"},
+	{name: "Ruby on Rails", aliases: []string{"ror"}},
+	{name: "Machine Learning"},
+	{name: "Data Analysis", aliases: []string{"data analytics"}},
+	{name: "TensorFlow"},
+	{name: "PyTorch"},
+	{name: "Excel", aliases: []string{"microsoft excel"}, exact: true},
+	{name: "Tableau"},
+	{name: "Power BI", aliases: []string{"powerbi"}},
+	{name: "Figma"},
+	{name: "Photoshop"},
+	{name: "Agile"},
+	{name: "Scrum"},
+	{name: "Jira"},
+	{name: "Project Management"},
+	{name: "Accounting"},
+	{name: "Sales"},
+	{name: "Marketing"},
+	{name: "SEO"},
+	{name: "Customer Service"},
+	{name: "Negotiation"},
+	{name: "Leadership"},
+	{name: "Communication"},
+}
+
+// skillPatterns matches each of knownSkills by any of its spellings as a
+// whole word.
+var skillPatterns = compileSkills()
+
+func compileSkills() []*regexp.Regexp {
+	patterns := make([]*regexp.Regexp, len(knownSkills))
+	for i, skill := range knownSkills {
+		name := "(?i:" + regexp.QuoteMeta(skill.name) + ")"
+		if skill.exact {
+			name = regexp.QuoteMeta(skill.name)
+		}
+
+		spellings := []string{name}
+		for _, alias := range skill.aliases {
+			spellings = append(spellings, "(?i:"+regexp.QuoteMeta(alias)+")")
+		}
+
+		patterns[i] = regexp.MustCompile(`(?:^|[^\pL\pN+#.])(?:` + strings.Join(spellings, "|") + `)(?:$|[^\pL\pN+#])`)
+	}
+
+	return patterns
+}
+
+// Skills returns the names of the known skills text mentions.
+func Skills(text string) []string {
+	result := []string{}
+	for i, pattern := range skillPatterns {
+		if pattern.MatchString(text) {
+			result = append(result, knownSkills[i].name)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"talentapp/repository"
+	"time"
+)
+
+// leaseTimeout bounds how long releasing the leases may take on shutdown.
+const leaseTimeout = 5 * time.Second
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+}
+
+// Scheduler runs background jobs at a fixed interval. Each run first takes a
+// lease named after the job, so when several app instances share a database
+// only the instance holding the lease runs the job.
+type Scheduler struct {
+	leaseRepository repository.LeaseRepository
+	holder          string
+	jobs            []job
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+}
+
+func NewScheduler(leaseRepository repository.LeaseRepository, holder string) *Scheduler {
+	return &Scheduler{
+		leaseRepository: leaseRepository,
+		holder:          holder,
+	}
+}
+
+// Register adds a job. Jobs must be registered before Start.
+func (s *Scheduler) Register(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, job{name: name, interval: interval, run: run})
+}
+
+// Start runs every registered job once and then on each tick of its
+// interval, until Stop is called.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, j)
+	}
+}
+
+// Stop waits for the running jobs to finish and releases their leases.
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	s.wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaseTimeout)
+	defer cancel()
+
+	for _, j := range s.jobs {
+		if err := s.leaseRepository.ReleaseLease(ctx, j.name, s.holder); err != nil {
+			log.Printf("scheduler: release lease %s: %v", j.name, err)
+		}
+	}
+}
+
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx, j)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick runs the job when the lease can be taken. The lease outlives the
+// interval so the holder keeps it between ticks, and another instance only
+// takes over once the holder stopped renewing it.
+func (s *Scheduler) tick(ctx context.Context, j job) {
+	acquired, err := s.leaseRepository.AcquireLease(ctx, j.name, s.holder, 2*j.interval)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("scheduler: acquire lease %s: %v", j.name, err)
+		}
+		return
+	}
+
+	if !acquired {
+		return
+	}
+
+	if err = j.run(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("scheduler: run %s: %v", j.name, err)
+	}
+}
@@ -0,0 +1,24 @@
+// Package storage keeps uploaded files outside the database, on the local
+// filesystem or in an S3 compatible object store.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no object is stored under a key.
+var ErrNotFound = errors.New("object not found")
+
+// Storage stores objects under slash separated keys.
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing any object
+	// stored there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns the content of the object stored under key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key. Deleting a missing
+	// object is not an error.
+	Delete(ctx context.Context, key string) error
+}
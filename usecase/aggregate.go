@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+	"talentapp/model"
+)
+
+// aggregate reduces the scores given by several interviewers to a single
+// score with the method configured on the recruitment.
+func aggregate(method string, values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	switch method {
+	case model.AggregationMedian:
+		middle := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			return round((sorted[middle-1] + sorted[middle]) / 2)
+		}
+		return round(sorted[middle])
+	case model.AggregationTrimmedMean:
+		// drop the highest and the lowest score once there are enough
+		// interviewers for it to leave something behind
+		if len(sorted) >= 3 {
+			sorted = sorted[1 : len(sorted)-1]
+		}
+		return round(mean(sorted))
+	default:
+		return round(mean(sorted))
+	}
+}
+
+// spread is the distance between the highest and the lowest score.
+func spread(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	lowest, highest := values[0], values[0]
+	for _, value := range values {
+		lowest = math.Min(lowest, value)
+		highest = math.Max(highest, value)
+	}
+
+	return round(highest - lowest)
+}
+
+func mean(values []float64) float64 {
+	var total float64
+	for _, value := range values {
+		total += value
+	}
+
+	return total / float64(len(values))
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"talentapp/model"
+	"talentapp/repository"
+	"time"
+)
+
+type ApplicationUsecase interface {
+	CreateNewApplication(ctx context.Context, recruitmentID string, payload model.ApplicationCreateRequest) (*model.Application, error)
+	GetApplicationByID(ctx context.Context, id string) (*model.Application, error)
+	GetApplicationBoard(ctx context.Context, recruitmentID, assignedTo string) (*model.ApplicationBoard, error)
+	MoveApplication(ctx context.Context, id string, payload model.ApplicationMoveRequest) (*model.Application, error)
+	GetApplicationEvents(ctx context.Context, id string) (*[]model.ApplicationStageEvent, error)
+}
+
+type applicationUsecase struct {
+	applicationRepository           repository.ApplicationRepository
+	recruitmentRepository           repository.RecruitmentRepository
+	candidateRepository             repository.CandidateRepository
+	interviewerAssignmentRepository repository.InterviewerAssignmentRepository
+	transactor                      repository.Transactor
+}
+
+func NewApplicationUsecase(
+	applicationRepository repository.ApplicationRepository,
+	recruitmentRepository repository.RecruitmentRepository,
+	candidateRepository repository.CandidateRepository,
+	interviewerAssignmentRepository repository.InterviewerAssignmentRepository,
+	transactor repository.Transactor,
+) ApplicationUsecase {
+	return &applicationUsecase{
+		applicationRepository:           applicationRepository,
+		recruitmentRepository:           recruitmentRepository,
+		candidateRepository:             candidateRepository,
+		interviewerAssignmentRepository: interviewerAssignmentRepository,
+		transactor:                      transactor,
+	}
+}
+
+func (u *applicationUsecase) GetApplicationByID(ctx context.Context, id string) (*model.Application, error) {
+	result, err := u.applicationRepository.GetApplicationByID(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("application with id %s %w", id, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateNewApplication puts a candidate in the applied stage of a
+// recruitment and records that first stage event, both or neither.
+func (u *applicationUsecase) CreateNewApplication(ctx context.Context, recruitmentID string, payload model.ApplicationCreateRequest) (*model.Application, error) {
+	if _, err := u.recruitmentRepository.GetRecruitmentByID(ctx, recruitmentID); err == sql.ErrNoRows {
+		return nil, fmt.Errorf("recruitment with id %s %w", recruitmentID, ErrNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+
+	candidate, err := u.candidateRepository.GetCandidateByID(ctx, payload.CandidateID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: candidate with id %s does not exist", ErrValidation, payload.CandidateID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if candidate.Erased() {
+		return nil, fmt.Errorf("candidate with id %s: %w", payload.CandidateID, ErrErased)
+	}
+
+	total, err := u.applicationRepository.CountApplicationByCandidate(ctx, recruitmentID, payload.CandidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if total > 0 {
+		return nil, fmt.Errorf("candidate with id %s already applied to recruitment %s: %w", payload.CandidateID, recruitmentID, ErrAlreadyExists)
+	}
+
+	now := time.Now()
+	result := &model.Application{
+		ID:            uuid.NewString(),
+		RecruitmentID: recruitmentID,
+		CandidateID:   payload.CandidateID,
+		Candidate:     candidate,
+		Stage:         model.StageApplied,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.applicationRepository.PostApplication(ctx, result); err != nil {
+			return err
+		}
+
+		return u.applicationRepository.PostApplicationStageEvent(ctx, &model.ApplicationStageEvent{
+			ID:            uuid.NewString(),
+			ApplicationID: result.ID,
+			ToStage:       result.Stage,
+			ChangedBy:     payload.ChangedBy,
+			CreatedAt:     now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *applicationUsecase) GetApplicationBoard(ctx context.Context, recruitmentID, assignedTo string) (*model.ApplicationBoard, error) {
+	if _, err := u.recruitmentRepository.GetRecruitmentByID(ctx, recruitmentID); err == sql.ErrNoRows {
+		return nil, fmt.Errorf("recruitment with id %s %w", recruitmentID, ErrNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+
+	applications, err := u.applicationRepository.GetApplicationListByRecruitmentID(ctx, recruitmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	assigned, err := u.assignedCandidates(ctx, recruitmentID, assignedTo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ApplicationBoard{RecruitmentID: recruitmentID}
+	for _, stage := range model.Stages {
+		column := model.ApplicationStage{Stage: stage, Applications: []model.Application{}}
+		for _, application := range *applications {
+			if assigned != nil && !assigned[application.CandidateID] {
+				continue
+			}
+
+			if application.Stage == stage {
+				column.Applications = append(column.Applications, application)
+			}
+		}
+		result.Columns = append(result.Columns, column)
+	}
+
+	return result, nil
+}
+
+// assignedCandidates returns the candidates the user is assigned to in the
+// recruitment, or nil without a user, when every candidate is shown.
+func (u *applicationUsecase) assignedCandidates(ctx context.Context, recruitmentID, userID string) (map[string]bool, error) {
+	if userID == "" {
+		return nil, nil
+	}
+
+	assignments, err := u.interviewerAssignmentRepository.GetInterviewerAssignmentListByRecruitmentID(ctx, recruitmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]bool{}
+	for _, assignment := range *assignments {
+		if assignment.UserID == userID {
+			result[assignment.CandidateID] = true
+		}
+	}
+
+	return result, nil
+}
+
+// MoveApplication changes the stage of an application when the state
+// machine allows it and records who made the change.
+func (u *applicationUsecase) MoveApplication(ctx context.Context, id string, payload model.ApplicationMoveRequest) (*model.Application, error) {
+	result, err := u.GetApplicationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.CanMoveTo(payload.Stage) {
+		return nil, fmt.Errorf("application cannot move from %s to %s: %w", result.Stage, payload.Stage, ErrIllegalTransition)
+	}
+
+	var (
+		fromStage = result.Stage
+		now       = time.Now()
+	)
+
+	result.Stage = payload.Stage
+	result.UpdatedAt = now
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := u.applicationRepository.UpdateApplicationStage(ctx, result, fromStage)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("application was moved from %s by someone else: %w", fromStage, ErrConflict)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return u.applicationRepository.PostApplicationStageEvent(ctx, &model.ApplicationStageEvent{
+			ID:            uuid.NewString(),
+			ApplicationID: result.ID,
+			FromStage:     fromStage,
+			ToStage:       result.Stage,
+			ChangedBy:     payload.ChangedBy,
+			Note:          payload.Note,
+			CreatedAt:     now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *applicationUsecase) GetApplicationEvents(ctx context.Context, id string) (*[]model.ApplicationStageEvent, error) {
+	if _, err := u.GetApplicationByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	result, err := u.applicationRepository.GetApplicationStageEventListByApplicationID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
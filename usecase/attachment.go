@@ -0,0 +1,354 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"talentapp/model"
+	"talentapp/repository"
+	"talentapp/resume"
+	"talentapp/storage"
+	"time"
+)
+
+type AttachmentUsecase interface {
+	UploadAttachment(ctx context.Context, candidateID, filename string, content io.Reader, payload model.AttachmentCreateRequest) (*model.Attachment, error)
+	GetAttachments(ctx context.Context, candidateID string) (*[]model.Attachment, error)
+	GetAttachmentByID(ctx context.Context, candidateID, id string) (*model.Attachment, error)
+	OpenAttachment(ctx context.Context, candidateID, id string) (*model.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, candidateID, id string) error
+	ParseResume(ctx context.Context, filename string, content io.Reader) (*model.ParsedResume, error)
+	ParseAttachment(ctx context.Context, candidateID, id string) (*model.ParsedResume, error)
+}
+
+// attachmentType is an accepted kind of file. The content of an upload must
+// sniff as one of sniffed, so a renamed executable is not accepted as a pdf.
+type attachmentType struct {
+	contentType string
+	sniffed     []string
+}
+
+// attachmentTypes holds the accepted files by extension. Office documents
+// are zip archives and sniff as such.
+var attachmentTypes = map[string]attachmentType{
+	".pdf":  {"application/pdf", []string{"application/pdf"}},
+	".docx": {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", []string{"application/zip"}},
+	".odt":  {"application/vnd.oasis.opendocument.text", []string{"application/zip"}},
+	".txt":  {"text/plain", []string{"text/plain"}},
+	".png":  {"image/png", []string{"image/png"}},
+	".jpg":  {"image/jpeg", []string{"image/jpeg"}},
+	".jpeg": {"image/jpeg", []string{"image/jpeg"}},
+	".zip":  {"application/zip", []string{"application/zip"}},
+}
+
+type attachmentUsecase struct {
+	attachmentRepository  repository.AttachmentRepository
+	candidateRepository   repository.CandidateRepository
+	applicationRepository repository.ApplicationRepository
+	storage               storage.Storage
+	transactor            repository.Transactor
+}
+
+func NewAttachmentUsecase(
+	attachmentRepository repository.AttachmentRepository,
+	candidateRepository repository.CandidateRepository,
+	applicationRepository repository.ApplicationRepository,
+	storage storage.Storage,
+	transactor repository.Transactor,
+) AttachmentUsecase {
+	return &attachmentUsecase{
+		attachmentRepository:  attachmentRepository,
+		candidateRepository:   candidateRepository,
+		applicationRepository: applicationRepository,
+		storage:               storage,
+		transactor:            transactor,
+	}
+}
+
+// UploadAttachment stores content as a document of the candidate. Files are
+// stored by the hash of their content, so the same file uploaded for two
+// candidates is kept once, and uploading it twice for one candidate is
+// rejected. The skills found in a CV are added to the candidate.
+func (u *attachmentUsecase) UploadAttachment(ctx context.Context, candidateID, filename string, content io.Reader, payload model.AttachmentCreateRequest) (*model.Attachment, error) {
+	if candidate, err := u.candidateRepository.GetCandidateByID(ctx, candidateID); err == sql.ErrNoRows {
+		return nil, fmt.Errorf("candidate with id %s %w", candidateID, ErrNotFound)
+	} else if err != nil {
+		return nil, err
+	} else if candidate.Erased() {
+		return nil, fmt.Errorf("candidate with id %s: %w", candidateID, ErrErased)
+	}
+
+	if payload.ApplicationID != "" {
+		application, err := u.applicationRepository.GetApplicationByID(ctx, payload.ApplicationID)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		if err == sql.ErrNoRows || application.CandidateID != candidateID {
+			return nil, fmt.Errorf("%w: application with id %s does not belong to candidate %s", ErrValidation, payload.ApplicationID, candidateID)
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, model.MaxAttachmentSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrValidation)
+	}
+
+	if len(data) > model.MaxAttachmentSize {
+		return nil, fmt.Errorf("%w: file is larger than %d MB", ErrValidation, model.MaxAttachmentSize>>20)
+	}
+
+	contentType, err := detectAttachmentType(filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(data)
+	result := &model.Attachment{
+		ID:            uuid.NewString(),
+		CandidateID:   candidateID,
+		ApplicationID: payload.ApplicationID,
+		Kind:          payload.Kind,
+		Filename:      filepath.Base(filename),
+		ContentType:   contentType,
+		Size:          int64(len(data)),
+		SHA256:        hex.EncodeToString(hash[:]),
+		UploadedBy:    payload.UploadedBy,
+		CreatedAt:     time.Now(),
+	}
+	result.StorageKey = "sha256/" + result.SHA256
+
+	var stored bool
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		total, err := u.attachmentRepository.CountAttachmentByCandidateSHA256(ctx, candidateID, result.SHA256)
+		if err != nil {
+			return err
+		}
+
+		if total > 0 {
+			return fmt.Errorf("candidate with id %s already has a file with the same content: %w", candidateID, ErrAlreadyExists)
+		}
+
+		if total, err = u.attachmentRepository.CountAttachmentByStorageKey(ctx, result.StorageKey); err != nil {
+			return err
+		}
+
+		if total == 0 {
+			if err = u.storage.Put(ctx, result.StorageKey, bytes.NewReader(data), result.Size, result.ContentType); err != nil {
+				return err
+			}
+			stored = true
+		}
+
+		if err = u.attachmentRepository.PostAttachment(ctx, result); err != nil {
+			return err
+		}
+
+		if result.Kind != model.AttachmentKindCV {
+			return nil
+		}
+
+		return u.addResumeSkills(ctx, candidateID, filename, data)
+	})
+	if err != nil {
+		if stored {
+			// ctx may be the reason the upload failed, so the object is
+			// removed regardless of it
+			_ = u.storage.Delete(context.Background(), result.StorageKey)
+		}
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// addResumeSkills adds the skills found in a resume to the candidate. A
+// resume whose text cannot be read, such as a scan, adds nothing.
+func (u *attachmentUsecase) addResumeSkills(ctx context.Context, candidateID, filename string, data []byte) error {
+	text, err := resume.Text(filename, data)
+	if err != nil {
+		return nil
+	}
+
+	skills := resume.Skills(text)
+	if len(skills) == 0 {
+		return nil
+	}
+
+	candidate, err := u.candidateRepository.GetCandidateByID(ctx, candidateID)
+	if err != nil {
+		return err
+	}
+
+	if err = u.candidateRepository.LoadSkills(ctx, candidate); err != nil {
+		return err
+	}
+
+	merged := model.NormalizeSkills(append(candidate.Skills, skills...))
+	if len(merged) == len(candidate.Skills) {
+		return nil
+	}
+
+	candidate.Skills = merged
+
+	return u.candidateRepository.UpdateCandidate(ctx, candidate)
+}
+
+// detectAttachmentType returns the content type of an upload, checking that
+// its content matches the extension of filename.
+func detectAttachmentType(filename string, data []byte) (string, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	accepted, ok := attachmentTypes[ext]
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported file type %q, expected pdf, docx, odt, txt, png, jpg or zip", ErrValidation, ext)
+	}
+
+	sniffed, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return "", err
+	}
+
+	for _, contentType := range accepted.sniffed {
+		if sniffed == contentType {
+			return accepted.contentType, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: content of %s is %s, not %s", ErrValidation, filepath.Base(filename), sniffed, accepted.contentType)
+}
+
+func (u *attachmentUsecase) GetAttachments(ctx context.Context, candidateID string) (*[]model.Attachment, error) {
+	if _, err := u.candidateRepository.GetCandidateByID(ctx, candidateID); err == sql.ErrNoRows {
+		return nil, fmt.Errorf("candidate with id %s %w", candidateID, ErrNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return u.attachmentRepository.GetAttachmentListByCandidateID(ctx, candidateID)
+}
+
+// GetAttachmentByID returns an attachment of the candidate. Attachments of
+// other candidates are not found, so the candidate in the path is the one
+// access is checked for.
+func (u *attachmentUsecase) GetAttachmentByID(ctx context.Context, candidateID, id string) (*model.Attachment, error) {
+	result, err := u.attachmentRepository.GetAttachmentByID(ctx, id)
+	if err == sql.ErrNoRows || err == nil && result.CandidateID != candidateID {
+		return nil, fmt.Errorf("attachment with id %s %w", id, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// OpenAttachment returns an attachment with its content, which the caller
+// must close.
+func (u *attachmentUsecase) OpenAttachment(ctx context.Context, candidateID, id string) (*model.Attachment, io.ReadCloser, error) {
+	result, err := u.GetAttachmentByID(ctx, candidateID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := u.storage.Open(ctx, result.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, fmt.Errorf("content of attachment with id %s %w", id, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, content, nil
+}
+
+// DeleteAttachment removes an attachment. Its content is deleted once the
+// removal is committed, unless another attachment shares it.
+func (u *attachmentUsecase) DeleteAttachment(ctx context.Context, candidateID, id string) error {
+	var storageKey string
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		attachment, err := u.GetAttachmentByID(ctx, candidateID, id)
+		if err != nil {
+			return err
+		}
+
+		storageKey = attachment.StorageKey
+
+		return u.attachmentRepository.DeleteAttachment(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	total, err := u.attachmentRepository.CountAttachmentByStorageKey(ctx, storageKey)
+	if err != nil || total > 0 {
+		return err
+	}
+
+	if err = u.storage.Delete(ctx, storageKey); err != nil {
+		return fmt.Errorf("attachment with id %s was deleted but its file was not: %w", id, err)
+	}
+
+	return nil
+}
+
+// ParseResume reads the details of a resume that is not stored, to prefill
+// the form of a new candidate.
+func (u *attachmentUsecase) ParseResume(ctx context.Context, filename string, content io.Reader) (*model.ParsedResume, error) {
+	data, err := io.ReadAll(io.LimitReader(content, model.MaxAttachmentSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > model.MaxAttachmentSize {
+		return nil, fmt.Errorf("%w: file is larger than %d MB", ErrValidation, model.MaxAttachmentSize>>20)
+	}
+
+	return parseResume(filename, data)
+}
+
+// ParseAttachment reads the details of a stored resume of the candidate.
+func (u *attachmentUsecase) ParseAttachment(ctx context.Context, candidateID, id string) (*model.ParsedResume, error) {
+	attachment, content, err := u.OpenAttachment(ctx, candidateID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseResume(attachment.Filename, data)
+}
+
+func parseResume(filename string, data []byte) (*model.ParsedResume, error) {
+	text, err := resume.Text(filename, data)
+	if errors.Is(err, resume.ErrUnsupported) || errors.Is(err, resume.ErrUnreadable) {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resume.Parse(text, time.Now()), nil
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+	"talentapp/model"
+	"talentapp/repository"
+)
+
+type AuditUsecase interface {
+	GetAuditLogs(ctx context.Context, filter model.AuditLogListRequest) (*[]model.AuditLog, *model.PageMeta, error)
+}
+
+type auditUsecase struct {
+	auditRepository repository.AuditRepository
+}
+
+func NewAuditUsecase(auditRepository repository.AuditRepository) AuditUsecase {
+	return &auditUsecase{
+		auditRepository: auditRepository,
+	}
+}
+
+func (u *auditUsecase) GetAuditLogs(ctx context.Context, filter model.AuditLogListRequest) (*[]model.AuditLog, *model.PageMeta, error) {
+	filter.Normalize()
+
+	result, total, err := u.auditRepository.GetAuditLogList(ctx, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, model.NewPageMeta(filter.Pagination, total), nil
+}
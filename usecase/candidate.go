@@ -7,46 +7,113 @@ import (
 	"github.com/google/uuid"
 	"talentapp/model"
 	"talentapp/repository"
+	"time"
 )
 
 type CandidateUsecase interface {
 	CreateNewCandidate(ctx context.Context, payload model.CandidateCreateRequest) (*model.Candidate, error)
 	GetCandidateByID(ctx context.Context, id string) (*model.Candidate, error)
-	GetCandidates(ctx context.Context) (*[]model.Candidate, error)
+	GetCandidates(ctx context.Context, filter model.CandidateListRequest) (*[]model.Candidate, *model.PageMeta, error)
+	UpdateCandidate(ctx context.Context, id string, payload model.CandidateUpdateRequest) (*model.Candidate, error)
+	DeleteCandidate(ctx context.Context, id string) error
+	ImportCandidates(ctx context.Context, rows [][]string, payload model.CandidateImportRequest) (*model.CandidateImportResult, error)
+	GetCandidateHistory(ctx context.Context, id, assignedTo string) (*model.CandidateHistory, error)
+	GetDuplicates(ctx context.Context, id string) (*[]model.CandidateDuplicate, error)
+	MergeCandidates(ctx context.Context, id string, payload model.CandidateMergeRequest) (*model.Candidate, error)
+	GetConsents(ctx context.Context, candidateID string) (*[]model.CandidateConsent, error)
+	CreateConsent(ctx context.Context, candidateID string, payload model.CandidateConsentCreateRequest) (*model.CandidateConsent, error)
+	WithdrawConsent(ctx context.Context, candidateID, id string) (*model.CandidateConsent, error)
+	GetContact(ctx context.Context, candidateID string) (*model.CandidateContact, error)
 }
 
 type candidateUsecase struct {
-	candidateRepository repository.CandidateRepository
+	candidateRepository             repository.CandidateRepository
+	candidateScoreRepository        repository.CandidateScoreRepository
+	scorecardRepository             repository.ScorecardRepository
+	applicationRepository           repository.ApplicationRepository
+	scoringProfileRepository        repository.ScoringProfileRepository
+	attachmentRepository            repository.AttachmentRepository
+	candidateConsentRepository      repository.CandidateConsentRepository
+	interviewerAssignmentRepository repository.InterviewerAssignmentRepository
+	transactor                      repository.Transactor
 }
 
-func NewCandidateUsecase(candidateRepository repository.CandidateRepository) CandidateUsecase {
+func NewCandidateUsecase(
+	candidateRepository repository.CandidateRepository,
+	candidateScoreRepository repository.CandidateScoreRepository,
+	scorecardRepository repository.ScorecardRepository,
+	applicationRepository repository.ApplicationRepository,
+	scoringProfileRepository repository.ScoringProfileRepository,
+	attachmentRepository repository.AttachmentRepository,
+	candidateConsentRepository repository.CandidateConsentRepository,
+	interviewerAssignmentRepository repository.InterviewerAssignmentRepository,
+	transactor repository.Transactor,
+) CandidateUsecase {
 	return &candidateUsecase{
-		candidateRepository: candidateRepository,
+		candidateRepository:             candidateRepository,
+		candidateScoreRepository:        candidateScoreRepository,
+		scorecardRepository:             scorecardRepository,
+		applicationRepository:           applicationRepository,
+		scoringProfileRepository:        scoringProfileRepository,
+		attachmentRepository:            attachmentRepository,
+		candidateConsentRepository:      candidateConsentRepository,
+		interviewerAssignmentRepository: interviewerAssignmentRepository,
+		transactor:                      transactor,
 	}
 }
 
 func (u *candidateUsecase) GetCandidateByID(ctx context.Context, id string) (*model.Candidate, error) {
 	result, err := u.candidateRepository.GetCandidateByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("candidate with id %s not found", id)
+		return nil, fmt.Errorf("candidate with id %s %w", id, ErrNotFound)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err = u.candidateRepository.LoadSkills(ctx, result); err != nil {
+		return nil, err
+	}
+
+	if err = u.candidateConsentRepository.LoadConsents(ctx, result); err != nil {
+		return nil, err
+	}
+
+	result.ApplyConsents(time.Now())
+
 	return result, nil
 }
 
-func (u *candidateUsecase) GetCandidates(ctx context.Context) (*[]model.Candidate, error) {
-	result, err := u.candidateRepository.GetCandidateList(ctx)
+func (u *candidateUsecase) GetCandidates(ctx context.Context, filter model.CandidateListRequest) (*[]model.Candidate, *model.PageMeta, error) {
+	filter.Normalize()
+
+	result, total, err := u.candidateRepository.GetCandidateList(ctx, filter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return result, nil
+	candidates := candidatePointers(*result)
+	if err = u.candidateRepository.LoadSkills(ctx, candidates...); err != nil {
+		return nil, nil, err
+	}
+
+	if err = u.candidateConsentRepository.LoadConsents(ctx, candidates...); err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	for _, candidate := range candidates {
+		candidate.ApplyConsents(now)
+	}
+
+	highlightCandidates(filter.Query, *result)
+
+	return result, model.NewPageMeta(filter.Pagination, total), nil
 }
 
+// CreateNewCandidate stores a new candidate, refusing one that looks like a
+// stored candidate with a DuplicateError unless the payload allows it.
 func (u *candidateUsecase) CreateNewCandidate(ctx context.Context, payload model.CandidateCreateRequest) (*model.Candidate, error) {
 	var (
 		result = new(model.Candidate)
@@ -59,6 +126,24 @@ func (u *candidateUsecase) CreateNewCandidate(ctx context.Context, payload model
 		Address:           payload.Address,
 		Experience:        payload.Experience,
 		WillingToRelocate: payload.WillingToRelocate,
+		Email:             payload.Email,
+		Phone:             payload.Phone,
+		Skills:            model.NormalizeSkills(payload.Skills),
+	}
+
+	if err := normalizeContact(result); err != nil {
+		return nil, err
+	}
+
+	if !payload.AllowDuplicate {
+		duplicates, err := u.findDuplicates(ctx, *result)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(duplicates) > 0 {
+			return nil, &DuplicateError{Duplicates: duplicates}
+		}
 	}
 
 	err := u.candidateRepository.PostCandidate(ctx, result)
@@ -68,3 +153,87 @@ func (u *candidateUsecase) CreateNewCandidate(ctx context.Context, payload model
 
 	return result, nil
 }
+
+func (u *candidateUsecase) UpdateCandidate(ctx context.Context, id string, payload model.CandidateUpdateRequest) (*model.Candidate, error) {
+	result, err := u.GetCandidateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Erased() {
+		return nil, fmt.Errorf("candidate with id %s: %w", id, ErrErased)
+	}
+
+	if payload.Name != nil {
+		result.Name = *payload.Name
+	}
+
+	if payload.Address != nil {
+		result.Address = *payload.Address
+	}
+
+	if payload.Experience != nil {
+		result.Experience = *payload.Experience
+	}
+
+	if payload.WillingToRelocate != nil {
+		result.WillingToRelocate = *payload.WillingToRelocate
+	}
+
+	if payload.Email != nil {
+		result.Email = *payload.Email
+	}
+
+	if payload.Phone != nil {
+		result.Phone = *payload.Phone
+	}
+
+	if payload.Skills != nil {
+		result.Skills = model.NormalizeSkills(*payload.Skills)
+	}
+
+	if err = normalizeContact(result); err != nil {
+		return nil, err
+	}
+
+	err = u.candidateRepository.UpdateCandidate(ctx, result)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("candidate with id %s %w", id, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteCandidate removes a candidate that has not been scored yet. Score
+// history is kept, so a candidate with scores, scorecards or applications
+// must not be deleted, and neither may one with attachments until they are
+// deleted. Skills, consents and interviewer assignments go with the
+// candidate.
+func (u *candidateUsecase) DeleteCandidate(ctx context.Context, id string) error {
+	if _, err := u.GetCandidateByID(ctx, id); err != nil {
+		return err
+	}
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := ensureUnused(ctx, "candidate", id,
+			dependent{"score", u.candidateScoreRepository.CountCandidateScoreByCandidateID},
+			dependent{"scorecard", u.scorecardRepository.CountScorecardByCandidateID},
+			dependent{"application", u.applicationRepository.CountApplicationByCandidateID},
+			dependent{"attachment", u.attachmentRepository.CountAttachmentByCandidateID},
+		)
+		if err != nil {
+			return err
+		}
+
+		return u.candidateRepository.DeleteCandidate(ctx, id)
+	})
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("candidate with id %s %w", id, ErrNotFound)
+	}
+
+	return err
+}
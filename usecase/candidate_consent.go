@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"net/mail"
+	"talentapp/model"
+	"time"
+)
+
+// GetConsents returns the consents of a candidate with their status.
+func (u *candidateUsecase) GetConsents(ctx context.Context, candidateID string) (*[]model.CandidateConsent, error) {
+	candidate, err := u.GetCandidateByID(ctx, candidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := candidate.Consents
+	if result == nil {
+		result = []model.CandidateConsent{}
+	}
+
+	return &result, nil
+}
+
+// CreateConsent records the consent of a candidate to a purpose.
+func (u *candidateUsecase) CreateConsent(ctx context.Context, candidateID string, payload model.CandidateConsentCreateRequest) (*model.CandidateConsent, error) {
+	if candidate, err := u.GetCandidateByID(ctx, candidateID); err != nil {
+		return nil, err
+	} else if candidate.Erased() {
+		return nil, fmt.Errorf("candidate with id %s: %w", candidateID, ErrErased)
+	}
+
+	now := time.Now()
+	result := &model.CandidateConsent{
+		ID:          uuid.NewString(),
+		CandidateID: candidateID,
+		Purpose:     payload.Purpose,
+		Source:      payload.Source,
+		GrantedAt:   now,
+		RecordedBy:  payload.RecordedBy,
+		CreatedAt:   now,
+	}
+
+	if payload.GrantedAt != "" {
+		grantedAt, err := time.Parse("2006-01-02", payload.GrantedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: granted_at %q is not a YYYY-MM-DD date", ErrValidation, payload.GrantedAt)
+		}
+
+		result.GrantedAt = grantedAt
+	}
+
+	result.ExpiresAt = result.GrantedAt.AddDate(0, model.DefaultConsentMonths, 0)
+	if payload.ExpiresAt != "" {
+		expiresAt, err := parseDeadline(payload.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: expires_at %q is not a YYYY-MM-DD date", ErrValidation, payload.ExpiresAt)
+		}
+
+		result.ExpiresAt = expiresAt
+	}
+
+	if !result.ExpiresAt.After(result.GrantedAt) {
+		return nil, fmt.Errorf("%w: consent must expire after it is granted", ErrValidation)
+	}
+
+	if err := u.candidateConsentRepository.PostConsent(ctx, result); err != nil {
+		return nil, err
+	}
+
+	result.Status = result.StatusAt(now)
+
+	return result, nil
+}
+
+// WithdrawConsent ends a consent of a candidate from now on.
+func (u *candidateUsecase) WithdrawConsent(ctx context.Context, candidateID, id string) (*model.CandidateConsent, error) {
+	result, err := u.candidateConsentRepository.GetConsentByID(ctx, id)
+	if err == sql.ErrNoRows || (err == nil && result.CandidateID != candidateID) {
+		return nil, fmt.Errorf("consent with id %s %w", id, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.WithdrawnAt != nil {
+		return nil, fmt.Errorf("consent with id %s was withdrawn on %s: %w", id, result.WithdrawnAt.Format("2006-01-02"), ErrConflict)
+	}
+
+	now := time.Now()
+	if err = u.candidateConsentRepository.WithdrawConsent(ctx, id, now); err != nil {
+		return nil, err
+	}
+
+	result.WithdrawnAt = &now
+	result.Status = result.StatusAt(now)
+
+	return result, nil
+}
+
+// GetContact returns how to reach a candidate. It is the way to contact a
+// candidate: it refuses with ErrNoConsent unless the candidate has a valid
+// consent to be contacted, and with ErrValidation when there is no email or
+// phone to use.
+func (u *candidateUsecase) GetContact(ctx context.Context, candidateID string) (*model.CandidateContact, error) {
+	candidate, err := u.GetCandidateByID(ctx, candidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	consent := model.ValidConsent(candidate.Consents, model.ConsentPurposeContact, time.Now())
+	if consent == nil {
+		return nil, fmt.Errorf("candidate with id %s: %w", candidateID, ErrNoConsent)
+	}
+
+	if candidate.Email == "" && candidate.Phone == "" {
+		return nil, fmt.Errorf("%w: candidate with id %s has no email or phone", ErrValidation, candidateID)
+	}
+
+	return &model.CandidateContact{
+		CandidateID: candidate.ID,
+		Name:        candidate.Name,
+		Email:       candidate.Email,
+		Phone:       candidate.Phone,
+		Consent:     *consent,
+	}, nil
+}
+
+// normalizeContact normalizes the email and phone of a candidate, refusing
+// them when they are not valid.
+func normalizeContact(candidate *model.Candidate) error {
+	candidate.Email = model.NormalizeEmail(candidate.Email)
+	if candidate.Email != "" {
+		if address, err := mail.ParseAddress(candidate.Email); err != nil || address.Address != candidate.Email {
+			return fmt.Errorf("%w: email %q is not an email address", ErrValidation, candidate.Email)
+		}
+	}
+
+	phone, ok := model.NormalizePhone(candidate.Phone)
+	if !ok {
+		return fmt.Errorf("%w: phone %q should have %d to %d digits", ErrValidation, candidate.Phone, model.MinPhoneDigits, model.MaxPhoneDigits)
+	}
+
+	candidate.Phone = phone
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"talentapp/model"
+	"time"
+)
+
+// GetCandidateHistory lists every recruitment the candidate was scored in or
+// applied to, with the score breakdown, the rank and the outcome. With
+// assignedTo, only the recruitments the user is assigned to the candidate in
+// are listed.
+func (u *candidateUsecase) GetCandidateHistory(ctx context.Context, id, assignedTo string) (*model.CandidateHistory, error) {
+	candidate, err := u.GetCandidateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := u.candidateScoreRepository.GetCandidateScoreListByCandidateID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applications, err := u.applicationRepository.GetApplicationListByCandidateID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		result = &model.CandidateHistory{
+			Candidate:    candidate,
+			Recruitments: []model.CandidateHistoryEntry{},
+		}
+		entries = map[string]*model.CandidateHistoryEntry{}
+		order   []string
+	)
+
+	entry := func(recruitment *model.Recruitment) *model.CandidateHistoryEntry {
+		if _, ok := entries[recruitment.ID]; !ok {
+			entries[recruitment.ID] = &model.CandidateHistoryEntry{Recruitment: recruitment}
+			order = append(order, recruitment.ID)
+		}
+
+		return entries[recruitment.ID]
+	}
+
+	scored := make([]*model.CandidateScore, len(*scores))
+	for i := range *scores {
+		scored[i] = &(*scores)[i]
+		entry(scored[i].Recruitment).Score = scored[i]
+	}
+
+	for i := range *applications {
+		application := &(*applications)[i]
+		entry(application.Recruitment).Application = application
+	}
+
+	if err = setScoreBreakdown(ctx, u.scoringProfileRepository, scored...); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, recruitmentID := range order {
+		e := entries[recruitmentID]
+		if assignedTo != "" {
+			total, err := u.interviewerAssignmentRepository.CountInterviewerAssignment(ctx, recruitmentID, id, assignedTo)
+			if err != nil {
+				return nil, err
+			}
+
+			if total == 0 {
+				continue
+			}
+		}
+
+		e.Outcome = historyOutcome(e, now)
+
+		// the entry already carries the recruitment and the history the
+		// candidate
+		if e.Score != nil {
+			e.Score.Recruitment, e.Score.Candidate = nil, nil
+		}
+
+		if e.Application != nil {
+			e.Application.Recruitment = nil
+		}
+
+		result.Recruitments = append(result.Recruitments, *e)
+	}
+
+	sort.SliceStable(result.Recruitments, func(i, j int) bool {
+		return result.Recruitments[i].Recruitment.Deadline.After(result.Recruitments[j].Recruitment.Deadline)
+	})
+
+	return result, nil
+}
+
+// historyOutcome is the final stage of the application, or whether the
+// recruitment is still running.
+func historyOutcome(entry *model.CandidateHistoryEntry, now time.Time) string {
+	if entry.Application != nil && model.IsFinalStage(entry.Application.Stage) {
+		return entry.Application.Stage
+	}
+
+	if entry.Recruitment.IsClosed(now) {
+		return model.OutcomeNotHired
+	}
+
+	return model.OutcomeInProgress
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"talentapp/model"
+	"talentapp/utils"
+)
+
+// candidateImportColumns maps the accepted header names of an import file to
+// the CandidateCreateRequest field they fill.
+var candidateImportColumns = map[string]string{
+	"name":                "Name",
+	"address":             "Address",
+	"experience":          "Experience",
+	"experience_years":    "Experience",
+	"years_of_experience": "Experience",
+	"willing_to_relocate": "WillingToRelocate",
+	"relocate":            "WillingToRelocate",
+	"email":               "Email",
+	"e_mail":              "Email",
+	"phone":               "Phone",
+	"phone_number":        "Phone",
+	"mobile":              "Phone",
+}
+
+var candidateImportRequired = []string{"Name", "Address", "Experience", "WillingToRelocate"}
+
+// ImportCandidates validates every data row of rows, whose first row is the
+// header, and inserts the valid rows in a single transaction. Unless allowed,
+// rows looking like a stored candidate or an earlier row are invalid. In
+// ImportModeAll nothing is inserted when a row is invalid; with DryRun nothing
+// is inserted at all and the result is only a preview.
+func (u *candidateUsecase) ImportCandidates(ctx context.Context, rows [][]string, payload model.CandidateImportRequest) (*model.CandidateImportResult, error) {
+	payload.Normalize()
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("file is empty: %w", ErrInvalidImport)
+	}
+
+	columns, err := candidateImportHeader(rows[0])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.CandidateImportResult{
+		Mode:   payload.Mode,
+		DryRun: payload.DryRun,
+		Rows:   []model.CandidateImportRow{},
+	}
+
+	for i, record := range rows[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+
+		result.Rows = append(result.Rows, parseCandidateImportRow(i+2, columns, record))
+	}
+
+	if !payload.AllowDuplicates {
+		if err = u.flagDuplicates(ctx, result.Rows); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, row := range result.Rows {
+		result.Total++
+		if row.Valid() {
+			result.Valid++
+		} else {
+			result.Invalid++
+		}
+	}
+
+	if payload.DryRun || result.Valid == 0 || (payload.Mode == model.ImportModeAll && result.Invalid > 0) {
+		clearImportContacts(result.Rows)
+		return result, nil
+	}
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i := range result.Rows {
+			if !result.Rows[i].Valid() {
+				continue
+			}
+
+			// duplicates were flagged above, against the pool and the file
+			candidate := result.Rows[i].Candidate
+			candidate.AllowDuplicate = true
+			if _, err := u.CreateNewCandidate(ctx, candidate); err != nil {
+				return fmt.Errorf("line %d: %w", result.Rows[i].Line, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range result.Rows {
+		if result.Rows[i].Valid() {
+			result.Rows[i].Imported = true
+			result.Imported++
+		}
+	}
+
+	clearImportContacts(result.Rows)
+
+	return result, nil
+}
+
+// clearImportContacts removes the email and phone from the rows of a report,
+// as contact details are only handed out with the consent of the candidate.
+func clearImportContacts(rows []model.CandidateImportRow) {
+	for i := range rows {
+		rows[i].Candidate.Email, rows[i].Candidate.Phone = "", ""
+	}
+}
+
+// candidateImportHeader returns the field filled by each column of header,
+// or an empty string for columns that are ignored.
+func candidateImportHeader(header []string) ([]string, error) {
+	var (
+		columns = make([]string, len(header))
+		found   = map[string]bool{}
+	)
+
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		name = strings.NewReplacer(" ", "_", "-", "_").Replace(name)
+
+		field := candidateImportColumns[name]
+		if field == "" || found[field] {
+			continue
+		}
+
+		columns[i] = field
+		found[field] = true
+	}
+
+	var missing []string
+	for _, field := range candidateImportRequired {
+		if !found[field] {
+			missing = append(missing, field)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing column(s) %s: %w", strings.Join(missing, ", "), ErrInvalidImport)
+	}
+
+	return columns, nil
+}
+
+func parseCandidateImportRow(line int, columns []string, record []string) model.CandidateImportRow {
+	var (
+		row       = model.CandidateImportRow{Line: line}
+		badFields = map[string]bool{}
+	)
+
+	for i, value := range record {
+		if i >= len(columns) {
+			break
+		}
+
+		value = strings.TrimSpace(value)
+		switch columns[i] {
+		case "Name":
+			row.Candidate.Name = value
+		case "Address":
+			row.Candidate.Address = value
+		case "Experience":
+			if value == "" {
+				continue
+			}
+
+			experience, err := strconv.Atoi(value)
+			if err != nil {
+				row.Errors = append(row.Errors, model.CandidateImportError{Field: "Experience", Message: "Should be number"})
+				badFields["Experience"] = true
+				continue
+			}
+
+			row.Candidate.Experience = experience
+		case "WillingToRelocate":
+			row.Candidate.WillingToRelocate = strings.ToLower(value)
+		case "Email":
+			row.Candidate.Email = model.NormalizeEmail(value)
+		case "Phone":
+			phone, ok := model.NormalizePhone(value)
+			if !ok {
+				row.Errors = append(row.Errors, model.CandidateImportError{
+					Field:   "Phone",
+					Message: fmt.Sprintf("Should have %d to %d digits", model.MinPhoneDigits, model.MaxPhoneDigits),
+				})
+				badFields["Phone"] = true
+				continue
+			}
+
+			row.Candidate.Phone = phone
+		}
+	}
+
+	if ok, err := utils.IsRequestValid(row.Candidate); !ok {
+		for _, e := range utils.CustomValidator(err) {
+			if badFields[e.Field] {
+				continue
+			}
+
+			row.Errors = append(row.Errors, model.CandidateImportError{Field: e.Field, Message: e.Message})
+		}
+	}
+
+	return row
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	return true
+}
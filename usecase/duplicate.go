@@ -0,0 +1,419 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"golang.org/x/text/unicode/norm"
+	"sort"
+	"strings"
+	"talentapp/model"
+	"talentapp/repository"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// duplicateNameThreshold is the name similarity two candidates without
+	// a shared email or phone need before their addresses are compared.
+	duplicateNameThreshold = 0.85
+	// duplicateThreshold is the similarity from which a candidate is taken
+	// for a duplicate.
+	duplicateThreshold = 0.8
+	// similarAddressThreshold is the address similarity reported as a
+	// similar address.
+	similarAddressThreshold = 0.5
+	// sameContactSimilarity is the least similarity of candidates sharing
+	// an email or a phone, which are duplicates whatever their names.
+	sameContactSimilarity = 0.95
+)
+
+// duplicateWeights weigh the similarity of the names and addresses of
+// candidates, adding up to 1. The name alone is not enough to flag a
+// duplicate, as namesakes are common.
+var duplicateWeights = struct{ name, address float64 }{name: 0.6, address: 0.4}
+
+// GetDuplicates returns the stored candidates that look like the candidate
+// with id, most similar first.
+func (u *candidateUsecase) GetDuplicates(ctx context.Context, id string) (*[]model.CandidateDuplicate, error) {
+	candidate, err := u.GetCandidateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := u.findDuplicates(ctx, *candidate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// MergeCandidates merges a duplicate into the candidate with id: the scores,
+// scorecards, applications, interviewer assignments, attachments and
+// consents of the duplicate move over, its skills are added, its email and
+// phone fill the ones the candidate lacks and it is deleted. The other
+// fields of the candidate are kept. Candidates taking part in the same
+// recruitment are not merged, as the recruitment would then hold two
+// records of the same person.
+func (u *candidateUsecase) MergeCandidates(ctx context.Context, id string, payload model.CandidateMergeRequest) (*model.Candidate, error) {
+	if payload.DuplicateID == id {
+		return nil, fmt.Errorf("%w: a candidate cannot be merged into itself", ErrValidation)
+	}
+
+	var result *model.Candidate
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if result, err = u.GetCandidateByID(ctx, id); err != nil {
+			return err
+		}
+
+		duplicate, err := u.candidateRepository.GetCandidateByID(ctx, payload.DuplicateID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: candidate with id %s does not exist", ErrValidation, payload.DuplicateID)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err = u.candidateRepository.LoadSkills(ctx, duplicate); err != nil {
+			return err
+		}
+
+		for _, candidate := range []*model.Candidate{result, duplicate} {
+			if candidate.Erased() {
+				return fmt.Errorf("candidate with id %s: %w", candidate.ID, ErrErased)
+			}
+		}
+
+		shared, err := u.candidateRepository.GetSharedRecruitmentIDs(ctx, id, duplicate.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(shared) > 0 {
+			return fmt.Errorf("candidates with id %s and %s both take part in recruitment(s) %s: %w",
+				id, duplicate.ID, strings.Join(shared, ", "), ErrConflict)
+		}
+
+		if err = u.candidateRepository.MergeCandidate(ctx, id, duplicate.ID); err != nil {
+			return err
+		}
+
+		skills := model.NormalizeSkills(append(append([]string{}, result.Skills...), duplicate.Skills...))
+		changed := len(skills) != len(result.Skills)
+		result.Skills = skills
+
+		if result.Email == "" && duplicate.Email != "" {
+			result.Email, changed = duplicate.Email, true
+		}
+
+		if result.Phone == "" && duplicate.Phone != "" {
+			result.Phone, changed = duplicate.Phone, true
+		}
+
+		if !changed {
+			return nil
+		}
+
+		return u.candidateRepository.UpdateCandidate(ctx, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// findDuplicates compares candidate with the whole pool.
+func (u *candidateUsecase) findDuplicates(ctx context.Context, candidate model.Candidate) ([]model.CandidateDuplicate, error) {
+	result := []model.CandidateDuplicate{}
+
+	err := forEachCandidate(ctx, u.candidateRepository, func(other model.Candidate) {
+		if other.ID == candidate.ID {
+			return
+		}
+
+		if duplicate, ok := compareCandidates(candidate, other); ok {
+			result = append(result, duplicate)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the pool is read by name, which orders equal similarities
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Similarity > result[j].Similarity
+	})
+
+	return result, nil
+}
+
+// flagDuplicates reports the valid rows of an import looking like a stored
+// candidate or like an earlier row of the file as invalid.
+func (u *candidateUsecase) flagDuplicates(ctx context.Context, rows []model.CandidateImportRow) error {
+	var (
+		best       = make([]*model.CandidateDuplicate, len(rows))
+		candidates = make([]model.Candidate, len(rows))
+	)
+
+	for i, row := range rows {
+		candidates[i] = model.Candidate{Name: row.Candidate.Name, Address: row.Candidate.Address, Email: row.Candidate.Email, Phone: row.Candidate.Phone}
+	}
+
+	err := forEachCandidate(ctx, u.candidateRepository, func(other model.Candidate) {
+		for i := range rows {
+			if !rows[i].Valid() {
+				continue
+			}
+
+			duplicate, ok := compareCandidates(candidates[i], other)
+			if ok && (best[i] == nil || duplicate.Similarity > best[i].Similarity) {
+				best[i] = &duplicate
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range rows {
+		if !rows[i].Valid() {
+			continue
+		}
+
+		if best[i] != nil {
+			rows[i].Errors = append(rows[i].Errors, model.CandidateImportError{
+				Field:   "Duplicate",
+				Message: fmt.Sprintf("Looks like candidate %s (%s)", best[i].Candidate.Name, best[i].Candidate.ID),
+			})
+			continue
+		}
+
+		for j := 0; j < i; j++ {
+			if _, ok := compareCandidates(candidates[i], candidates[j]); ok && rows[j].Valid() {
+				rows[i].Errors = append(rows[i].Errors, model.CandidateImportError{
+					Field:   "Duplicate",
+					Message: fmt.Sprintf("Looks like line %d", rows[j].Line),
+				})
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// forEachCandidate calls fn with every candidate of the pool and their
+// skills, reading the pool a page at a time in name order. Erased candidates
+// are left out of the pool.
+func forEachCandidate(ctx context.Context, candidateRepository repository.CandidateRepository, fn func(candidate model.Candidate)) error {
+	var (
+		pool = model.CandidateListRequest{Pagination: model.Pagination{Page: 1, Size: model.MaxPageSize}}
+		read int
+	)
+
+	for {
+		candidates, total, err := candidateRepository.GetCandidateList(ctx, pool)
+		if err != nil {
+			return err
+		}
+
+		if err = candidateRepository.LoadSkills(ctx, candidatePointers(*candidates)...); err != nil {
+			return err
+		}
+
+		for _, candidate := range *candidates {
+			if !candidate.Erased() {
+				fn(candidate)
+			}
+		}
+
+		read += len(*candidates)
+		if len(*candidates) == 0 || read >= total {
+			return nil
+		}
+
+		pool.Page++
+	}
+}
+
+// compareCandidates tells how much other looks like candidate, and whether
+// it is similar enough to be taken for a duplicate. Candidates sharing an
+// email or a phone are duplicates, others need a similar name and address.
+func compareCandidates(candidate, other model.Candidate) (model.CandidateDuplicate, bool) {
+	var (
+		result    = model.CandidateDuplicate{Candidate: other, Reasons: []string{}}
+		sameEmail = candidate.Email != "" && model.NormalizeEmail(candidate.Email) == model.NormalizeEmail(other.Email)
+		samePhone = candidate.Phone != "" && phoneDigits(candidate.Phone) == phoneDigits(other.Phone)
+	)
+
+	name := textSimilarity(normalizeName(candidate.Name), normalizeName(other.Name))
+	if name < duplicateNameThreshold && !sameEmail && !samePhone {
+		return result, false
+	}
+
+	address := addressSimilarity(candidate.Address, other.Address)
+	result.Similarity = round(duplicateWeights.name*name + duplicateWeights.address*address)
+
+	if sameEmail {
+		result.Reasons = append(result.Reasons, model.DuplicateReasonSameEmail)
+	}
+
+	if samePhone {
+		result.Reasons = append(result.Reasons, model.DuplicateReasonSamePhone)
+	}
+
+	if (sameEmail || samePhone) && result.Similarity < sameContactSimilarity {
+		result.Similarity = sameContactSimilarity
+	}
+
+	if name == 1 {
+		result.Reasons = append(result.Reasons, model.DuplicateReasonSameName)
+	} else if name >= duplicateNameThreshold {
+		result.Reasons = append(result.Reasons, model.DuplicateReasonSimilarName)
+	}
+
+	if address == 1 {
+		result.Reasons = append(result.Reasons, model.DuplicateReasonSameAddress)
+	} else if address >= similarAddressThreshold {
+		result.Reasons = append(result.Reasons, model.DuplicateReasonSimilarAddress)
+	}
+
+	return result, result.Similarity >= duplicateThreshold
+}
+
+// phoneDigits returns the digits of a phone number, so numbers written
+// differently compare equal.
+func phoneDigits(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+// normalizeName returns the words of a name in alphabetical order, so
+// "Smith, John" reads like "John Smith".
+func normalizeName(name string) string {
+	words := normalizeWords(name)
+	sort.Strings(words)
+
+	return strings.Join(words, " ")
+}
+
+// addressSimilarity compares two addresses both as text, which forgives
+// typos, and as sets of words, which forgives words in another order or
+// left out.
+func addressSimilarity(address, other string) float64 {
+	var (
+		words      = normalizeWords(address)
+		otherWords = normalizeWords(other)
+		text       = textSimilarity(strings.Join(words, " "), strings.Join(otherWords, " "))
+	)
+
+	if set := wordSimilarity(words, otherWords); set > text {
+		return set
+	}
+
+	return text
+}
+
+// normalizeWords splits text into lowercase words without accents, so
+// "José García" and "jose garcia" have the same words.
+func normalizeWords(text string) []string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(text) {
+		if !unicode.Is(unicode.Mn, r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+
+	return strings.FieldsFunc(b.String(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// textSimilarity is one minus the edit distance between a and b relative to
+// the longer of them: 1 for equal texts and 0 when nothing is alike.
+func textSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	longest := utf8.RuneCountInString(a)
+	if n := utf8.RuneCountInString(b); n > longest {
+		longest = n
+	}
+
+	return 1 - float64(editDistance(a, b))/float64(longest)
+}
+
+// editDistance counts the runes to insert, delete or replace to turn a into
+// b.
+func editDistance(a, b string) int {
+	var (
+		ra       = []rune(a)
+		rb       = []rune(b)
+		previous = make([]int, len(rb)+1)
+		current  = make([]int, len(rb)+1)
+	)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+// wordSimilarity is the share of words a and b have in common.
+func wordSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	words := map[string]bool{}
+	for _, word := range a {
+		words[word] = true
+	}
+
+	var common int
+	seen := map[string]bool{}
+	for _, word := range b {
+		if words[word] && !seen[word] {
+			common++
+		}
+		seen[word] = true
+	}
+
+	return 2 * float64(common) / float64(len(words)+len(seen))
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+
+	return result
+}
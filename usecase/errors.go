@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"talentapp/model"
+)
+
+// The kinds of error a usecase returns. Callers test for them with
+// errors.Is to pick a response; the errors below each belong to one kind.
+var (
+	// ErrNotFound is returned when the record a request is about does not
+	// exist. It reads as the end of a sentence, as in "job with id x not
+	// found".
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is returned when a request cannot be applied to the
+	// current state of the records.
+	ErrConflict = errors.New("conflict")
+
+	// ErrValidation is returned when a request is well formed but its
+	// values are not acceptable, such as an id that refers to no record.
+	ErrValidation = errors.New("validation failed")
+
+	// ErrClosed is returned when a record no longer accepts changes.
+	ErrClosed = errors.New("closed")
+)
+
+var (
+	// ErrInUse is returned when a record cannot be deleted because other
+	// records still reference it.
+	ErrInUse error = &kindError{"record is still in use", ErrConflict}
+
+	// ErrAlreadyExists is returned when a record that must be unique is
+	// created twice.
+	ErrAlreadyExists error = &kindError{"record already exists", ErrConflict}
+
+	// ErrIllegalTransition is returned when an application is moved to a
+	// stage that cannot follow its current stage.
+	ErrIllegalTransition error = &kindError{"illegal stage transition", ErrValidation}
+
+	// ErrRecruitmentClosed is returned when a score is submitted to a
+	// recruitment that is closed or past its deadline.
+	ErrRecruitmentClosed error = &kindError{"recruitment is closed", ErrClosed}
+
+	// ErrNoConsent is returned when a candidate is to be contacted without a
+	// valid consent to it.
+	ErrNoConsent error = &kindError{"candidate has not consented to be contacted", ErrConflict}
+
+	// ErrErased is returned when a candidate whose personal data was erased
+	// is to be changed or given new records.
+	ErrErased error = &kindError{"candidate was erased", ErrClosed}
+
+	// ErrInvalidCredentials is returned when an email and password or a
+	// token do not match any user.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	// ErrInvalidImport is returned when an import file cannot be read or
+	// lacks a required column.
+	ErrInvalidImport = errors.New("invalid import file")
+)
+
+// kindError is an error that also matches its kind with errors.Is.
+type kindError struct {
+	message string
+	kind    error
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// DuplicateError is returned when a new candidate looks like one already
+// stored. It is an ErrAlreadyExists and carries the look-alikes, best first.
+type DuplicateError struct {
+	Duplicates []model.CandidateDuplicate
+}
+
+func (e *DuplicateError) Error() string {
+	ids := make([]string, len(e.Duplicates))
+	for i, duplicate := range e.Duplicates {
+		ids[i] = duplicate.Candidate.ID
+	}
+
+	return "candidate looks like the one(s) with id " + strings.Join(ids, ", ")
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrAlreadyExists
+}
+
+// dependent counts the records of one kind that refer to a record, which
+// keep it from being deleted.
+type dependent struct {
+	name  string
+	count func(ctx context.Context, id string) (int, error)
+}
+
+// ensureUnused returns an ErrInUse naming the first kind of dependent that
+// still refers to the record of subject with id.
+func ensureUnused(ctx context.Context, subject, id string, dependents ...dependent) error {
+	for _, d := range dependents {
+		total, err := d.count(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if total > 0 {
+			return fmt.Errorf("%s with id %s has %d %s(s): %w", subject, id, total, d.name, ErrInUse)
+		}
+	}
+
+	return nil
+}
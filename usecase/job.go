@@ -5,30 +5,48 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/google/uuid"
+	"math"
 	"talentapp/model"
 	"talentapp/repository"
+	"time"
 )
 
 type JobUsecase interface {
 	CreateNewJob(ctx context.Context, payload model.JobCreateRequest) (*model.Job, error)
 	GetJobByID(ctx context.Context, id string) (*model.Job, error)
-	GetJobs(ctx context.Context) (*[]model.Job, error)
+	GetJobs(ctx context.Context, filter model.JobListRequest) (*[]model.Job, *model.PageMeta, error)
+	UpdateJob(ctx context.Context, id string, payload model.JobUpdateRequest) (*model.Job, error)
+	DeleteJob(ctx context.Context, id string) error
+	GetScoringProfile(ctx context.Context, id string) (*model.ScoringProfile, error)
+	GetScoringProfiles(ctx context.Context, id string) (*[]model.ScoringProfile, error)
+	UpdateScoringProfile(ctx context.Context, id string, payload model.ScoringProfileRequest) (*model.ScoringProfile, error)
 }
 
 type jobUsecase struct {
-	jobRepository repository.JobRepository
+	jobRepository            repository.JobRepository
+	recruitmentRepository    repository.RecruitmentRepository
+	scoringProfileRepository repository.ScoringProfileRepository
+	transactor               repository.Transactor
 }
 
-func NewJobUsecase(jobRepository repository.JobRepository) JobUsecase {
+func NewJobUsecase(
+	jobRepository repository.JobRepository,
+	recruitmentRepository repository.RecruitmentRepository,
+	scoringProfileRepository repository.ScoringProfileRepository,
+	transactor repository.Transactor,
+) JobUsecase {
 	return &jobUsecase{
 		jobRepository,
+		recruitmentRepository,
+		scoringProfileRepository,
+		transactor,
 	}
 }
 
 func (u *jobUsecase) GetJobByID(ctx context.Context, id string) (*model.Job, error) {
 	result, err := u.jobRepository.GetJobByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("job with id %s not found", id)
+		return nil, fmt.Errorf("job with id %s %w", id, ErrNotFound)
 	}
 
 	if err != nil {
@@ -38,15 +56,21 @@ func (u *jobUsecase) GetJobByID(ctx context.Context, id string) (*model.Job, err
 	return result, nil
 }
 
-func (u *jobUsecase) GetJobs(ctx context.Context) (*[]model.Job, error) {
-	result, err := u.jobRepository.GetJobList(ctx)
+func (u *jobUsecase) GetJobs(ctx context.Context, filter model.JobListRequest) (*[]model.Job, *model.PageMeta, error) {
+	filter.Normalize()
+
+	result, total, err := u.jobRepository.GetJobList(ctx, filter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return result, nil
+	highlightJobs(filter.Query, *result)
+
+	return result, model.NewPageMeta(filter.Pagination, total), nil
 }
 
+// CreateNewJob stores a job with the default scoring profile as the first
+// version of its profile.
 func (u *jobUsecase) CreateNewJob(ctx context.Context, payload model.JobCreateRequest) (*model.Job, error) {
 	var (
 		result = new(model.Job)
@@ -62,10 +86,179 @@ func (u *jobUsecase) CreateNewJob(ctx context.Context, payload model.JobCreateRe
 		Criteria:       payload.Criteria,
 	}
 
-	err := u.jobRepository.PostJob(ctx, result)
+	if payload.Requirements != nil {
+		result.Requirements = *payload.Requirements
+	}
+	result.Requirements.Normalize()
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.jobRepository.PostJob(ctx, result); err != nil {
+			return err
+		}
+
+		_, err := seedScoringProfile(ctx, u.scoringProfileRepository, newID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *jobUsecase) UpdateJob(ctx context.Context, id string, payload model.JobUpdateRequest) (*model.Job, error) {
+	result, err := u.GetJobByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	if payload.Position != nil {
+		result.Position = *payload.Position
+	}
+
+	if payload.Department != nil {
+		result.Department = *payload.Department
+	}
+
+	if payload.Requester != nil {
+		result.Requester = *payload.Requester
+	}
+
+	if payload.JobDescription != nil {
+		result.JobDescription = *payload.JobDescription
+	}
+
+	if payload.Criteria != nil {
+		result.Criteria = *payload.Criteria
+	}
+
+	if payload.Requirements != nil {
+		result.Requirements = *payload.Requirements
+		result.Requirements.Normalize()
+	}
+
+	err = u.jobRepository.UpdateJob(ctx, result)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("job with id %s %w", id, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteJob removes a job that is not used by any recruitment.
+func (u *jobUsecase) DeleteJob(ctx context.Context, id string) error {
+	if _, err := u.GetJobByID(ctx, id); err != nil {
+		return err
+	}
+
+	total, err := u.recruitmentRepository.CountRecruitmentByJobID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if total > 0 {
+		return fmt.Errorf("job with id %s is used by %d recruitment(s): %w", id, total, ErrInUse)
+	}
+
+	err = u.jobRepository.DeleteJob(ctx, id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("job with id %s %w", id, ErrNotFound)
+	}
+
+	return err
+}
+
+// GetScoringProfile returns the latest scoring profile of a job.
+func (u *jobUsecase) GetScoringProfile(ctx context.Context, id string) (*model.ScoringProfile, error) {
+	if _, err := u.GetJobByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return currentScoringProfile(ctx, u.scoringProfileRepository, id)
+}
+
+func (u *jobUsecase) GetScoringProfiles(ctx context.Context, id string) (*[]model.ScoringProfile, error) {
+	if _, err := u.GetJobByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	result, err := u.scoringProfileRepository.GetScoringProfileListByJobID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateScoringProfile stores payload as a new version of the job scoring
+// profile. Scores already given keep the version they were computed with.
+func (u *jobUsecase) UpdateScoringProfile(ctx context.Context, id string, payload model.ScoringProfileRequest) (*model.ScoringProfile, error) {
+	if _, err := u.GetJobByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	totalWeight := payload.AttitudeWeight + payload.RelocationWeight + payload.SkillWeight + payload.ExperienceWeight
+	if math.Abs(totalWeight-1) > 0.001 {
+		return nil, fmt.Errorf("%w: scoring weights must add up to 1, got %.3f", ErrValidation, totalWeight)
+	}
+
+	years := make(map[int]bool)
+	for _, band := range payload.ExperienceBands {
+		if years[band.MinYears] {
+			return nil, fmt.Errorf("%w: experience band for %d year(s) is defined more than once", ErrValidation, band.MinYears)
+		}
+		years[band.MinYears] = true
+	}
+
+	result := &model.ScoringProfile{
+		ID:               uuid.NewString(),
+		JobID:            id,
+		AttitudeWeight:   payload.AttitudeWeight,
+		RelocationWeight: payload.RelocationWeight,
+		SkillWeight:      payload.SkillWeight,
+		ExperienceWeight: payload.ExperienceWeight,
+		ExperienceBands:  payload.ExperienceBands,
+		GradeValues:      payload.GradeValues,
+		CreatedAt:        time.Now(),
+	}
+
+	err := u.scoringProfileRepository.PostScoringProfile(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// currentScoringProfile returns the latest scoring profile of a job. A job
+// that somehow has none is given the default one first, so scores always
+// refer to a stored profile.
+func currentScoringProfile(ctx context.Context, scoringProfileRepository repository.ScoringProfileRepository, jobID string) (*model.ScoringProfile, error) {
+	result, err := scoringProfileRepository.GetLatestScoringProfileByJobID(ctx, jobID)
+	if err == sql.ErrNoRows {
+		return seedScoringProfile(ctx, scoringProfileRepository, jobID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// seedScoringProfile stores the default profile as the first version of the
+// profile of a job.
+func seedScoringProfile(ctx context.Context, scoringProfileRepository repository.ScoringProfileRepository, jobID string) (*model.ScoringProfile, error) {
+	result := model.DefaultScoringProfile(jobID)
+	result.CreatedAt = time.Now()
+
+	if err := scoringProfileRepository.PostScoringProfile(ctx, result); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
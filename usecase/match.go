@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"talentapp/model"
+)
+
+// criterionWeights weigh the criteria of a job in the score of a match.
+// Criteria the job does not ask for are left out of it.
+var criterionWeights = map[string]float64{
+	model.CriterionExperience:       25,
+	model.CriterionRequiredSkills:   35,
+	model.CriterionNiceToHaveSkills: 15,
+	model.CriterionLocation:         15,
+	model.CriterionRelocation:       10,
+}
+
+// relocationLocationScore is the location score of a candidate who lives
+// elsewhere but is willing to relocate.
+const relocationLocationScore = 0.75
+
+// matchCandidate scores candidate against requirements, which must ask for
+// something, explaining each criterion.
+func matchCandidate(requirements model.JobRequirements, candidate model.Candidate) model.CandidateMatch {
+	var (
+		result = model.CandidateMatch{
+			Candidate: candidate,
+			Qualified: true,
+			Criteria:  []model.MatchCriterion{},
+		}
+		willing    = candidate.WillingToRelocate == "yes"
+		total, sum float64
+		has        = map[string]bool{}
+	)
+
+	for _, skill := range candidate.Skills {
+		has[strings.ToLower(skill)] = true
+	}
+
+	if requirements.MinExperience > 0 {
+		result.Criteria = append(result.Criteria, model.MatchCriterion{
+			Criterion: model.CriterionExperience,
+			Required:  true,
+			Met:       candidate.Experience >= requirements.MinExperience,
+			Score:     math.Min(1, float64(candidate.Experience)/float64(requirements.MinExperience)),
+			Detail:    fmt.Sprintf("%d year(s), %d asked", candidate.Experience, requirements.MinExperience),
+		})
+	}
+
+	if len(requirements.RequiredSkills) > 0 {
+		result.Criteria = append(result.Criteria, matchSkills(model.CriterionRequiredSkills, requirements.RequiredSkills, has))
+	}
+
+	if len(requirements.NiceToHaveSkills) > 0 {
+		result.Criteria = append(result.Criteria, matchSkills(model.CriterionNiceToHaveSkills, requirements.NiceToHaveSkills, has))
+	}
+
+	if requirements.Location != "" {
+		criterion := model.MatchCriterion{
+			Criterion: model.CriterionLocation,
+			Required:  true,
+			Detail:    "lives in " + candidate.Address,
+		}
+
+		switch {
+		case strings.Contains(strings.ToLower(candidate.Address), strings.ToLower(requirements.Location)):
+			criterion.Met, criterion.Score = true, 1
+		case willing:
+			criterion.Met, criterion.Score = true, relocationLocationScore
+			criterion.Detail += ", willing to relocate to " + requirements.Location
+		default:
+			criterion.Detail += ", not willing to relocate to " + requirements.Location
+		}
+
+		result.Criteria = append(result.Criteria, criterion)
+	}
+
+	if requirements.RelocationRequired {
+		criterion := model.MatchCriterion{
+			Criterion: model.CriterionRelocation,
+			Required:  true,
+			Met:       willing,
+			Detail:    "not willing to relocate",
+		}
+
+		if willing {
+			criterion.Score, criterion.Detail = 1, "willing to relocate"
+		}
+
+		result.Criteria = append(result.Criteria, criterion)
+	}
+
+	for i, criterion := range result.Criteria {
+		weight := criterionWeights[criterion.Criterion]
+		total += weight
+		sum += weight * criterion.Score
+
+		if criterion.Required && !criterion.Met {
+			result.Qualified = false
+		}
+
+		result.Criteria[i].Score = math.Round(criterion.Score*100) / 100
+	}
+
+	if total > 0 {
+		result.Score = math.Round(sum/total*10000) / 100
+	}
+
+	return result
+}
+
+// matchSkills compares the skills a job asks for with the lowercase skills
+// of a candidate. Required skills are met when the candidate has them all,
+// nice to have ones when the candidate has any.
+func matchSkills(criterion string, skills []string, has map[string]bool) model.MatchCriterion {
+	result := model.MatchCriterion{
+		Criterion: criterion,
+		Required:  criterion == model.CriterionRequiredSkills,
+	}
+
+	for _, skill := range skills {
+		if has[strings.ToLower(skill)] {
+			result.Matched = append(result.Matched, skill)
+		} else {
+			result.Missing = append(result.Missing, skill)
+		}
+	}
+
+	result.Score = float64(len(result.Matched)) / float64(len(skills))
+	result.Detail = fmt.Sprintf("%d of %d", len(result.Matched), len(skills))
+
+	if result.Required {
+		result.Met = len(result.Missing) == 0
+	} else {
+		result.Met = len(result.Matched) > 0
+	}
+
+	return result
+}
@@ -0,0 +1,214 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"talentapp/model"
+	"talentapp/repository"
+	"talentapp/storage"
+	"time"
+)
+
+// PrivacyUsecase answers the requests of candidates about their personal
+// data: a copy of everything stored about them, and its erasure.
+type PrivacyUsecase interface {
+	ExportCandidate(ctx context.Context, id string) (*model.CandidateExport, error)
+	OpenAttachment(ctx context.Context, attachment model.Attachment) (io.ReadCloser, error)
+	EraseCandidate(ctx context.Context, id string) (*model.Candidate, error)
+}
+
+type privacyUsecase struct {
+	candidateRepository        repository.CandidateRepository
+	candidateConsentRepository repository.CandidateConsentRepository
+	candidateScoreRepository   repository.CandidateScoreRepository
+	scorecardRepository        repository.ScorecardRepository
+	applicationRepository      repository.ApplicationRepository
+	attachmentRepository       repository.AttachmentRepository
+	auditRepository            repository.AuditRepository
+	storage                    storage.Storage
+	transactor                 repository.Transactor
+}
+
+func NewPrivacyUsecase(
+	candidateRepository repository.CandidateRepository,
+	candidateConsentRepository repository.CandidateConsentRepository,
+	candidateScoreRepository repository.CandidateScoreRepository,
+	scorecardRepository repository.ScorecardRepository,
+	applicationRepository repository.ApplicationRepository,
+	attachmentRepository repository.AttachmentRepository,
+	auditRepository repository.AuditRepository,
+	storage storage.Storage,
+	transactor repository.Transactor,
+) PrivacyUsecase {
+	return &privacyUsecase{
+		candidateRepository:        candidateRepository,
+		candidateConsentRepository: candidateConsentRepository,
+		candidateScoreRepository:   candidateScoreRepository,
+		scorecardRepository:        scorecardRepository,
+		applicationRepository:      applicationRepository,
+		attachmentRepository:       attachmentRepository,
+		auditRepository:            auditRepository,
+		storage:                    storage,
+		transactor:                 transactor,
+	}
+}
+
+// ExportCandidate gathers everything stored about a candidate. The content
+// of the attachments is read with OpenAttachment.
+func (u *privacyUsecase) ExportCandidate(ctx context.Context, id string) (*model.CandidateExport, error) {
+	result := &model.CandidateExport{ExportedAt: time.Now()}
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		candidate, err := u.candidateRepository.GetCandidateByID(ctx, id)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("candidate with id %s %w", id, ErrNotFound)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err = u.candidateRepository.LoadSkills(ctx, candidate); err != nil {
+			return err
+		}
+
+		if err = u.candidateConsentRepository.LoadConsents(ctx, candidate); err != nil {
+			return err
+		}
+
+		candidate.ApplyConsents(result.ExportedAt)
+		result.Candidate = *candidate
+		result.Email, result.Phone = candidate.Email, candidate.Phone
+
+		scores, err := u.candidateScoreRepository.GetCandidateScoreListByCandidateID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// the export already is about the candidate
+		for i := range *scores {
+			(*scores)[i].Candidate = nil
+		}
+		result.Scores = *scores
+
+		scorecards, err := u.scorecardRepository.GetScorecardListByCandidateID(ctx, id)
+		if err != nil {
+			return err
+		}
+		result.Scorecards = *scorecards
+
+		applications, err := u.applicationRepository.GetApplicationListByCandidateID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		result.Applications = make([]model.CandidateExportApplication, len(*applications))
+		for i, application := range *applications {
+			events, err := u.applicationRepository.GetApplicationStageEventListByApplicationID(ctx, application.ID)
+			if err != nil {
+				return err
+			}
+
+			result.Applications[i] = model.CandidateExportApplication{Application: application, Events: *events}
+		}
+
+		attachments, err := u.attachmentRepository.GetAttachmentListByCandidateID(ctx, id)
+		if err != nil {
+			return err
+		}
+		result.Attachments = *attachments
+
+		logs, err := u.auditRepository.GetAuditLogListByCandidateID(ctx, id)
+		if err != nil {
+			return err
+		}
+		result.AuditLog = *logs
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// OpenAttachment returns the content of an attachment of an export, which
+// the caller must close.
+func (u *privacyUsecase) OpenAttachment(ctx context.Context, attachment model.Attachment) (io.ReadCloser, error) {
+	content, err := u.storage.Open(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("content of attachment with id %s %w", attachment.ID, ErrNotFound)
+	}
+
+	return content, err
+}
+
+// EraseCandidate anonymizes a candidate and deletes their attachments, see
+// model.ErasedCandidateName. Their scores and applications are kept, so the
+// statistics of past recruitments do not change. The files of the
+// attachments are deleted once the erasure is committed, unless another
+// candidate shares them.
+func (u *privacyUsecase) EraseCandidate(ctx context.Context, id string) (*model.Candidate, error) {
+	var storageKeys []string
+
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		candidate, err := u.candidateRepository.GetCandidateByID(ctx, id)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("candidate with id %s %w", id, ErrNotFound)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if candidate.Erased() {
+			return fmt.Errorf("candidate with id %s: %w", id, ErrErased)
+		}
+
+		attachments, err := u.attachmentRepository.GetAttachmentListByCandidateID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// attachments are deleted first, so the erasure also clears their
+		// names from the audit log
+		for _, attachment := range *attachments {
+			if err = u.attachmentRepository.DeleteAttachment(ctx, attachment.ID); err != nil {
+				return err
+			}
+
+			storageKeys = append(storageKeys, attachment.StorageKey)
+		}
+
+		return u.candidateRepository.EraseCandidate(ctx, id, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range storageKeys {
+		total, err := u.attachmentRepository.CountAttachmentByStorageKey(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		if total > 0 {
+			continue
+		}
+
+		if err = u.storage.Delete(ctx, key); err != nil {
+			return nil, fmt.Errorf("candidate with id %s was erased but a file of theirs was not deleted: %w", id, err)
+		}
+	}
+
+	result, err := u.candidateRepository.GetCandidateByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
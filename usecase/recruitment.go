@@ -6,20 +6,31 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"math"
+	"sort"
+	"strings"
 	"talentapp/model"
 	"talentapp/repository"
-	"talentapp/utils"
 	"time"
 )
 
 type RecruitmentUsecase interface {
 	CreateNewRecruitment(ctx context.Context, payload model.RecruitmentCreateRequest) (*model.Recruitment, error)
 	GetRecruitmentByID(ctx context.Context, id string) (*model.Recruitment, error)
-	GetRecruitments(ctx context.Context) (*[]model.Recruitment, error)
+	GetRecruitments(ctx context.Context, filter model.RecruitmentListRequest) (*[]model.Recruitment, *model.PageMeta, error)
 	UpdateRecruitmentStatus(ctx context.Context, id string, payload model.RecruitmentUpdateStatusRequest) (*model.Recruitment, error)
-	GetRecruitmentScores(ctx context.Context, id string) (*[]model.CandidateScore, error)
+	GetRecruitmentScores(ctx context.Context, id string, filter model.CandidateScoreListRequest) (*[]model.CandidateScore, *model.PageMeta, error)
+	GetCandidateScoreReport(ctx context.Context, id string, filter model.CandidateScoreListRequest) (*model.CandidateScoreReport, error)
 	CreateNewCandidateScore(ctx context.Context, recruitmentID string, payload model.CandidateScoreCreateRequest) (*model.CandidateScore, error)
 	GetCandidateScoreByID(ctx context.Context, id, candidateID string) (*model.CandidateScore, error)
+	UpdateRecruitment(ctx context.Context, id string, payload model.RecruitmentUpdateRequest) (*model.Recruitment, error)
+	DeleteRecruitment(ctx context.Context, id string) error
+	UpdateCandidateScore(ctx context.Context, id, candidateID string, payload model.CandidateScoreUpdateRequest) (*model.CandidateScore, error)
+	DeleteCandidateScore(ctx context.Context, id, candidateID string) error
+	GetScorecards(ctx context.Context, id, candidateID string) (*model.ScorecardSummary, error)
+	SubmitScorecard(ctx context.Context, id, candidateID string, payload model.ScorecardCreateRequest) (*model.Scorecard, error)
+	DeleteScorecard(ctx context.Context, id, candidateID, scorecardID string) error
+	CloseExpiredRecruitments(ctx context.Context) (int64, error)
+	GetSuggestedCandidates(ctx context.Context, id string, filter model.CandidateMatchListRequest) (*[]model.CandidateMatch, *model.PageMeta, error)
 }
 
 type recruitmentUsecase struct {
@@ -27,6 +38,10 @@ type recruitmentUsecase struct {
 	candidateScoreRepository repository.CandidateScoreRepository
 	candidateRepository      repository.CandidateRepository
 	jobRepository            repository.JobRepository
+	scoringProfileRepository repository.ScoringProfileRepository
+	scorecardRepository      repository.ScorecardRepository
+	applicationRepository    repository.ApplicationRepository
+	transactor               repository.Transactor
 }
 
 func NewRecruitmentUsecase(
@@ -34,39 +49,45 @@ func NewRecruitmentUsecase(
 	candidateScoreRepository repository.CandidateScoreRepository,
 	candidateRepository repository.CandidateRepository,
 	jobRepository repository.JobRepository,
+	scoringProfileRepository repository.ScoringProfileRepository,
+	scorecardRepository repository.ScorecardRepository,
+	applicationRepository repository.ApplicationRepository,
+	transactor repository.Transactor,
 ) RecruitmentUsecase {
 	return &recruitmentUsecase{
 		recruitmentRepository:    recruitmentRepository,
 		candidateScoreRepository: candidateScoreRepository,
 		candidateRepository:      candidateRepository,
 		jobRepository:            jobRepository,
+		scoringProfileRepository: scoringProfileRepository,
+		scorecardRepository:      scorecardRepository,
+		applicationRepository:    applicationRepository,
+		transactor:               transactor,
 	}
 }
 
 func (u *recruitmentUsecase) GetRecruitmentByID(ctx context.Context, id string) (*model.Recruitment, error) {
 	result, err := u.recruitmentRepository.GetRecruitmentByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("recruitment with id %s not found", id)
+		return nil, fmt.Errorf("recruitment with id %s %w", id, ErrNotFound)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	if err = result.LoadJob(ctx); err != nil {
-		return nil, err
-	}
-
 	return result, nil
 }
 
-func (u *recruitmentUsecase) GetRecruitments(ctx context.Context) (*[]model.Recruitment, error) {
-	result, err := u.recruitmentRepository.GetRecruitments(ctx)
+func (u *recruitmentUsecase) GetRecruitments(ctx context.Context, filter model.RecruitmentListRequest) (*[]model.Recruitment, *model.PageMeta, error) {
+	filter.Normalize()
+
+	result, total, err := u.recruitmentRepository.GetRecruitments(ctx, filter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return result, nil
+	return result, model.NewPageMeta(filter.Pagination, total), nil
 }
 
 func (u *recruitmentUsecase) CreateNewRecruitment(ctx context.Context, payload model.RecruitmentCreateRequest) (*model.Recruitment, error) {
@@ -75,16 +96,27 @@ func (u *recruitmentUsecase) CreateNewRecruitment(ctx context.Context, payload m
 		newID  = uuid.NewString()
 	)
 
-	deadline, err := time.Parse("2006-01-02", payload.Deadline)
+	deadline, err := parseDeadline(payload.Deadline)
 	if err != nil {
 		return nil, err
 	}
 
+	if payload.ScoreAggregation == "" {
+		payload.ScoreAggregation = model.AggregationMean
+	}
+
+	if _, err = u.jobRepository.GetJobByID(ctx, payload.JobID); err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: job with id %s does not exist", ErrValidation, payload.JobID)
+	} else if err != nil {
+		return nil, err
+	}
+
 	result = &model.Recruitment{
-		ID:       newID,
-		JobID:    payload.JobID, // TODO: validate job id
-		Status:   "open",
-		Deadline: deadline.Add(time.Hour * 23).Add(time.Minute * 59).Add(time.Second * 59),
+		ID:               newID,
+		JobID:            payload.JobID,
+		Status:           model.RecruitmentOpen,
+		Deadline:         deadline,
+		ScoreAggregation: payload.ScoreAggregation,
 	}
 
 	err = u.recruitmentRepository.CreateRecruitment(ctx, result)
@@ -92,6 +124,10 @@ func (u *recruitmentUsecase) CreateNewRecruitment(ctx context.Context, payload m
 		return nil, err
 	}
 
+	if err = u.recruitmentRepository.LoadJobs(ctx, result); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -102,14 +138,14 @@ func (u *recruitmentUsecase) UpdateRecruitmentStatus(ctx context.Context, id str
 
 	result, err := u.recruitmentRepository.GetRecruitmentByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("recruitment with id %s not found", id)
+		return nil, fmt.Errorf("recruitment with id %s %w", id, ErrNotFound)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
-	result.Status = payload.Status
+	setRecruitmentStatus(result, payload.Status)
 
 	err = u.recruitmentRepository.UpdateRecruitmentStatus(ctx, result)
 	if err != nil {
@@ -119,83 +155,690 @@ func (u *recruitmentUsecase) UpdateRecruitmentStatus(ctx context.Context, id str
 	return result, nil
 }
 
-func (u *recruitmentUsecase) GetRecruitmentScores(ctx context.Context, id string) (*[]model.CandidateScore, error) {
-	result, err := u.candidateScoreRepository.GetCandidateScoreListByRecruitmentID(ctx, id)
+func (u *recruitmentUsecase) GetRecruitmentScores(ctx context.Context, id string, filter model.CandidateScoreListRequest) (*[]model.CandidateScore, *model.PageMeta, error) {
+	filter.Normalize()
+
+	result, total, err := u.candidateScoreRepository.GetCandidateScoreListByRecruitmentID(ctx, id, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, model.NewPageMeta(filter.Pagination, total), nil
+}
+
+// GetCandidateScoreReport returns every score of a recruitment that matches
+// filter, ignoring its page, together with the recruitment and its job.
+func (u *recruitmentUsecase) GetCandidateScoreReport(ctx context.Context, id string, filter model.CandidateScoreListRequest) (*model.CandidateScoreReport, error) {
+	recruitment, err := u.GetRecruitmentByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	result := &model.CandidateScoreReport{
+		Recruitment: recruitment,
+		Scores:      []model.CandidateScore{},
+		GeneratedAt: time.Now(),
+	}
+
+	filter.Page = 1
+	filter.Size = model.MaxPageSize
+
+	for {
+		scores, total, err := u.candidateScoreRepository.GetCandidateScoreListByRecruitmentID(ctx, id, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Scores = append(result.Scores, *scores...)
+		if len(*scores) == 0 || len(result.Scores) >= total {
+			break
+		}
+
+		filter.Page++
+	}
+
 	return result, nil
 }
 
+// GetSuggestedCandidates matches the whole talent pool against the
+// requirements of the job of a recruitment, best match first. Candidates
+// who already applied to the recruitment are left out.
+func (u *recruitmentUsecase) GetSuggestedCandidates(ctx context.Context, id string, filter model.CandidateMatchListRequest) (*[]model.CandidateMatch, *model.PageMeta, error) {
+	filter.Normalize()
+
+	recruitment, err := u.GetRecruitmentByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requirements := recruitment.Job.Requirements
+	if requirements.IsZero() {
+		return nil, nil, fmt.Errorf("%w: job with id %s has no requirements to match candidates against", ErrValidation, recruitment.JobID)
+	}
+
+	applications, err := u.applicationRepository.GetApplicationListByRecruitmentID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applied := map[string]bool{}
+	for _, application := range *applications {
+		applied[application.CandidateID] = true
+	}
+
+	matches := []model.CandidateMatch{}
+
+	err = forEachCandidate(ctx, u.candidateRepository, func(candidate model.Candidate) {
+		if applied[candidate.ID] {
+			return
+		}
+
+		match := matchCandidate(requirements, candidate)
+		if (filter.QualifiedOnly && !match.Qualified) || match.Score < filter.MinScore {
+			return
+		}
+
+		matches = append(matches, match)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the pool is read by name, which orders equal scores
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	total := len(matches)
+	start := filter.Offset()
+	if start > total {
+		start = total
+	}
+
+	end := start + filter.Size
+	if end > total {
+		end = total
+	}
+
+	result := matches[start:end]
+
+	return &result, model.NewPageMeta(filter.Pagination, total), nil
+}
+
+// CreateNewCandidateScore scores a candidate who has no score in the
+// recruitment yet.
 func (u *recruitmentUsecase) CreateNewCandidateScore(ctx context.Context, recruitmentID string, payload model.CandidateScoreCreateRequest) (*model.CandidateScore, error) {
 	var (
-		result                                                             = new(model.CandidateScore)
-		newID                                                              = uuid.NewString()
-		willingToRelocateScore, experienceScore, attitudeScore, skillScore float64
+		result = new(model.CandidateScore)
+		newID  = uuid.NewString()
+	)
+
+	recruitment, err := u.recruitmentRepository.GetRecruitmentByID(ctx, recruitmentID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("recruitment with id %s %w", recruitmentID, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if recruitment.IsClosed(time.Now()) {
+		return nil, fmt.Errorf("recruitment with id %s no longer accepts scores: %w", recruitmentID, ErrRecruitmentClosed)
+	}
+
+	if candidate, err := u.candidateRepository.GetCandidateByID(ctx, payload.CandidateID); err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: candidate with id %s does not exist", ErrValidation, payload.CandidateID)
+	} else if err != nil {
+		return nil, err
+	} else if candidate.Erased() {
+		return nil, fmt.Errorf("candidate with id %s: %w", payload.CandidateID, ErrErased)
+	}
+
+	profile, err := currentScoringProfile(ctx, u.scoringProfileRepository, recruitment.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ensureGrades(profile, payload.AttitudeScore, payload.SkillScore); err != nil {
+		return nil, err
+	}
+
+	result = calculateCandidateScore(profile, payload.WillingToRelocate, payload.Experience, payload.AttitudeScore, payload.SkillScore)
+	result.ID = newID
+	result.CandidateID = payload.CandidateID
+	result.RecruitmentID = recruitmentID
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		total, err := u.candidateScoreRepository.CountCandidateScore(ctx, recruitmentID, payload.CandidateID)
+		if err != nil {
+			return err
+		}
+
+		if total > 0 {
+			return fmt.Errorf("candidate with id %s already has a score in recruitment %s: %w", payload.CandidateID, recruitmentID, ErrAlreadyExists)
+		}
+
+		// the next scorecard would overwrite a manual score
+		scorecards, err := u.scorecardRepository.GetScorecardListByCandidate(ctx, recruitmentID, payload.CandidateID)
+		if err != nil {
+			return err
+		}
+
+		if len(*scorecards) > 0 {
+			return fmt.Errorf("candidate with id %s is scored by %d scorecard(s) in recruitment %s: %w", payload.CandidateID, len(*scorecards), recruitmentID, ErrAlreadyExists)
+		}
+
+		return u.candidateScoreRepository.PostCandidateScore(ctx, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// setRecruitmentStatus opens or closes the recruitment by hand. Closing an
+// already closed recruitment keeps the original closing time and reason.
+func setRecruitmentStatus(recruitment *model.Recruitment, status string) {
+	if status == recruitment.Status {
+		return
+	}
+
+	if status == model.RecruitmentClose {
+		recruitment.Close(time.Now(), model.ClosedReasonManual)
+		return
+	}
+
+	recruitment.Reopen()
+}
+
+// CloseExpiredRecruitments closes the open recruitments whose deadline has
+// passed. It is run periodically by the scheduler.
+func (u *recruitmentUsecase) CloseExpiredRecruitments(ctx context.Context) (int64, error) {
+	return u.recruitmentRepository.CloseExpiredRecruitments(ctx, time.Now(), model.ClosedReasonDeadline)
+}
+
+// recruitmentScoringProfile returns the profile new scores of a recruitment
+// are computed with, which is the current profile of its job.
+func (u *recruitmentUsecase) recruitmentScoringProfile(ctx context.Context, id string) (*model.ScoringProfile, error) {
+	recruitment, err := u.recruitmentRepository.GetRecruitmentByID(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("recruitment with id %s %w", id, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return currentScoringProfile(ctx, u.scoringProfileRepository, recruitment.JobID)
+}
+
+// calculateCandidateScore scores a candidate with Simple Additive Weighting
+// using the weights, experience bands and grade scale of profile.
+func calculateCandidateScore(profile *model.ScoringProfile, relocation string, years int, attitudeGrade, skillGrade string) *model.CandidateScore {
+	return weighCandidateScore(
+		profile,
+		relocationScore(relocation),
+		profile.ExperienceScore(years),
+		profile.GradeValue(attitudeGrade),
+		profile.GradeValue(skillGrade),
 	)
+}
 
-	switch payload.WillingToRelocate {
+func relocationScore(relocation string) float64 {
+	switch relocation {
 	case "yes":
-		willingToRelocateScore = 100
+		return 100
 	case "no":
-		willingToRelocateScore = 50
-	default:
-		willingToRelocateScore = 50
-	}
-
-	switch exp := payload.Experience; {
-	case exp == 1:
-		experienceScore = 50
-	case exp == 2:
-		experienceScore = 70
-	case exp == 3:
-		experienceScore = 80
-	case exp == 4:
-		experienceScore = 90
-	case exp >= 5:
-		experienceScore = 100
+		return 50
 	default:
-		experienceScore = 50
+		return 50
+	}
+}
+
+// ensureGrades returns an ErrValidation naming the first grade that is not
+// on the scale of profile.
+func ensureGrades(profile *model.ScoringProfile, grades ...string) error {
+	for _, grade := range grades {
+		if _, ok := profile.GradeValues[grade]; !ok {
+			return fmt.Errorf("%w: grade %q is not one of %s", ErrValidation, grade, strings.Join(profile.Grades(), ", "))
+		}
 	}
 
-	attitudeScore = utils.GetFloat64Value(payload.AttitudeScore)
-	skillScore = utils.GetFloat64Value(payload.SkillScore)
+	return nil
+}
 
-	attitude := attitudeScore * 0.2
-	willingToRelocate := willingToRelocateScore * 0.2
-	skill := skillScore * 0.3
-	experience := experienceScore * 0.3
+// weighCandidateScore applies the profile weights to criteria that are
+// already converted to scores.
+func weighCandidateScore(profile *model.ScoringProfile, willingToRelocateScore, experienceScore, attitudeScore, skillScore float64) *model.CandidateScore {
+	attitude := attitudeScore * profile.AttitudeWeight
+	willingToRelocate := willingToRelocateScore * profile.RelocationWeight
+	skill := skillScore * profile.SkillWeight
+	experience := experienceScore * profile.ExperienceWeight
 	overallScore := math.Round((attitude+willingToRelocate+skill+experience)*100) / 100
 
-	result = &model.CandidateScore{
-		ID:                     newID,
-		CandidateID:            payload.CandidateID, // TODO: validate candidateID
-		RecruitmentID:          recruitmentID,       // TODO: validate recruitmentID
+	return &model.CandidateScore{
 		WillingToRelocateScore: willingToRelocateScore,
 		AttitudeScore:          attitudeScore,
 		SkillScore:             skillScore,
 		ExperienceScore:        experienceScore,
 		OverallScore:           overallScore,
+		ScoringProfileID:       profile.ID,
+		ScoringProfileVersion:  profile.Version,
+	}
+}
+
+// GetCandidateScoreByID returns the score of a candidate in a recruitment
+// with its relations and the breakdown by criterion.
+func (u *recruitmentUsecase) GetCandidateScoreByID(ctx context.Context, id, candidateID string) (*model.CandidateScore, error) {
+	result, err := u.candidateScoreRepository.GetCandidateScoreByID(ctx, id, candidateID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("candidate score for candidate %s in recruitment %s %w", candidateID, id, ErrNotFound)
 	}
 
-	err := u.candidateScoreRepository.PostCandidateScore(ctx, result)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = setScoreBreakdown(ctx, u.scoringProfileRepository, result); err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
 
-func (u *recruitmentUsecase) GetCandidateScoreByID(ctx context.Context, id, candidateID string) (*model.CandidateScore, error) {
-	result, err := u.candidateScoreRepository.GetCandidateScoreByID(ctx, id, candidateID)
+// setScoreBreakdown fills the breakdown of every score with the profile it
+// was computed with, reading each profile once. A score without a profile
+// was computed with the default one. The recruitments of the scores must be
+// loaded.
+func setScoreBreakdown(ctx context.Context, scoringProfileRepository repository.ScoringProfileRepository, scores ...*model.CandidateScore) error {
+	profiles := map[string]*model.ScoringProfile{}
+
+	for _, score := range scores {
+		if score.ScoringProfileID == "" {
+			score.SetBreakdown(model.DefaultScoringProfile(score.Recruitment.JobID))
+			continue
+		}
+
+		profile, ok := profiles[score.ScoringProfileID]
+		if !ok {
+			var err error
+			if profile, err = scoringProfileRepository.GetScoringProfileByID(ctx, score.ScoringProfileID); err != nil {
+				return err
+			}
+
+			profiles[score.ScoringProfileID] = profile
+		}
+
+		score.SetBreakdown(profile)
+	}
+
+	return nil
+}
+
+func (u *recruitmentUsecase) UpdateRecruitment(ctx context.Context, id string, payload model.RecruitmentUpdateRequest) (*model.Recruitment, error) {
+	result, err := u.recruitmentRepository.GetRecruitmentByID(ctx, id)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("candidate score with id %s not found", id)
+		return nil, fmt.Errorf("recruitment with id %s %w", id, ErrNotFound)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	if payload.JobID != nil {
+		job, err := u.jobRepository.GetJobByID(ctx, *payload.JobID)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: job with id %s does not exist", ErrValidation, *payload.JobID)
+		} else if err != nil {
+			return nil, err
+		}
+
+		result.JobID = *payload.JobID
+		result.Job = job
+	}
+
+	if payload.Deadline != nil {
+		if result.Deadline, err = parseDeadline(*payload.Deadline); err != nil {
+			return nil, err
+		}
+
+		result.DeadlineString = *payload.Deadline
+	}
+
+	if payload.Status != nil {
+		setRecruitmentStatus(result, *payload.Status)
+	}
+
+	reaggregate := payload.ScoreAggregation != nil && *payload.ScoreAggregation != result.ScoreAggregation
+	if payload.ScoreAggregation != nil {
+		result.ScoreAggregation = *payload.ScoreAggregation
+	}
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := u.recruitmentRepository.UpdateRecruitment(ctx, result)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("recruitment with id %s %w", id, ErrNotFound)
+		}
+
+		if err != nil || !reaggregate {
+			return err
+		}
+
+		candidateIDs, err := u.scorecardRepository.GetCandidateIDListByRecruitmentID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		for _, candidateID := range candidateIDs {
+			if err = u.aggregateCandidateScore(ctx, result, candidateID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteRecruitment removes a recruitment that has no candidate score,
+// scorecard or application yet. Interviewer assignments go with it.
+func (u *recruitmentUsecase) DeleteRecruitment(ctx context.Context, id string) error {
+	err := u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := ensureUnused(ctx, "recruitment", id,
+			dependent{"score", u.candidateScoreRepository.CountCandidateScoreByRecruitmentID},
+			dependent{"scorecard", u.scorecardRepository.CountScorecardByRecruitmentID},
+			dependent{"application", u.applicationRepository.CountApplicationByRecruitmentID},
+		)
+		if err != nil {
+			return err
+		}
+
+		return u.recruitmentRepository.DeleteRecruitment(ctx, id)
+	})
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("recruitment with id %s %w", id, ErrNotFound)
+	}
+
+	return err
+}
+
+// UpdateCandidateScore re-scores a candidate with the current profile of the
+// recruitment job and records that profile version on the score. Scores
+// aggregated from scorecards change with their scorecards only.
+func (u *recruitmentUsecase) UpdateCandidateScore(ctx context.Context, id, candidateID string, payload model.CandidateScoreUpdateRequest) (*model.CandidateScore, error) {
+	profile, err := u.recruitmentScoringProfile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ensureGrades(profile, payload.AttitudeScore, payload.SkillScore); err != nil {
+		return nil, err
+	}
+
+	result := calculateCandidateScore(profile, payload.WillingToRelocate, payload.Experience, payload.AttitudeScore, payload.SkillScore)
+	result.RecruitmentID = id
+	result.CandidateID = candidateID
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := u.candidateScoreRepository.GetCandidateScoreByID(ctx, id, candidateID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("candidate score for candidate %s in recruitment %s %w", candidateID, id, ErrNotFound)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if current.InterviewerCount > 0 {
+			return fmt.Errorf("candidate score for candidate %s in recruitment %s is aggregated from %d scorecard(s): %w", candidateID, id, current.InterviewerCount, ErrConflict)
+		}
+
+		return u.candidateScoreRepository.UpdateCandidateScore(ctx, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return result, nil
 }
+
+func (u *recruitmentUsecase) DeleteCandidateScore(ctx context.Context, id, candidateID string) error {
+	err := u.candidateScoreRepository.DeleteCandidateScore(ctx, id, candidateID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("candidate score for candidate %s in recruitment %s %w", candidateID, id, ErrNotFound)
+	}
+
+	return err
+}
+
+// parseDeadline parses a YYYY-MM-DD date and moves it to the last second of
+// that day.
+func parseDeadline(value string) (time.Time, error) {
+	deadline, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: deadline %q is not a YYYY-MM-DD date", ErrValidation, value)
+	}
+
+	return deadline.Add(time.Hour * 23).Add(time.Minute * 59).Add(time.Second * 59), nil
+}
+
+func (u *recruitmentUsecase) GetScorecards(ctx context.Context, id, candidateID string) (*model.ScorecardSummary, error) {
+	recruitment, err := u.recruitmentRepository.GetRecruitmentByID(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("recruitment with id %s %w", id, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	scorecards, err := u.scorecardRepository.GetScorecardListByCandidate(ctx, id, candidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := currentScoringProfile(ctx, u.scoringProfileRepository, recruitment.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	attitudes, skills := scorecardScores(profile, *scorecards)
+
+	return &model.ScorecardSummary{
+		RecruitmentID:    id,
+		CandidateID:      candidateID,
+		ScoreAggregation: recruitment.ScoreAggregation,
+		AttitudeScore:    aggregate(recruitment.ScoreAggregation, attitudes),
+		SkillScore:       aggregate(recruitment.ScoreAggregation, skills),
+		AttitudeSpread:   spread(attitudes),
+		SkillSpread:      spread(skills),
+		Scorecards:       *scorecards,
+	}, nil
+}
+
+// SubmitScorecard records the grades of one interviewer and refreshes the
+// aggregated candidate score of the recruitment. A candidate with a manual
+// score takes no scorecard, so the aggregate never replaces it.
+func (u *recruitmentUsecase) SubmitScorecard(ctx context.Context, id, candidateID string, payload model.ScorecardCreateRequest) (*model.Scorecard, error) {
+	recruitment, err := u.recruitmentRepository.GetRecruitmentByID(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("recruitment with id %s %w", id, ErrNotFound)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if recruitment.IsClosed(time.Now()) {
+		return nil, fmt.Errorf("recruitment with id %s no longer accepts scores: %w", id, ErrRecruitmentClosed)
+	}
+
+	if candidate, err := u.candidateRepository.GetCandidateByID(ctx, candidateID); err == sql.ErrNoRows {
+		return nil, fmt.Errorf("candidate with id %s %w", candidateID, ErrNotFound)
+	} else if err != nil {
+		return nil, err
+	} else if candidate.Erased() {
+		return nil, fmt.Errorf("candidate with id %s: %w", candidateID, ErrErased)
+	}
+
+	profile, err := currentScoringProfile(ctx, u.scoringProfileRepository, recruitment.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = ensureGrades(profile, payload.AttitudeScore, payload.SkillScore); err != nil {
+		return nil, err
+	}
+
+	result := &model.Scorecard{
+		ID:            uuid.NewString(),
+		RecruitmentID: id,
+		CandidateID:   candidateID,
+		Interviewer:   payload.Interviewer,
+		AttitudeGrade: payload.AttitudeScore,
+		SkillGrade:    payload.SkillScore,
+		AttitudeScore: profile.GradeValue(payload.AttitudeScore),
+		SkillScore:    profile.GradeValue(payload.SkillScore),
+		Comment:       payload.Comment,
+		CreatedAt:     time.Now(),
+	}
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		score, err := u.candidateScoreRepository.GetCandidateScoreByID(ctx, id, candidateID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		if err == nil && score.InterviewerCount == 0 {
+			return fmt.Errorf("candidate with id %s already has a manual score in recruitment %s: %w", candidateID, id, ErrAlreadyExists)
+		}
+
+		if err := u.scorecardRepository.PostScorecard(ctx, result); err != nil {
+			return err
+		}
+
+		return u.aggregateCandidateScore(ctx, recruitment, candidateID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *recruitmentUsecase) DeleteScorecard(ctx context.Context, id, candidateID, scorecardID string) error {
+	recruitment, err := u.recruitmentRepository.GetRecruitmentByID(ctx, id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("recruitment with id %s %w", id, ErrNotFound)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	scorecard, err := u.scorecardRepository.GetScorecardByID(ctx, scorecardID)
+	if err == sql.ErrNoRows || (err == nil && (scorecard.RecruitmentID != id || scorecard.CandidateID != candidateID)) {
+		return fmt.Errorf("scorecard with id %s %w", scorecardID, ErrNotFound)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		scorecards, err := u.scorecardRepository.GetScorecardListByCandidate(ctx, id, candidateID)
+		if err != nil {
+			return err
+		}
+
+		if len(*scorecards) == 1 {
+			total, err := u.candidateScoreRepository.CountCandidateScore(ctx, id, candidateID)
+			if err != nil {
+				return err
+			}
+
+			if total > 0 {
+				return fmt.Errorf("scorecard with id %s is the last one of the score of candidate %s in recruitment %s, delete the score first: %w", scorecardID, candidateID, id, ErrInUse)
+			}
+		}
+
+		if err := u.scorecardRepository.DeleteScorecard(ctx, scorecardID); err != nil {
+			return err
+		}
+
+		return u.aggregateCandidateScore(ctx, recruitment, candidateID)
+	})
+}
+
+// aggregateCandidateScore rebuilds the candidate score of a recruitment from
+// its scorecards. Attitude and skill come from the interviewers, relocation
+// and experience from the candidate record. It leaves manual scores, and
+// candidates without scorecards, alone.
+func (u *recruitmentUsecase) aggregateCandidateScore(ctx context.Context, recruitment *model.Recruitment, candidateID string) error {
+	scorecards, err := u.scorecardRepository.GetScorecardListByCandidate(ctx, recruitment.ID, candidateID)
+	if err != nil {
+		return err
+	}
+
+	if len(*scorecards) == 0 {
+		return nil
+	}
+
+	current, err := u.candidateScoreRepository.GetCandidateScoreByID(ctx, recruitment.ID, candidateID)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if err == nil && current.InterviewerCount == 0 {
+		return nil
+	}
+
+	candidate, err := u.candidateRepository.GetCandidateByID(ctx, candidateID)
+	if err != nil {
+		return err
+	}
+
+	profile, err := currentScoringProfile(ctx, u.scoringProfileRepository, recruitment.JobID)
+	if err != nil {
+		return err
+	}
+
+	attitudes, skills := scorecardScores(profile, *scorecards)
+
+	result := weighCandidateScore(
+		profile,
+		relocationScore(candidate.WillingToRelocate),
+		profile.ExperienceScore(candidate.Experience),
+		aggregate(recruitment.ScoreAggregation, attitudes),
+		aggregate(recruitment.ScoreAggregation, skills),
+	)
+	result.RecruitmentID = recruitment.ID
+	result.CandidateID = candidateID
+	result.InterviewerCount = len(*scorecards)
+	result.AttitudeSpread = spread(attitudes)
+	result.SkillSpread = spread(skills)
+
+	err = u.candidateScoreRepository.UpdateCandidateScore(ctx, result)
+	if err == sql.ErrNoRows {
+		result.ID = uuid.NewString()
+		return u.candidateScoreRepository.PostCandidateScore(ctx, result)
+	}
+
+	return err
+}
+
+// scorecardScores converts the grades of every scorecard with the grade scale
+// of profile, so a changed scale applies to grades given before the change.
+func scorecardScores(profile *model.ScoringProfile, scorecards []model.Scorecard) (attitudes, skills []float64) {
+	for _, scorecard := range scorecards {
+		attitudes = append(attitudes, profile.GradeValue(scorecard.AttitudeGrade))
+		skills = append(skills, profile.GradeValue(scorecard.SkillGrade))
+	}
+
+	return attitudes, skills
+}
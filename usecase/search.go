@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"strings"
+	"talentapp/model"
+	"talentapp/repository"
+	"unicode/utf8"
+)
+
+const (
+	// snippetLength is the most runes of a field shown in a highlight.
+	snippetLength = 160
+	// snippetLead is the number of runes kept before the first match when
+	// the text goes on after the snippet.
+	snippetLead = 40
+)
+
+type SearchUsecase interface {
+	Search(ctx context.Context, payload model.SearchRequest) (*model.SearchResult, error)
+}
+
+type searchUsecase struct {
+	candidateRepository repository.CandidateRepository
+	jobRepository       repository.JobRepository
+}
+
+func NewSearchUsecase(
+	candidateRepository repository.CandidateRepository,
+	jobRepository repository.JobRepository,
+) SearchUsecase {
+	return &searchUsecase{
+		candidateRepository,
+		jobRepository,
+	}
+}
+
+// Search returns the most relevant candidates and jobs for a query.
+func (u *searchUsecase) Search(ctx context.Context, payload model.SearchRequest) (*model.SearchResult, error) {
+	payload.Normalize()
+	page := model.Pagination{Page: 1, Size: payload.Size}
+
+	candidates, candidatesTotal, err := u.candidateRepository.GetCandidateList(ctx, model.CandidateListRequest{
+		Pagination: page,
+		Query:      payload.Query,
+		AssignedTo: payload.AssignedTo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.candidateRepository.LoadSkills(ctx, candidatePointers(*candidates)...); err != nil {
+		return nil, err
+	}
+
+	jobs, jobsTotal, err := u.jobRepository.GetJobList(ctx, model.JobListRequest{
+		Pagination: page,
+		Query:      payload.Query,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	highlightCandidates(payload.Query, *candidates)
+	highlightJobs(payload.Query, *jobs)
+
+	return &model.SearchResult{
+		Query:           payload.Query,
+		Candidates:      *candidates,
+		CandidatesTotal: candidatesTotal,
+		Jobs:            *jobs,
+		JobsTotal:       jobsTotal,
+	}, nil
+}
+
+func candidatePointers(candidates []model.Candidate) []*model.Candidate {
+	result := make([]*model.Candidate, len(candidates))
+	for i := range candidates {
+		result[i] = &candidates[i]
+	}
+
+	return result
+}
+
+// highlightCandidates sets the Highlights of candidates found by query.
+func highlightCandidates(query string, candidates []model.Candidate) {
+	var (
+		pattern = searchPattern(query)
+		skills  = map[string]bool{}
+	)
+
+	for _, name := range model.SearchSkills(query) {
+		skills[name] = true
+	}
+
+	if pattern == nil && len(skills) == 0 {
+		return
+	}
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		candidate.Highlights = highlights(pattern, [][2]string{
+			{"name", candidate.Name},
+			{"address", candidate.Address},
+		})
+
+		if snippet, ok := highlightSkills(pattern, skills, candidate.Skills); ok {
+			candidate.Highlights = append(candidate.Highlights, model.SearchHighlight{Field: "skills", Snippet: snippet})
+		}
+	}
+}
+
+// highlightJobs sets the Highlights of jobs found by query.
+func highlightJobs(query string, jobs []model.Job) {
+	pattern := searchPattern(query)
+	if pattern == nil {
+		return
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		job.Highlights = highlights(pattern, [][2]string{
+			{"position", job.Position},
+			{"department", job.Department},
+			{"job_description", job.JobDescription},
+			{"criteria", job.Criteria},
+		})
+	}
+}
+
+// searchPattern matches the words starting with a term of query, like the
+// full-text search does, capturing the word. It is nil for a query without
+// any term long enough to match words.
+func searchPattern(query string) *regexp.Regexp {
+	terms := model.PrefixTerms(model.SearchTerms(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	for i, term := range terms {
+		terms[i] = regexp.QuoteMeta(term)
+	}
+
+	return regexp.MustCompile(`(?i)(?:^|[^\pL\pN])((?:` + strings.Join(terms, "|") + `)[\pL\pN]*)`)
+}
+
+// highlightSkills lists skills, marking the ones named in names as a whole
+// and the words matching pattern in the others.
+func highlightSkills(pattern *regexp.Regexp, names map[string]bool, skills []string) (string, bool) {
+	var (
+		parts   = make([]string, len(skills))
+		matched bool
+	)
+
+	for i, skill := range skills {
+		if names[strings.ToLower(skill)] {
+			parts[i] = "<mark>" + html.EscapeString(skill) + "</mark>"
+			matched = true
+		} else if snippet, ok := highlight(pattern, skill); ok {
+			parts[i] = snippet
+			matched = true
+		} else {
+			parts[i] = html.EscapeString(skill)
+		}
+	}
+
+	return strings.Join(parts, ", "), matched
+}
+
+// highlights returns a highlight for each named field matching pattern.
+func highlights(pattern *regexp.Regexp, fields [][2]string) []model.SearchHighlight {
+	var result []model.SearchHighlight
+	for _, field := range fields {
+		if snippet, ok := highlight(pattern, field[1]); ok {
+			result = append(result, model.SearchHighlight{Field: field[0], Snippet: snippet})
+		}
+	}
+
+	return result
+}
+
+// highlight escapes the passage of text around its first match of pattern
+// and marks the matching words in it. A nil pattern matches nothing. Long texts are cut to snippetLength
+// runes at word boundaries, with an ellipsis where text was left out.
+func highlight(pattern *regexp.Regexp, text string) (string, bool) {
+	if pattern == nil {
+		return "", false
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	matches := pattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	start, end := 0, len(text)
+	if utf8.RuneCountInString(text) > snippetLength {
+		// a match near the end leaves room for more text before it
+		lead := snippetLead
+		if tail := utf8.RuneCountInString(text[matches[0][2]:]); tail < snippetLength-lead {
+			lead = snippetLength - tail
+		}
+
+		start = snippetStart(text, matches[0][2], lead)
+		end = snippetEnd(text, start, matches[0][3])
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("… ")
+	}
+
+	at := start
+	for _, match := range matches {
+		from, to := match[2], match[3]
+		if to > end {
+			break
+		}
+
+		b.WriteString(html.EscapeString(text[at:from]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[from:to]))
+		b.WriteString("</mark>")
+		at = to
+	}
+
+	b.WriteString(html.EscapeString(text[at:end]))
+	if end < len(text) {
+		b.WriteString(" …")
+	}
+
+	return b.String(), true
+}
+
+// snippetStart goes back lead runes from the match at offset and on to the
+// start of the next word.
+func snippetStart(text string, offset, lead int) int {
+	start := offset
+	for i := 0; i < lead && start > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(text[:start])
+		start -= size
+	}
+
+	if start == 0 {
+		return 0
+	}
+
+	if space := strings.IndexByte(text[start:offset], ' '); space >= 0 {
+		return start + space + 1
+	}
+
+	return offset
+}
+
+// snippetEnd goes snippetLength runes past start and back to the end of a
+// word, never cutting before the first match, which ends at matchEnd.
+func snippetEnd(text string, start, matchEnd int) int {
+	end := start
+	for i := 0; i < snippetLength && end < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+
+	if end == len(text) || matchEnd >= end {
+		return end
+	}
+
+	if space := strings.LastIndexByte(text[matchEnd:end], ' '); space >= 0 {
+		return matchEnd + space
+	}
+
+	return end
+}
@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"talentapp/model"
+	"talentapp/repository"
+	"time"
+)
+
+// SessionTTL is how long a web login lasts.
+const SessionTTL = 12 * time.Hour
+
+type UserUsecase interface {
+	CreateUser(ctx context.Context, payload model.UserCreateRequest) (*model.User, error)
+	GetUsers(ctx context.Context) (*[]model.User, error)
+	DeleteUser(ctx context.Context, id string) error
+	EnsureAdmin(ctx context.Context, payload model.UserCreateRequest) error
+	Login(ctx context.Context, payload model.LoginRequest) (string, *model.User, error)
+	Logout(ctx context.Context, token string) error
+	Authenticate(ctx context.Context, kind, token string) (*model.User, error)
+	CreateAPIToken(ctx context.Context, payload model.TokenCreateRequest) (string, *model.UserToken, error)
+	GetAPITokens(ctx context.Context, userID string) (*[]model.UserToken, error)
+	DeleteAPIToken(ctx context.Context, userID, id string) error
+	AssignInterviewer(ctx context.Context, recruitmentID string, payload model.InterviewerAssignmentCreateRequest) (*model.InterviewerAssignment, error)
+	GetInterviewerAssignments(ctx context.Context, recruitmentID string) (*[]model.InterviewerAssignment, error)
+	UnassignInterviewer(ctx context.Context, recruitmentID, id string) error
+	IsAssigned(ctx context.Context, recruitmentID, candidateID, userID string) (bool, error)
+	IsAssignedToCandidate(ctx context.Context, candidateID, userID string) (bool, error)
+	IsAssignedToApplication(ctx context.Context, applicationID, userID string) (bool, error)
+}
+
+type userUsecase struct {
+	userRepository                  repository.UserRepository
+	interviewerAssignmentRepository repository.InterviewerAssignmentRepository
+	recruitmentRepository           repository.RecruitmentRepository
+	candidateRepository             repository.CandidateRepository
+}
+
+func NewUserUsecase(
+	userRepository repository.UserRepository,
+	interviewerAssignmentRepository repository.InterviewerAssignmentRepository,
+	recruitmentRepository repository.RecruitmentRepository,
+	candidateRepository repository.CandidateRepository,
+) UserUsecase {
+	return &userUsecase{
+		userRepository:                  userRepository,
+		interviewerAssignmentRepository: interviewerAssignmentRepository,
+		recruitmentRepository:           recruitmentRepository,
+		candidateRepository:             candidateRepository,
+	}
+}
+
+func (u *userUsecase) CreateUser(ctx context.Context, payload model.UserCreateRequest) (*model.User, error) {
+	if _, err := u.userRepository.GetUserByEmail(ctx, payload.Email); err == nil {
+		return nil, fmt.Errorf("user with email %s: %w", payload.Email, ErrAlreadyExists)
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(payload.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.User{
+		ID:           uuid.NewString(),
+		Name:         payload.Name,
+		Email:        payload.Email,
+		PasswordHash: string(hash),
+		Role:         payload.Role,
+		CreatedAt:    time.Now(),
+	}
+
+	err = u.userRepository.PostUser(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *userUsecase) GetUsers(ctx context.Context) (*[]model.User, error) {
+	return u.userRepository.GetUserList(ctx)
+}
+
+func (u *userUsecase) DeleteUser(ctx context.Context, id string) error {
+	err := u.userRepository.DeleteUser(ctx, id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("user with id %s %w", id, ErrNotFound)
+	}
+
+	return err
+}
+
+// EnsureAdmin creates the given admin when there is no user yet, so a fresh
+// installation can be logged into.
+func (u *userUsecase) EnsureAdmin(ctx context.Context, payload model.UserCreateRequest) error {
+	total, err := u.userRepository.CountUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	if total > 0 {
+		return nil
+	}
+
+	payload.Role = model.RoleAdmin
+	_, err = u.CreateUser(ctx, payload)
+
+	return err
+}
+
+// Login checks the credentials and starts a web session. The returned token
+// is only known to the caller; the database keeps its hash.
+func (u *userUsecase) Login(ctx context.Context, payload model.LoginRequest) (string, *model.User, error) {
+	user, err := u.checkPassword(ctx, payload.Email, payload.Password)
+	if err != nil {
+		return "", nil, err
+	}
+
+	expiresAt := time.Now().Add(SessionTTL)
+	token, _, err := u.issueToken(ctx, user, model.TokenSession, "web", &expiresAt)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, user, nil
+}
+
+func (u *userUsecase) Logout(ctx context.Context, token string) error {
+	user, session, err := u.userRepository.GetUserByTokenHash(ctx, model.TokenSession, hashToken(token), time.Now())
+	if err == sql.ErrNoRows {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return u.userRepository.DeleteUserToken(ctx, user.ID, session.ID)
+}
+
+// Authenticate returns the owner of a session or API token.
+func (u *userUsecase) Authenticate(ctx context.Context, kind, token string) (*model.User, error) {
+	now := time.Now()
+
+	user, result, err := u.userRepository.GetUserByTokenHash(ctx, kind, hashToken(token), now)
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = u.userRepository.TouchUserToken(ctx, result.ID, now); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// CreateAPIToken issues an API key for the JSON API. The key does not
+// expire until it is deleted.
+func (u *userUsecase) CreateAPIToken(ctx context.Context, payload model.TokenCreateRequest) (string, *model.UserToken, error) {
+	user, err := u.checkPassword(ctx, payload.Email, payload.Password)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return u.issueToken(ctx, user, model.TokenAPI, payload.Name, nil)
+}
+
+func (u *userUsecase) GetAPITokens(ctx context.Context, userID string) (*[]model.UserToken, error) {
+	return u.userRepository.GetUserTokenListByUserID(ctx, userID)
+}
+
+func (u *userUsecase) DeleteAPIToken(ctx context.Context, userID, id string) error {
+	err := u.userRepository.DeleteUserToken(ctx, userID, id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("token with id %s %w", id, ErrNotFound)
+	}
+
+	return err
+}
+
+func (u *userUsecase) AssignInterviewer(ctx context.Context, recruitmentID string, payload model.InterviewerAssignmentCreateRequest) (*model.InterviewerAssignment, error) {
+	if _, err := u.recruitmentRepository.GetRecruitmentByID(ctx, recruitmentID); err == sql.ErrNoRows {
+		return nil, fmt.Errorf("recruitment with id %s %w", recruitmentID, ErrNotFound)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if candidate, err := u.candidateRepository.GetCandidateByID(ctx, payload.CandidateID); err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: candidate with id %s does not exist", ErrValidation, payload.CandidateID)
+	} else if err != nil {
+		return nil, err
+	} else if candidate.Erased() {
+		return nil, fmt.Errorf("candidate with id %s: %w", payload.CandidateID, ErrErased)
+	}
+
+	if _, err := u.userRepository.GetUserByID(ctx, payload.UserID); err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: user with id %s does not exist", ErrValidation, payload.UserID)
+	} else if err != nil {
+		return nil, err
+	}
+
+	assigned, err := u.IsAssigned(ctx, recruitmentID, payload.CandidateID, payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assigned {
+		return nil, fmt.Errorf("user with id %s is already assigned to candidate %s: %w", payload.UserID, payload.CandidateID, ErrAlreadyExists)
+	}
+
+	result := &model.InterviewerAssignment{
+		ID:            uuid.NewString(),
+		RecruitmentID: recruitmentID,
+		CandidateID:   payload.CandidateID,
+		UserID:        payload.UserID,
+		CreatedAt:     time.Now(),
+	}
+
+	err = u.interviewerAssignmentRepository.PostInterviewerAssignment(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *userUsecase) GetInterviewerAssignments(ctx context.Context, recruitmentID string) (*[]model.InterviewerAssignment, error) {
+	return u.interviewerAssignmentRepository.GetInterviewerAssignmentListByRecruitmentID(ctx, recruitmentID)
+}
+
+func (u *userUsecase) UnassignInterviewer(ctx context.Context, recruitmentID, id string) error {
+	err := u.interviewerAssignmentRepository.DeleteInterviewerAssignment(ctx, recruitmentID, id)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("assignment with id %s %w", id, ErrNotFound)
+	}
+
+	return err
+}
+
+func (u *userUsecase) IsAssigned(ctx context.Context, recruitmentID, candidateID, userID string) (bool, error) {
+	total, err := u.interviewerAssignmentRepository.CountInterviewerAssignment(ctx, recruitmentID, candidateID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return total > 0, nil
+}
+
+// IsAssignedToCandidate reports whether the user is assigned to the
+// candidate in any recruitment.
+func (u *userUsecase) IsAssignedToCandidate(ctx context.Context, candidateID, userID string) (bool, error) {
+	total, err := u.interviewerAssignmentRepository.CountInterviewerAssignmentByCandidateID(ctx, candidateID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return total > 0, nil
+}
+
+// IsAssignedToApplication reports whether the user is assigned to the
+// candidate of the application in its recruitment.
+func (u *userUsecase) IsAssignedToApplication(ctx context.Context, applicationID, userID string) (bool, error) {
+	total, err := u.interviewerAssignmentRepository.CountInterviewerAssignmentByApplicationID(ctx, applicationID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return total > 0, nil
+}
+
+func (u *userUsecase) checkPassword(ctx context.Context, email, password string) (*model.User, error) {
+	user, err := u.userRepository.GetUserByEmail(ctx, email)
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+func (u *userUsecase) issueToken(ctx context.Context, user *model.User, kind, name string, expiresAt *time.Time) (string, *model.UserToken, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	result := &model.UserToken{
+		ID:        uuid.NewString(),
+		UserID:    user.ID,
+		Kind:      kind,
+		Name:      name,
+		TokenHash: hashToken(token),
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	err := u.userRepository.PostUserToken(ctx, result)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return token, result, nil
+}
+
+// hashToken returns the hex encoded SHA-256 of a token, which is what the
+// database stores.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"bufio"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"log"
+)
+
+// SendAttachment streams what write produces as a file download named
+// filename. write runs after the handler returned, so it must not use ctx or
+// strings taken from the request, and its error can only be logged.
+func SendAttachment(ctx *fiber.Ctx, filename, contentType string, write func(w io.Writer) error) error {
+	ctx.Attachment(filename)
+	ctx.Set(fiber.HeaderContentType, contentType)
+
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := write(w); err != nil {
+			log.Printf("writing %s: %v", filename, err)
+			return
+		}
+
+		if err := w.Flush(); err != nil {
+			log.Printf("writing %s: %v", filename, err)
+		}
+	})
+
+	return nil
+}
+
+// SendFile streams size bytes of content as a file download named filename
+// and closes content once they are sent. The browser is told not to guess
+// another type, since the file was uploaded by a user.
+func SendFile(ctx *fiber.Ctx, filename, contentType string, size int64, content io.ReadCloser) error {
+	ctx.Attachment(filename)
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+
+	return ctx.SendStream(content, int(size))
+}
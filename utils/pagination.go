@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"strconv"
+	"talentapp/model"
+)
+
+// SetPageLinks fills the next and previous links of meta from the current
+// request, keeping every other query parameter (filters, sort and size).
+func SetPageLinks(ctx *fiber.Ctx, meta *model.PageMeta) {
+	if meta == nil {
+		return
+	}
+
+	if meta.HasNext() {
+		meta.Next = pageURL(ctx, meta.Page+1)
+	}
+
+	if meta.HasPrev() {
+		meta.Prev = pageURL(ctx, meta.Page-1)
+	}
+}
+
+func pageURL(ctx *fiber.Ctx, page int) string {
+	args := fiber.AcquireArgs()
+	defer fiber.ReleaseArgs(args)
+
+	ctx.Request().URI().QueryArgs().CopyTo(args)
+	args.Set("page", strconv.Itoa(page))
+
+	return ctx.Path() + "?" + args.String()
+}
+
+// ExportURL links path to the export of the current list in format, keeping
+// its filters and sort but not its page.
+func ExportURL(ctx *fiber.Ctx, path, format string) string {
+	args := fiber.AcquireArgs()
+	defer fiber.ReleaseArgs(args)
+
+	ctx.Request().URI().QueryArgs().CopyTo(args)
+	args.Del("page")
+	args.Del("size")
+
+	if args.Len() == 0 {
+		return path + "/" + format
+	}
+
+	return path + "/" + format + "?" + args.String()
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"mime/multipart"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsupportedSpreadsheet is returned for files that are neither CSV nor XLSX.
+var ErrUnsupportedSpreadsheet = errors.New("unsupported file type, expected .csv or .xlsx")
+
+// ReadSpreadsheet returns the rows of a CSV file or of the first sheet of an
+// XLSX file, picking the format from the file name. Rows may have different
+// lengths.
+func ReadSpreadsheet(filename string, r io.Reader) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return readCSV(r)
+	case ".xlsx":
+		return readXLSX(r)
+	}
+
+	return nil, ErrUnsupportedSpreadsheet
+}
+
+// ReadSpreadsheetFile reads an uploaded CSV or XLSX file with ReadSpreadsheet.
+func ReadSpreadsheetFile(header *multipart.FileHeader) ([][]string, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadSpreadsheet(header.Filename, file)
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// spreadsheet programs often save CSV with a UTF-8 byte order mark
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+	}
+
+	return rows, nil
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("workbook has no sheets")
+	}
+
+	return file.GetRows(sheets[0])
+}
@@ -44,6 +44,20 @@ func getErrorMsg(fe validator.FieldError) string {
 		return "This field is numeric type"
 	case "number":
 		return "This field is number type"
+	case "min":
+		return "Should be at least " + fe.Param()
+	case "max":
+		return "Should be at most " + fe.Param()
+	case "len":
+		return "Should have length " + fe.Param()
+	case "alpha":
+		return "Should be alphabetic"
+	case "oneof":
+		return "Should be one of " + fe.Param()
+	case "datetime":
+		return "Should be date with format " + fe.Param()
+	case "email":
+		return "Should be email format"
 	case "url":
 		return "Should be url format"
 	case "base64":